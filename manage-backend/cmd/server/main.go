@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	_ "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/docs" // 导入生成的 docs
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/handler"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/middleware"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/repository"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
 	casbinpkg "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/casbin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/database"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
@@ -49,6 +65,17 @@ func main() {
 	// 记录配置详情
 	config.LogConfigDetails(cfg)
 
+	// 校验安全策略，避免错误的锁定配置在运行时才暴露
+	if err := cfg.Security.Validate(); err != nil {
+		logger.Fatal("安全策略配置无效", zap.Error(err))
+	}
+
+	// 初始化链路追踪
+	shutdownTracing, err := tracing.Init(cfg.Tracing, cfg.Environment)
+	if err != nil {
+		logger.Fatal("链路追踪初始化失败", zap.Error(err))
+	}
+
 	// 初始化数据库
 	db, err := database.Init(cfg.Database)
 	if err != nil {
@@ -74,6 +101,12 @@ func main() {
 	// 如需种子数据，可以手动调用: database.SeedDatabase(db, cfg.Environment)
 	logger.Info("✅ 数据库连接成功")
 
+	if cfg.Tracing.Enabled {
+		if err := db.Use(tracing.GormPlugin{}); err != nil {
+			logger.Fatal("注册 SQL 链路追踪失败", zap.Error(err))
+		}
+	}
+
 	// 初始化 Casbin Enforcer
 	modelPath := "pkg/casbin/model.conf"
 	enforcer, err := casbinpkg.NewEnforcer(db, modelPath)
@@ -81,9 +114,24 @@ func main() {
 		logger.Fatal("Casbin 初始化失败", zap.Error(err))
 	}
 	logger.Info("✅ Casbin 权限引擎初始化成功")
-	
-	// TODO: enforcer 将在后续的权限中间件中使用
-	_ = enforcer
+
+	// 加载 JWT 签名密钥
+	keySet, err := auth.LoadKeySet(cfg.JWT)
+	if err != nil {
+		logger.Fatal("JWT 密钥加载失败", zap.Error(err))
+	}
+	logger.Info("✅ JWT 密钥加载成功",
+		zap.String("algorithm", keySet.Algorithm()),
+		zap.String("kid", keySet.ActiveKeyID()))
+
+	// 初始化 Redis 客户端
+	redisClient := cache.NewRedisClient(cfg.Redis)
+	if cfg.Tracing.Enabled {
+		redisClient.GetClient().AddHook(tracing.RedisHook{})
+		logger.Info("✅ 链路追踪已启用",
+			zap.String("exporter", cfg.Tracing.Exporter),
+			zap.Float64("sample_ratio", cfg.Tracing.SampleRatio))
+	}
 
 	// 根据环境初始化 Gin 路由器设置
 	switch cfg.Environment {
@@ -99,25 +147,167 @@ func main() {
 	}
 
 	router := gin.New()
-	router.Use(gin.Logger())
+	if cfg.Tracing.Enabled {
+		// 服务端 span 需要最先创建，之后的中间件日志才能带上 trace_id
+		// 探针和指标接口调用频繁，不记录链路
+		skipProbes := otelgin.WithFilter(func(r *http.Request) bool {
+			return !strings.HasPrefix(r.URL.Path, "/health") && r.URL.Path != "/ready" && r.URL.Path != cfg.Metrics.Path
+		})
+		router.Use(otelgin.Middleware(cfg.Tracing.ServiceName, skipProbes))
+	}
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog())
 	router.Use(gin.Recovery())
+	router.Use(middleware.Metrics())
 	router.Use(middleware.CORS())
 
+	// 审计日志批量写入器
+	auditWriterConfig := middleware.DefaultAuditWriterConfig()
+	if cfg.Audit.QueueSize > 0 {
+		auditWriterConfig.QueueSize = cfg.Audit.QueueSize
+	}
+	if cfg.Audit.BatchSize > 0 {
+		auditWriterConfig.BatchSize = cfg.Audit.BatchSize
+	}
+	if cfg.Audit.FlushIntervalMs > 0 {
+		auditWriterConfig.FlushInterval = time.Duration(cfg.Audit.FlushIntervalMs) * time.Millisecond
+	}
+	if cfg.Audit.OverflowPolicy == middleware.AuditOverflowBlock {
+		auditWriterConfig.OverflowPolicy = middleware.AuditOverflowBlock
+	}
+	auditWriter := middleware.NewAuditWriter(repository.NewAuditLogRepository(db), auditWriterConfig)
+	metrics.RegisterAuditQueue(
+		func() float64 { return float64(auditWriter.Stats().Queued) },
+		func() float64 { return float64(auditWriter.Stats().Written) },
+		func() float64 { return float64(auditWriter.Stats().Dropped) },
+		func() float64 { return float64(auditWriter.Stats().Failed) },
+	)
+
+	// 审计日志定时归档与清理
+	var auditRetentionJob *service.AuditRetentionJob
+	if cfg.Audit.Retention.Enabled {
+		auditLogService := service.NewAuditLogService(repository.NewAuditLogRepository(db), nil, cfg.AuditCheckpointKey())
+		auditRetentionJob = service.NewAuditRetentionJob(auditLogService, cfg.Audit.Retention)
+		auditRetentionJob.Start()
+	}
+
+	// 安全策略：优先使用管理员在运行时修改并持久化的策略
+	securityPolicyService := service.NewSecurityPolicyService(repository.NewSystemSettingRepository(db), redisClient, cfg.Security)
+	if err := securityPolicyService.Reload(context.Background()); err != nil {
+		logger.Error("加载安全策略失败，使用配置文件中的策略", zap.Error(err))
+	}
+	securityPolicyService.Start()
+
 	// API 路由
 	api := router.Group("/api/v1")
-	handler.SetupRoutes(api, db, enforcer)
+	handler.SetupRoutes(api, db, enforcer, keySet, auditWriter, redisClient, securityPolicyService)
+
+	// JWT 公钥发布
+	router.GET("/.well-known/jwks.json", handler.NewJWKSHandler(keySet).GetJWKS)
+
+	// Prometheus 指标
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
+
+	// 本地存储的上传文件（用户头像等）
+	if cfg.Storage.Type == config.StorageTypeLocal && strings.HasPrefix(cfg.Storage.Local.BaseURL, "/") {
+		router.Static(cfg.Storage.Local.BaseURL, cfg.Storage.Local.Dir)
+	}
 
 	// Swagger 文档
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// 健康检查
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	// 健康检查：存活检查只确认进程可用，就绪检查会检查依赖组件，开始优雅关闭后返回 503
+	healthChecks := []service.HealthCheck{
+		service.DatabaseHealthCheck(db),
+		service.RedisHealthCheck(redisClient),
+		service.CasbinHealthCheck(enforcer),
+	}
+	if cfg.Email.SMTPHost != "" {
+		healthChecks = append(healthChecks, service.SMTPHealthCheck(cfg.Email))
+	}
+	healthHandler := handler.NewHealthHandler(service.NewHealthService(
+		healthChecks,
+		time.Duration(cfg.Health.TimeoutMs)*time.Millisecond,
+		time.Duration(cfg.Health.CacheTTLMs)*time.Millisecond,
+	))
+	router.GET("/health", healthHandler.Live)
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)
+	router.GET("/ready", healthHandler.Ready)
 
 	// 启动服务器
-	logger.Info("服务器正在启动", zap.String("port", cfg.Port))
-	if err := router.Run(":" + cfg.Port); err != nil {
-		logger.Fatal("服务器启动失败", zap.Error(err))
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      router,
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:  time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
+	}
+	go func() {
+		logger.Info("服务器正在启动", zap.String("port", cfg.Port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("服务器启动失败", zap.Error(err))
+		}
+	}()
+
+	// 等待退出信号
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.Info("服务器正在关闭...")
+
+	// 先标记为未就绪，等待负载均衡摘除流量
+	healthHandler.MarkDraining()
+	if cfg.Server.DrainDelaySeconds > 0 {
+		logger.Info("等待负载均衡摘除流量", zap.Int("seconds", cfg.Server.DrainDelaySeconds))
+		time.Sleep(time.Duration(cfg.Server.DrainDelaySeconds) * time.Second)
+	}
+
+	// 停止接收新连接，等待进行中的请求处理完成
+	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeoutSeconds) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = 15 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("服务器关闭超时，仍有未完成的请求", zap.Error(err))
+	}
+
+	// 请求全部结束后再按顺序停止后台组件：审计日志写入器 -> 定时任务 -> 策略订阅 -> Redis -> 数据库
+	// 每个组件单独计时，避免请求处理耗尽时间导致审计日志来不及写入
+	workerCtx, workerCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer workerCancel()
+
+	if err := auditWriter.Close(workerCtx); err != nil {
+		logger.Error("审计日志写入失败", zap.Error(err))
+	}
+
+	if auditRetentionJob != nil {
+		if err := auditRetentionJob.Stop(workerCtx); err != nil {
+			logger.Error("审计日志保留任务停止超时", zap.Error(err))
+		}
 	}
+
+	if err := securityPolicyService.Stop(workerCtx); err != nil {
+		logger.Error("安全策略订阅停止失败", zap.Error(err))
+	}
+
+	if err := redisClient.Close(); err != nil {
+		logger.Error("Redis 连接关闭失败", zap.Error(err))
+	}
+
+	if err := database.Close(db); err != nil {
+		logger.Error("数据库连接关闭失败", zap.Error(err))
+	}
+
+	// 最后导出剩余的 span
+	if err := shutdownTracing(workerCtx); err != nil {
+		logger.Error("链路数据导出失败", zap.Error(err))
+	}
+
+	logger.Info("服务器已关闭")
 }
\ No newline at end of file
@@ -3848,8 +3848,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "password": {
-                    "type": "string",
-                    "minLength": 6
+                    "type": "string"
                 },
                 "role": {
                     "type": "string"
@@ -4162,8 +4161,7 @@ const docTemplate = `{
             ],
             "properties": {
                 "new_password": {
-                    "type": "string",
-                    "minLength": 6
+                    "type": "string"
                 },
                 "token": {
                     "type": "string"
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -10,6 +11,10 @@ import (
 type Config struct {
 	Environment   string                `mapstructure:"environment"`
 	Port          string                `mapstructure:"port"`
+	Server        ServerConfig          `mapstructure:"server"`
+	Health        HealthConfig          `mapstructure:"health"`
+	Metrics       MetricsConfig         `mapstructure:"metrics"`
+	Tracing       TracingConfig         `mapstructure:"tracing"`
 	LogLevel      string                `mapstructure:"log_level"`
 	Log           LogConfig             `mapstructure:"log"`
 	Database      Database              `mapstructure:"database"`
@@ -18,6 +23,12 @@ type Config struct {
 	Captcha       CaptchaConfig         `mapstructure:"captcha"`
 	Email         EmailConfig           `mapstructure:"email"`
 	PasswordReset PasswordResetConfig   `mapstructure:"password_reset"`
+	Authz         AuthzConfig           `mapstructure:"authz"`
+	TwoFactor     TwoFactorConfig       `mapstructure:"two_factor"`
+	Audit         AuditConfig           `mapstructure:"audit"`
+	RateLimit     RateLimitConfig       `mapstructure:"rate_limit"`
+	Security      SecurityConfig        `mapstructure:"security"`
+	Storage       StorageConfig         `mapstructure:"storage"`
 }
 
 type Database struct {
@@ -37,10 +48,51 @@ type Redis struct {
 }
 
 type JWT struct {
-	Secret               string `mapstructure:"secret"`
-	ExpireTime           int    `mapstructure:"expire_time"`           // Deprecated: use AccessTokenExpire
-	AccessTokenExpire    int    `mapstructure:"access_token_expire"`   // Access token expiration in minutes
-	RefreshTokenExpire   int    `mapstructure:"refresh_token_expire"`  // Refresh token expiration in hours
+	Secret               string   `mapstructure:"secret"`
+	ExpireTime           int      `mapstructure:"expire_time"`          // Deprecated: use AccessTokenExpire
+	AccessTokenExpire    int      `mapstructure:"access_token_expire"`  // Access token expiration in minutes
+	RefreshTokenExpire   int      `mapstructure:"refresh_token_expire"` // Refresh token expiration in hours
+	ActiveKeyID          string   `mapstructure:"active_key_id"`        // 当前签名密钥的 kid，为空时使用 HS256 + secret
+	Keys                 []JWTKey `mapstructure:"keys"`                 // 非对称签名密钥（含已退役的验签密钥）
+	AcceptLegacyHS256    bool     `mapstructure:"accept_legacy_hs256"`  // 切换到非对称签名后是否继续接受旧的 HS256 令牌
+}
+
+// JWTKey JWT 签名密钥配置
+type JWTKey struct {
+	ID             string `mapstructure:"id"`               // 密钥ID（kid）
+	Algorithm      string `mapstructure:"algorithm"`        // 签名算法: RS256, EdDSA
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM 私钥文件路径（活跃密钥必填）
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM 公钥文件路径（已退役密钥只需公钥）
+}
+
+// ServerConfig HTTP 服务器配置
+type ServerConfig struct {
+	ReadTimeoutSeconds     int `mapstructure:"read_timeout_seconds"`     // 读取整个请求（含请求体）的超时时间
+	WriteTimeoutSeconds    int `mapstructure:"write_timeout_seconds"`    // 写响应的超时时间（流式导出接口会单独放宽）
+	IdleTimeoutSeconds     int `mapstructure:"idle_timeout_seconds"`     // keep-alive 连接的空闲超时时间
+	ShutdownTimeoutSeconds int `mapstructure:"shutdown_timeout_seconds"` // 优雅关闭时等待请求处理完成的最长时间
+	DrainDelaySeconds      int `mapstructure:"drain_delay_seconds"`      // 收到退出信号后 /ready 返回未就绪，等待该时间再停止接收新连接
+}
+
+// HealthConfig 就绪检查配置
+type HealthConfig struct {
+	TimeoutMs  int `mapstructure:"timeout_ms"`   // 单次检查的超时时间（毫秒）
+	CacheTTLMs int `mapstructure:"cache_ttl_ms"` // 检查结果缓存时间（毫秒），探针频繁调用时避免压到依赖组件
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否暴露指标接口
+	Path    string `mapstructure:"path"`    // 指标接口路径
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`      // 是否启用链路追踪
+	Exporter    string  `mapstructure:"exporter"`     // 导出方式: otlp（OTLP/HTTP）, stdout（打印到控制台，本地开发使用）
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP 地址，如 http://localhost:4318，为空时使用 OTEL_EXPORTER_OTLP_* 环境变量
+	ServiceName string  `mapstructure:"service_name"` // 服务名称
+	SampleRatio float64 `mapstructure:"sample_ratio"` // 采样比例（0-1），上游请求已携带采样决定时沿用上游
 }
 
 // LogConfig 日志配置
@@ -70,6 +122,166 @@ type PasswordResetConfig struct {
 	FrontendURL        string `mapstructure:"frontend_url"`
 }
 
+// AuthzConfig 接口鉴权配置
+type AuthzConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`           // 是否启用 Casbin 接口鉴权
+	SuperAdminRoles []string `mapstructure:"super_admin_roles"` // 超级管理员角色代码（跳过鉴权）
+	AllowList       []string `mapstructure:"allow_list"`        // 登录即可访问的路径（相对 API 前缀，支持 :id 和 * 通配）
+	PolicyPrefixes  []string `mapstructure:"policy_prefixes"`   // 策略路径可能使用的前缀，如 /api/v1、/api
+}
+
+// TwoFactorConfig 两步验证配置
+type TwoFactorConfig struct {
+	Issuer                 string `mapstructure:"issuer"`                   // 身份验证器中显示的签发方名称
+	ChallengeExpireMinutes int    `mapstructure:"challenge_expire_minutes"` // 登录挑战有效期（分钟）
+}
+
+// 文件存储类型
+const (
+	StorageTypeLocal = "local" // 本地磁盘
+)
+
+// StorageConfig 文件存储配置（用户头像等上传文件）
+type StorageConfig struct {
+	Type  string             `mapstructure:"type"`  // 存储类型: local（本地磁盘）
+	Local LocalStorageConfig `mapstructure:"local"` // 本地磁盘存储配置
+}
+
+// LocalStorageConfig 本地磁盘存储配置
+type LocalStorageConfig struct {
+	Dir     string `mapstructure:"dir"`      // 文件保存目录
+	BaseURL string `mapstructure:"base_url"` // 文件访问路径前缀，服务会在该路径下提供静态文件访问
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	QueueSize        int                  `mapstructure:"queue_size"`        // 写入队列容量
+	BatchSize        int                  `mapstructure:"batch_size"`        // 单次批量写入的最大条数
+	FlushIntervalMs  int                  `mapstructure:"flush_interval_ms"` // 未攒满一批时的最长等待时间（毫秒）
+	OverflowPolicy   string               `mapstructure:"overflow_policy"`   // 队列已满时的处理策略: drop（丢弃）, block（阻塞请求）
+	CheckpointSecret string               `mapstructure:"checkpoint_secret"` // 清理检查点的 HMAC 签名密钥，为空时使用 jwt.secret
+	Retention        AuditRetentionConfig `mapstructure:"retention"`         // 定时归档与清理
+}
+
+// AuditRetentionConfig 审计日志保留策略配置
+type AuditRetentionConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`        // 是否启用定时归档与清理
+	Days          int    `mapstructure:"days"`           // 保留天数，更早的日志会被归档后删除
+	IntervalHours int    `mapstructure:"interval_hours"` // 执行间隔（小时）
+	ArchiveDir    string `mapstructure:"archive_dir"`    // 归档目录（gzip 压缩的 NDJSON），为空时只删除不归档
+	BatchSize     int    `mapstructure:"batch_size"`     // 每批归档并删除的条数
+}
+
+// RateLimitConfig 接口限流配置
+type RateLimitConfig struct {
+	Enabled bool                     `mapstructure:"enabled"`  // 是否启用接口限流
+	Rules   map[string]RateLimitRule `mapstructure:"rules"`    // 路由分组 -> 限流规则，未配置规则的分组不限流
+	APIKeys []string                 `mapstructure:"api_keys"` // 已签发 API Key 的 SHA-256 摘要（十六进制），按 api_key 限流时只认可这些 Key
+}
+
+// RateLimitRule 限流规则（滑动窗口）
+type RateLimitRule struct {
+	Limit         int    `mapstructure:"limit"`          // 窗口内允许的最大请求数
+	WindowSeconds int    `mapstructure:"window_seconds"` // 窗口长度（秒）
+	KeyBy         string `mapstructure:"key_by"`         // 限流维度: ip, user（未登录时按 IP）, api_key（Key 无效时按登录用户，未登录时按 IP）
+}
+
+// 账户锁定方式
+const (
+	LockoutModeFixed       = "fixed"       // 每次锁定固定时长
+	LockoutModeExponential = "exponential" // 每次锁定时长翻倍，直到上限
+)
+
+// MaxPasswordHistoryCount 密码历史的最大保留条数
+const MaxPasswordHistoryCount = 24
+
+// SecurityConfig 登录锁定、密码重置限流与密码强度策略
+// 配置文件中的值为默认策略，管理员可通过接口在运行时修改（持久化到数据库并广播到所有实例）
+type SecurityConfig struct {
+	Login         LoginSecurityConfig         `mapstructure:"login" json:"login"`
+	PasswordReset PasswordResetSecurityConfig `mapstructure:"password_reset" json:"password_reset"`
+	Password      PasswordSecurityConfig      `mapstructure:"password" json:"password"`
+}
+
+// LoginSecurityConfig 登录限流与账户锁定策略
+type LoginSecurityConfig struct {
+	MaxAttemptsPerIP       int    `mapstructure:"max_attempts_per_ip" json:"max_attempts_per_ip"`             // 每个 IP 在窗口内最多尝试登录的次数
+	IPWindowMinutes        int    `mapstructure:"ip_window_minutes" json:"ip_window_minutes"`                 // IP 限流窗口（分钟）
+	MaxFailsPerAccount     int    `mapstructure:"max_fails_per_account" json:"max_fails_per_account"`         // 账户连续失败多少次后锁定
+	FailWindowMinutes      int    `mapstructure:"fail_window_minutes" json:"fail_window_minutes"`             // 失败次数统计窗口（分钟）
+	LockoutMode            string `mapstructure:"lockout_mode" json:"lockout_mode"`                           // 锁定方式: fixed, exponential
+	LockDurationMinutes    int    `mapstructure:"lock_duration_minutes" json:"lock_duration_minutes"`         // 锁定时长（分钟），exponential 模式下为首次锁定时长
+	MaxLockDurationMinutes int    `mapstructure:"max_lock_duration_minutes" json:"max_lock_duration_minutes"` // exponential 模式下单次锁定的最长时长（分钟）
+	LockoutResetHours      int    `mapstructure:"lockout_reset_hours" json:"lockout_reset_hours"`             // exponential 模式下锁定次数的保留时间（小时），期间无新的锁定则重新从首次时长开始
+}
+
+// PasswordResetSecurityConfig 密码重置请求限流策略
+type PasswordResetSecurityConfig struct {
+	MaxRequestsPerIP    int `mapstructure:"max_requests_per_ip" json:"max_requests_per_ip"`       // 每个 IP 在窗口内最多请求的次数
+	MaxRequestsPerEmail int `mapstructure:"max_requests_per_email" json:"max_requests_per_email"` // 每个邮箱在窗口内最多请求的次数
+	WindowMinutes       int `mapstructure:"window_minutes" json:"window_minutes"`                 // 限流窗口（分钟）
+}
+
+// PasswordSecurityConfig 密码强度与历史策略，适用于注册、创建用户、修改密码和重置密码
+type PasswordSecurityConfig struct {
+	MinLength        int      `mapstructure:"min_length" json:"min_length"`                 // 最小长度
+	RequireUppercase bool     `mapstructure:"require_uppercase" json:"require_uppercase"`   // 必须包含大写字母
+	RequireLowercase bool     `mapstructure:"require_lowercase" json:"require_lowercase"`   // 必须包含小写字母
+	RequireDigit     bool     `mapstructure:"require_digit" json:"require_digit"`           // 必须包含数字
+	RequireSymbol    bool     `mapstructure:"require_symbol" json:"require_symbol"`         // 必须包含特殊字符
+	BannedPasswords  []string `mapstructure:"banned_passwords" json:"banned_passwords"`     // 禁止使用的常见密码（不区分大小写）
+	DisallowUserInfo bool     `mapstructure:"disallow_user_info" json:"disallow_user_info"` // 禁止包含用户名或邮箱前缀
+	HistoryCount     int      `mapstructure:"history_count" json:"history_count"`           // 禁止重复使用最近几次使用过的密码（含当前密码），0 或 1 表示仅禁止当前密码
+}
+
+// Validate 校验安全策略取值
+func (c SecurityConfig) Validate() error {
+	const maxMinutes = 7 * 24 * 60 // 窗口和锁定时长最长一周
+
+	type rangeCheck struct {
+		name     string
+		value    int
+		min, max int
+	}
+	checks := []rangeCheck{
+		{"login.max_attempts_per_ip", c.Login.MaxAttemptsPerIP, 1, 10000},
+		{"login.ip_window_minutes", c.Login.IPWindowMinutes, 1, maxMinutes},
+		{"login.max_fails_per_account", c.Login.MaxFailsPerAccount, 1, 100},
+		{"login.fail_window_minutes", c.Login.FailWindowMinutes, 1, maxMinutes},
+		{"login.lock_duration_minutes", c.Login.LockDurationMinutes, 1, maxMinutes},
+		{"password_reset.max_requests_per_ip", c.PasswordReset.MaxRequestsPerIP, 1, 1000},
+		{"password_reset.max_requests_per_email", c.PasswordReset.MaxRequestsPerEmail, 1, 1000},
+		{"password_reset.window_minutes", c.PasswordReset.WindowMinutes, 1, maxMinutes},
+		{"password.min_length", c.Password.MinLength, 6, 128},
+		{"password.history_count", c.Password.HistoryCount, 0, MaxPasswordHistoryCount},
+	}
+	if c.Login.LockoutMode == LockoutModeExponential {
+		checks = append(checks,
+			rangeCheck{"login.max_lock_duration_minutes", c.Login.MaxLockDurationMinutes, c.Login.LockDurationMinutes, maxMinutes},
+			rangeCheck{"login.lockout_reset_hours", c.Login.LockoutResetHours, 1, 30 * 24},
+		)
+	}
+
+	var errs []error
+	if c.Login.LockoutMode != LockoutModeFixed && c.Login.LockoutMode != LockoutModeExponential {
+		errs = append(errs, fmt.Errorf("login.lockout_mode 必须为 %s 或 %s", LockoutModeFixed, LockoutModeExponential))
+	}
+	for _, check := range checks {
+		if check.value < check.min || check.value > check.max {
+			errs = append(errs, fmt.Errorf("%s 必须在 %d 到 %d 之间", check.name, check.min, check.max))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AuditCheckpointKey 返回审计日志清理检查点的签名密钥
+func (c *Config) AuditCheckpointKey() []byte {
+	if c.Audit.CheckpointSecret != "" {
+		return []byte(c.Audit.CheckpointSecret)
+	}
+	return []byte(c.JWT.Secret)
+}
+
 func Load() *Config {
 	// 首先启用从环境变量读取配置
 	viper.AutomaticEnv()
@@ -126,6 +338,58 @@ func Load() *Config {
 	viper.BindEnv("jwt.expire_time", "JWT_EXPIRE_TIME")
 	viper.BindEnv("jwt.access_token_expire", "JWT_ACCESS_TOKEN_EXPIRE")
 	viper.BindEnv("jwt.refresh_token_expire", "JWT_REFRESH_TOKEN_EXPIRE")
+	viper.BindEnv("audit.checkpoint_secret", "AUDIT_CHECKPOINT_SECRET")
+	viper.BindEnv("tracing.enabled", "TRACING_ENABLED")
+	viper.BindEnv("tracing.endpoint", "TRACING_ENDPOINT")
+	viper.BindEnv("rate_limit.enabled", "RATE_LIMIT_ENABLED")
+
+	viper.SetDefault("server.read_timeout_seconds", 15)
+	viper.SetDefault("server.write_timeout_seconds", 60)
+	viper.SetDefault("server.idle_timeout_seconds", 120)
+	viper.SetDefault("server.shutdown_timeout_seconds", 15)
+	viper.SetDefault("server.drain_delay_seconds", 0)
+	viper.SetDefault("health.timeout_ms", 2000)
+	viper.SetDefault("health.cache_ttl_ms", 2000)
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.service_name", "go-manage-backend")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
+	// 未配置时默认启用接口鉴权
+	viper.SetDefault("authz.enabled", true)
+	viper.SetDefault("two_factor.issuer", "Go Manage Starter")
+	viper.SetDefault("two_factor.challenge_expire_minutes", 5)
+	viper.SetDefault("audit.queue_size", 1000)
+	viper.SetDefault("audit.batch_size", 100)
+	viper.SetDefault("audit.flush_interval_ms", 1000)
+	viper.SetDefault("audit.overflow_policy", "drop")
+	viper.SetDefault("audit.retention.enabled", false)
+	viper.SetDefault("audit.retention.days", 90)
+	viper.SetDefault("audit.retention.interval_hours", 24)
+	viper.SetDefault("audit.retention.archive_dir", "./data/audit-archive")
+	viper.SetDefault("audit.retention.batch_size", 1000)
+	viper.SetDefault("rate_limit.enabled", false)
+	viper.SetDefault("security.login.max_attempts_per_ip", 10)
+	viper.SetDefault("security.login.ip_window_minutes", 60)
+	viper.SetDefault("security.login.max_fails_per_account", 5)
+	viper.SetDefault("security.login.fail_window_minutes", 15)
+	viper.SetDefault("security.login.lockout_mode", LockoutModeFixed)
+	viper.SetDefault("security.login.lock_duration_minutes", 15)
+	viper.SetDefault("security.login.max_lock_duration_minutes", 24*60)
+	viper.SetDefault("security.login.lockout_reset_hours", 24)
+	viper.SetDefault("security.password_reset.max_requests_per_ip", 5)
+	viper.SetDefault("security.password_reset.max_requests_per_email", 3)
+	viper.SetDefault("security.password_reset.window_minutes", 60)
+	viper.SetDefault("security.password.min_length", 8)
+	viper.SetDefault("security.password.require_lowercase", true)
+	viper.SetDefault("security.password.require_digit", true)
+	viper.SetDefault("security.password.disallow_user_info", true)
+	viper.SetDefault("security.password.history_count", 5)
+	viper.SetDefault("storage.type", StorageTypeLocal)
+	viper.SetDefault("storage.local.dir", "./data/uploads")
+	viper.SetDefault("storage.local.base_url", "/uploads")
 
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
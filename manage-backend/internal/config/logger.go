@@ -34,7 +34,13 @@ func LogConfigDetails(cfg *Config) {
 	if cfg.JWT.Secret != "" {
 		jwtSecret = "***已设置***"
 	}
+	jwtKeyID := cfg.JWT.ActiveKeyID
+	if jwtKeyID == "" {
+		jwtKeyID = "HS256（共享密钥）"
+	}
 	logger.Info("JWT配置",
 		zap.String("密钥", jwtSecret),
+		zap.String("签名密钥ID", jwtKeyID),
+		zap.Int("密钥数量", len(cfg.JWT.Keys)),
 		zap.Int("过期时间", cfg.JWT.ExpireTime))
 }
\ No newline at end of file
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -8,8 +12,13 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
 )
 
+// exportFlushEvery 导出时每输出多少条刷新一次响应
+const exportFlushEvery = 500
+
 type AuditLogHandler struct {
 	auditLogService *service.AuditLogService
 }
@@ -58,6 +67,7 @@ func (h *AuditLogHandler) GetAuditLog(c *gin.Context) {
 // @Param resource query string false "资源"
 // @Param method query string false "HTTP方法"
 // @Param status query int false "状态码"
+// @Param request_id query string false "请求ID"
 // @Param start_time query string false "开始时间" format(date-time)
 // @Param end_time query string false "结束时间" format(date-time)
 // @Param page query int false "页码" default(1)
@@ -66,6 +76,232 @@ func (h *AuditLogHandler) GetAuditLog(c *gin.Context) {
 // @Failure 400 {object} utils.APIResponse
 // @Router /audit-logs [get]
 func (h *AuditLogHandler) QueryAuditLogs(c *gin.Context) {
+	query := parseAuditLogQuery(c)
+
+	// 分页参数
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("page_size", "10")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(pageSizeStr)
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+
+	query.Page = page
+	query.PageSize = pageSize
+
+	// 查询
+	logs, total, err := h.auditLogService.Query(&query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	pagination := utils.PaginationMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+
+	utils.PaginatedSuccess(c, logs, pagination)
+}
+
+// ExportAuditLogs godoc
+// @Summary 导出审计日志
+// @Description 按条件导出审计日志（CSV 或 NDJSON），数据流式输出，导出操作本身会记录审计日志
+// @Tags audit-logs
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "导出格式" Enums(csv, ndjson) default(csv)
+// @Param user_id query int false "用户ID"
+// @Param username query string false "用户名"
+// @Param action query string false "操作"
+// @Param resource query string false "资源"
+// @Param method query string false "HTTP方法"
+// @Param status query int false "状态码"
+// @Param request_id query string false "请求ID"
+// @Param start_time query string false "开始时间" format(date-time)
+// @Param end_time query string false "结束时间" format(date-time)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.APIResponse
+// @Router /audit-logs/export [get]
+func (h *AuditLogHandler) ExportAuditLogs(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		utils.BadRequest(c, "无效的导出格式，仅支持 csv 和 ndjson")
+		return
+	}
+
+	query := parseAuditLogQuery(c)
+
+	info := service.AuditLogExportInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Path:      c.Request.URL.Path,
+		RequestID: c.GetString("request_id"),
+		Format:    format,
+		Query:     &query,
+	}
+	if uid, exists := c.Get("user_id"); exists {
+		info.UserID = uid.(uint)
+	}
+	if uname, exists := c.Get("username"); exists {
+		info.Username = uname.(string)
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+
+	// 导出数据量大时耗时较长，取消服务器的写超时限制
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.FromContext(c.Request.Context()).Warn("取消导出响应写超时失败", zap.Error(err))
+	}
+
+	var (
+		count int64
+		err   error
+	)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		count, err = h.exportCSV(c, &query)
+	} else {
+		c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+		c.Status(http.StatusOK)
+		count, err = h.exportNDJSON(c, &query)
+	}
+
+	// 响应头已发送，出错时只能中断输出并记录
+	h.auditLogService.RecordExport(info, count, err)
+}
+
+// VerifyAuditLogs godoc
+// @Summary 校验审计日志哈希链
+// @Description 按 ID 顺序校验时间范围内审计日志的哈希链，返回第一处断裂（日志被修改、删除或插入）
+// @Tags audit-logs
+// @Produce json
+// @Security BearerAuth
+// @Param start_time query string false "开始时间" format(date-time)
+// @Param end_time query string false "结束时间" format(date-time)
+// @Success 200 {object} utils.APIResponse{data=model.AuditLogVerifyResult}
+// @Failure 400 {object} utils.APIResponse
+// @Router /audit-logs/verify [get]
+func (h *AuditLogHandler) VerifyAuditLogs(c *gin.Context) {
+	var startTime, endTime time.Time
+	var err error
+
+	if startTimeStr := c.Query("start_time"); startTimeStr != "" {
+		if startTime, err = time.Parse(time.RFC3339, startTimeStr); err != nil {
+			utils.BadRequest(c, "无效的开始时间")
+			return
+		}
+	}
+	if endTimeStr := c.Query("end_time"); endTimeStr != "" {
+		if endTime, err = time.Parse(time.RFC3339, endTimeStr); err != nil {
+			utils.BadRequest(c, "无效的结束时间")
+			return
+		}
+	}
+
+	result, err := h.auditLogService.Verify(startTime, endTime)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, result)
+}
+
+// exportCSV 以 CSV 格式流式输出审计日志
+func (h *AuditLogHandler) exportCSV(c *gin.Context, query *model.AuditLogQuery) (int64, error) {
+	// 写入 UTF-8 BOM，便于 Excel 正确识别中文
+	if _, err := c.Writer.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return 0, err
+	}
+
+	writer := csv.NewWriter(c.Writer)
+	header := []string{"id", "user_id", "username", "action", "resource", "resource_id", "method", "path",
+		"ip", "user_agent", "status", "error_msg", "request_body", "duration", "created_at", "prev_hash", "hash"}
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	var written int64
+	count, err := h.auditLogService.Export(query, func(log *model.AuditLogResponse) error {
+		record := []string{
+			strconv.FormatUint(uint64(log.ID), 10),
+			strconv.FormatUint(uint64(log.UserID), 10),
+			log.Username,
+			log.Action,
+			log.Resource,
+			log.ResourceID,
+			log.Method,
+			log.Path,
+			log.IP,
+			log.UserAgent,
+			strconv.Itoa(log.Status),
+			log.ErrorMsg,
+			log.RequestBody,
+			strconv.FormatInt(log.Duration, 10),
+			log.CreatedAt.Format(time.RFC3339Nano),
+			log.PrevHash,
+			log.Hash,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		// 每批刷新一次，避免在内存中堆积
+		written++
+		if written%exportFlushEvery == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+			return writer.Error()
+		}
+		return nil
+	})
+
+	writer.Flush()
+	c.Writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	return count, err
+}
+
+// exportNDJSON 以 NDJSON 格式（每行一个 JSON 对象）流式输出审计日志
+func (h *AuditLogHandler) exportNDJSON(c *gin.Context, query *model.AuditLogQuery) (int64, error) {
+	encoder := json.NewEncoder(c.Writer)
+	encoder.SetEscapeHTML(false)
+
+	var written int64
+	count, err := h.auditLogService.Export(query, func(log *model.AuditLogResponse) error {
+		if err := encoder.Encode(log); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	c.Writer.Flush()
+	return count, err
+}
+
+// parseAuditLogQuery 解析审计日志过滤条件（不含分页参数）
+func parseAuditLogQuery(c *gin.Context) model.AuditLogQuery {
 	var query model.AuditLogQuery
 
 	// 解析查询参数
@@ -81,6 +317,7 @@ func (h *AuditLogHandler) QueryAuditLogs(c *gin.Context) {
 	query.Action = c.Query("action")
 	query.Resource = c.Query("resource")
 	query.Method = c.Query("method")
+	query.RequestID = c.Query("request_id")
 
 	if statusStr := c.Query("status"); statusStr != "" {
 		status, err := strconv.Atoi(statusStr)
@@ -102,39 +339,7 @@ func (h *AuditLogHandler) QueryAuditLogs(c *gin.Context) {
 		}
 	}
 
-	// 分页参数
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
-		pageSize = 10
-	}
-
-	query.Page = page
-	query.PageSize = pageSize
-
-	// 查询
-	logs, total, err := h.auditLogService.Query(&query)
-	if err != nil {
-		utils.HandleError(c, err)
-		return
-	}
-
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
-	pagination := utils.PaginationMeta{
-		Page:       page,
-		PageSize:   pageSize,
-		Total:      total,
-		TotalPages: totalPages,
-	}
-
-	utils.PaginatedSuccess(c, logs, pagination)
+	return query
 }
 
 // CleanOldAuditLogs godoc
@@ -155,7 +360,7 @@ func (h *AuditLogHandler) CleanOldAuditLogs(c *gin.Context) {
 		return
 	}
 
-	if err := h.auditLogService.CleanOldLogs(days); err != nil {
+	if _, err := h.auditLogService.CleanOldLogs(days, 0, nil); err != nil {
 		utils.HandleError(c, err)
 		return
 	}
@@ -150,7 +150,11 @@ func (h *DictTypeHandler) DeleteDictType(c *gin.Context) {
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Param status query string false "状态筛选" Enums(active, inactive)
-// @Param keyword query string false "关键字搜索"
+// @Param keyword query string false "代码或名称关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, code, name, sort_order, status, created_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
 // @Success 200 {object} utils.APIResponse{data=[]model.DictTypeResponse}
 // @Failure 400 {object} utils.APIResponse
 // @Router /dict-types [get]
@@ -161,15 +165,13 @@ func (h *DictTypeHandler) ListDictTypes(c *gin.Context) {
 		return
 	}
 
-	// 设置默认值
-	if req.Page <= 0 {
-		req.Page = 1
-	}
-	if req.PageSize <= 0 {
-		req.PageSize = 10
+	// 设置默认值并校验排序字段
+	if err := req.Normalize(model.DictTypeSortFields, 100); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
-	dictTypes, total, err := h.dictTypeService.List(req.Page, req.PageSize, req.Status, req.Keyword)
+	dictTypes, total, err := h.dictTypeService.List(&req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -197,6 +199,59 @@ func (h *DictTypeHandler) GetAllDictTypes(c *gin.Context) {
 	utils.Success(c, dictTypes)
 }
 
+// ListDeletedDictTypes godoc
+// @Summary 获取回收站中的字典类型
+// @Description 分页获取已删除的字典类型，默认按删除时间倒序
+// @Tags dict-types
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "代码或名称关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, code, name, sort_order, status, created_at, deleted_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} utils.PaginatedResponse{data=[]model.DictTypeResponse}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /dict-types/deleted [get]
+func (h *DictTypeHandler) ListDeletedDictTypes(c *gin.Context) {
+	listDeleted(c, model.DictTypeSortFields, h.dictTypeService.ListDeleted)
+}
+
+// RestoreDictType godoc
+// @Summary 恢复字典类型
+// @Description 恢复回收站中的字典类型，其下已删除的字典项不会随之恢复
+// @Tags dict-types
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "字典类型ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /dict-types/{id}/restore [post]
+func (h *DictTypeHandler) RestoreDictType(c *gin.Context) {
+	restoreDeleted(c, "字典类型", h.dictTypeService.Restore)
+}
+
+// PurgeDictType godoc
+// @Summary 彻底删除字典类型
+// @Description 彻底删除回收站中的字典类型及其下所有字典项，之后字典类型代码可以被重新使用
+// @Tags dict-types
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "字典类型ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /dict-types/{id}/purge [delete]
+func (h *DictTypeHandler) PurgeDictType(c *gin.Context) {
+	purgeDeleted(c, "字典类型", h.dictTypeService.Purge)
+}
+
 // ==================== DictItemHandler ====================
 
 // DictItemHandler 字典项处理器
@@ -346,6 +401,11 @@ func (h *DictItemHandler) DeleteDictItem(c *gin.Context) {
 // @Param page_size query int false "每页数量" default(10)
 // @Param dict_type_code query string false "字典类型代码"
 // @Param status query string false "状态筛选" Enums(active, inactive)
+// @Param keyword query string false "标签或值关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, label, value, sort_order, status, created_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
 // @Success 200 {object} utils.APIResponse{data=[]model.DictItemResponse}
 // @Failure 400 {object} utils.APIResponse
 // @Router /dict-items [get]
@@ -356,15 +416,13 @@ func (h *DictItemHandler) ListDictItems(c *gin.Context) {
 		return
 	}
 
-	// 设置默认值
-	if req.Page <= 0 {
-		req.Page = 1
-	}
-	if req.PageSize <= 0 {
-		req.PageSize = 10
+	// 设置默认值并校验排序字段
+	if err := req.Normalize(model.DictItemSortFields, 100); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
-	dictItems, total, err := h.dictItemService.List(req.Page, req.PageSize, req.DictTypeCode, req.Status)
+	dictItems, total, err := h.dictItemService.List(&req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
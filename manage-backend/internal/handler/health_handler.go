@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 存活与就绪检查
+type HealthHandler struct {
+	healthService *service.HealthService
+	draining      atomic.Bool
+}
+
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// MarkDraining 标记服务开始优雅关闭，之后就绪检查始终返回 503
+func (h *HealthHandler) MarkDraining() {
+	h.draining.Store(true)
+}
+
+// Live godoc
+// @Summary 存活检查
+// @Description 进程可以处理请求即返回 200，不检查依赖组件
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	// 探针接口不使用统一响应包装
+	c.JSON(http.StatusOK, gin.H{"status": model.HealthStatusOK})
+}
+
+// Ready godoc
+// @Summary 就绪检查
+// @Description 检查数据库、Redis、Casbin 策略以及（已配置时）SMTP 服务器。必需组件失败或正在关闭时返回 503；SMTP 失败只标记为 degraded，仍返回 200。结果会短暂缓存
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthReport
+// @Failure 503 {object} model.HealthReport
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, model.HealthReport{
+			Status:     model.HealthStatusDraining,
+			Components: map[string]model.ComponentHealth{},
+			CheckedAt:  time.Now(),
+		})
+		return
+	}
+
+	report := h.healthService.Check(c.Request.Context())
+	if report.Status == model.HealthStatusDown {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
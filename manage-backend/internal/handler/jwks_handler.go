@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler 发布 JWT 验签公钥
+type JWKSHandler struct {
+	keySet *auth.KeySet
+}
+
+func NewJWKSHandler(keySet *auth.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keySet: keySet,
+	}
+}
+
+// GetJWKS godoc
+// @Summary 获取 JWT 公钥集合
+// @Description 以 JWKS 格式发布所有可用于验签的公钥（含已退役但仍有效的密钥），供其他服务校验令牌
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKS "公钥集合"
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// JWKS 为标准格式，不使用统一响应包装
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keySet.JWKS())
+}
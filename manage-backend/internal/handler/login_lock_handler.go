@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/middleware"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+)
+
+// LoginLockHandler 登录锁定管理处理器
+type LoginLockHandler struct {
+	loginRateLimitService *service.LoginRateLimitService
+}
+
+// NewLoginLockHandler 创建登录锁定管理处理器实例
+func NewLoginLockHandler(loginRateLimitService *service.LoginRateLimitService) *LoginLockHandler {
+	return &LoginLockHandler{
+		loginRateLimitService: loginRateLimitService,
+	}
+}
+
+// ListAccountLocks godoc
+// @Summary 查询账户锁定状态
+// @Description 查询因连续登录失败被锁定的账户，以及当前窗口内有登录失败记录的账户
+// @Tags login-locks
+// @Produce json
+// @Security BearerAuth
+// @Param username query string false "用户名前缀"
+// @Param locked_only query bool false "只返回已锁定的账户"
+// @Success 200 {object} utils.APIResponse{data=[]model.AccountLockEntry} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /login-locks/accounts [get]
+func (h *LoginLockHandler) ListAccountLocks(c *gin.Context) {
+	var query model.AccountLockQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	entries, err := h.loginRateLimitService.ListAccountLocks(c.Request.Context(), &query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, entries)
+}
+
+// UnlockAccount godoc
+// @Summary 解除账户锁定
+// @Description 解除账户的登录锁定并清零失败次数
+// @Tags login-locks
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "用户名"
+// @Success 200 {object} utils.APIResponse "解锁成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /login-locks/accounts/{username} [delete]
+func (h *LoginLockHandler) UnlockAccount(c *gin.Context) {
+	username := c.Param("username")
+	middleware.SetAuditInfo(c, "解除账户锁定", username)
+
+	if err := h.loginRateLimitService.UnlockAccount(c.Request.Context(), username); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "账户已解锁", nil)
+}
+
+// ResetAccountFailures godoc
+// @Summary 重置登录失败次数
+// @Description 清零账户的登录失败次数和锁定次数（exponential 锁定方式下下一次锁定从首次时长开始），不解除当前的锁定
+// @Tags login-locks
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "用户名"
+// @Success 200 {object} utils.APIResponse "重置成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /login-locks/accounts/{username}/failures [delete]
+func (h *LoginLockHandler) ResetAccountFailures(c *gin.Context) {
+	username := c.Param("username")
+	middleware.SetAuditInfo(c, "重置登录失败次数", username)
+
+	if err := h.loginRateLimitService.ResetAccountFailures(c.Request.Context(), username); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "登录失败次数已重置", nil)
+}
+
+// ListIPBlocks godoc
+// @Summary 查询登录 IP 封禁状态
+// @Description 查询当前窗口内有登录记录的 IP 及是否已因请求过多被封禁
+// @Tags login-locks
+// @Produce json
+// @Security BearerAuth
+// @Param ip query string false "IP 前缀"
+// @Param blocked_only query bool false "只返回已封禁的 IP"
+// @Success 200 {object} utils.APIResponse{data=[]model.IPBlockEntry} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /login-locks/ips [get]
+func (h *LoginLockHandler) ListIPBlocks(c *gin.Context) {
+	var query model.IPBlockQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	entries, err := h.loginRateLimitService.ListIPBlocks(c.Request.Context(), &query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, entries)
+}
+
+// UnblockIP godoc
+// @Summary 解除登录 IP 封禁
+// @Description 清除 IP 在当前窗口内的登录次数
+// @Tags login-locks
+// @Produce json
+// @Security BearerAuth
+// @Param ip path string true "客户端 IP"
+// @Success 200 {object} utils.APIResponse "解除成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /login-locks/ips/{ip} [delete]
+func (h *LoginLockHandler) UnblockIP(c *gin.Context) {
+	ip := c.Param("ip")
+	middleware.SetAuditInfo(c, "解除登录 IP 封禁", ip)
+
+	if err := h.loginRateLimitService.UnblockIP(c.Request.Context(), ip); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "IP 封禁已解除", nil)
+}
@@ -230,3 +230,56 @@ func (h *MenuHandler) UpdateMenuOrder(c *gin.Context) {
 
 	utils.Success(c, gin.H{"message": "顺序更新成功"})
 }
+
+// ListDeletedMenus godoc
+// @Summary 获取回收站中的菜单
+// @Description 分页获取已删除的菜单，默认按删除时间倒序
+// @Tags menus
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "名称、标题或路径关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, name, title, order_num, status, created_at, deleted_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} utils.PaginatedResponse{data=[]model.MenuResponse}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /menus/deleted [get]
+func (h *MenuHandler) ListDeletedMenus(c *gin.Context) {
+	listDeleted(c, model.MenuSortFields, h.menuService.ListDeleted)
+}
+
+// RestoreMenu godoc
+// @Summary 恢复菜单
+// @Description 恢复回收站中的菜单，父菜单必须存在且未被删除
+// @Tags menus
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "菜单ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /menus/{id}/restore [post]
+func (h *MenuHandler) RestoreMenu(c *gin.Context) {
+	restoreDeleted(c, "菜单", h.menuService.Restore)
+}
+
+// PurgeMenu godoc
+// @Summary 彻底删除菜单
+// @Description 彻底删除回收站中的菜单，存在子菜单（包括已删除的子菜单）时不允许删除
+// @Tags menus
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "菜单ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /menus/{id}/purge [delete]
+func (h *MenuHandler) PurgeMenu(c *gin.Context) {
+	purgeDeleted(c, "菜单", h.menuService.Purge)
+}
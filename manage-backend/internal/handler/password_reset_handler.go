@@ -33,7 +33,7 @@ func NewPasswordResetHandler(service service.PasswordResetService) *PasswordRese
 func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
 	var req model.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("忘记密码请求参数错误",
+		logger.FromContext(c.Request.Context()).Warn("忘记密码请求参数错误",
 			zap.Error(err),
 			zap.String("operation", "forgot_password"))
 		utils.BadRequest(c, "请求参数错误")
@@ -43,13 +43,13 @@ func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
-	logger.Info("收到忘记密码请求",
+	logger.FromContext(c.Request.Context()).Info("收到忘记密码请求",
 		zap.String("email", req.Email),
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "forgot_password"))
 
 	if err := h.service.RequestPasswordReset(c.Request.Context(), req.Email, ipAddress, userAgent); err != nil {
-		logger.Error("处理忘记密码请求失败",
+		logger.FromContext(c.Request.Context()).Error("处理忘记密码请求失败",
 			zap.String("email", req.Email),
 			zap.Error(err),
 			zap.String("operation", "forgot_password"))
@@ -77,26 +77,26 @@ func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
 func (h *PasswordResetHandler) VerifyResetToken(c *gin.Context) {
 	var req model.VerifyResetTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("验证Token请求参数错误",
+		logger.FromContext(c.Request.Context()).Warn("验证Token请求参数错误",
 			zap.Error(err),
 			zap.String("operation", "verify_reset_token"))
 		utils.BadRequest(c, "请求参数错误")
 		return
 	}
 
-	logger.Debug("收到验证Token请求",
+	logger.FromContext(c.Request.Context()).Debug("收到验证Token请求",
 		zap.String("operation", "verify_reset_token"))
 
 	user, err := h.service.VerifyResetToken(c.Request.Context(), req.Token)
 	if err != nil {
-		logger.Warn("Token验证失败",
+		logger.FromContext(c.Request.Context()).Warn("Token验证失败",
 			zap.Error(err),
 			zap.String("operation", "verify_reset_token"))
 		utils.BadRequest(c, err.Error())
 		return
 	}
 
-	logger.Info("Token验证成功",
+	logger.FromContext(c.Request.Context()).Info("Token验证成功",
 		zap.Uint("user_id", user.ID),
 		zap.String("email", user.Email),
 		zap.String("operation", "verify_reset_token"))
@@ -121,7 +121,7 @@ func (h *PasswordResetHandler) VerifyResetToken(c *gin.Context) {
 func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 	var req model.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("重置密码请求参数错误",
+		logger.FromContext(c.Request.Context()).Warn("重置密码请求参数错误",
 			zap.Error(err),
 			zap.String("operation", "reset_password"))
 		utils.BadRequest(c, "请求参数错误")
@@ -131,12 +131,12 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
-	logger.Info("收到重置密码请求",
+	logger.FromContext(c.Request.Context()).Info("收到重置密码请求",
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "reset_password"))
 
 	if err := h.service.ResetPassword(c.Request.Context(), req.Token, req.NewPassword, ipAddress, userAgent); err != nil {
-		logger.Warn("密码重置失败",
+		logger.FromContext(c.Request.Context()).Warn("密码重置失败",
 			zap.Error(err),
 			zap.String("ip_address", ipAddress),
 			zap.String("operation", "reset_password"))
@@ -144,7 +144,7 @@ func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	logger.Info("密码重置成功",
+	logger.FromContext(c.Request.Context()).Info("密码重置成功",
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "reset_password"))
 
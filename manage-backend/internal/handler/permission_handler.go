@@ -142,36 +142,38 @@ func (h *PermissionHandler) DeletePermission(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "名称、代码或路径关键字"
+// @Param resource query string false "资源"
+// @Param type query string false "类型" Enums(api, menu, button)
+// @Param status query string false "状态" Enums(active, inactive)
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, name, code, resource, type, status, created_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
 // @Success 200 {object} utils.PaginatedResponse{data=[]model.PermissionResponse}
 // @Failure 400 {object} utils.APIResponse
 // @Router /permissions [get]
 func (h *PermissionHandler) ListPermissions(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
-		pageSize = 10
+	var query model.PermissionListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	if err := query.Normalize(model.PermissionSortFields, 100); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
-	permissions, total, err := h.permissionService.List(page, pageSize)
+	permissions, total, err := h.permissionService.List(&query)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
 
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
 	pagination := utils.PaginationMeta{
-		Page:       page,
-		PageSize:   pageSize,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
 		Total:      total,
 		TotalPages: totalPages,
 	}
@@ -268,3 +270,56 @@ func (h *PermissionHandler) GetPermissionTree(c *gin.Context) {
 
 	utils.Success(c, tree)
 }
+
+// ListDeletedPermissions godoc
+// @Summary 获取回收站中的权限
+// @Description 分页获取已删除的权限，默认按删除时间倒序
+// @Tags permissions
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "名称、代码或路径关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, name, code, resource, type, status, created_at, deleted_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} utils.PaginatedResponse{data=[]model.PermissionResponse}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /permissions/deleted [get]
+func (h *PermissionHandler) ListDeletedPermissions(c *gin.Context) {
+	listDeleted(c, model.PermissionSortFields, h.permissionService.ListDeleted)
+}
+
+// RestorePermission godoc
+// @Summary 恢复权限
+// @Description 恢复回收站中的权限
+// @Tags permissions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "权限ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /permissions/{id}/restore [post]
+func (h *PermissionHandler) RestorePermission(c *gin.Context) {
+	restoreDeleted(c, "权限", h.permissionService.Restore)
+}
+
+// PurgePermission godoc
+// @Summary 彻底删除权限
+// @Description 彻底删除回收站中的权限及其角色关联和对应的 Casbin 策略，之后权限代码可以被重新使用
+// @Tags permissions
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "权限ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /permissions/{id}/purge [delete]
+func (h *PermissionHandler) PurgePermission(c *gin.Context) {
+	purgeDeleted(c, "权限", h.permissionService.Purge)
+}
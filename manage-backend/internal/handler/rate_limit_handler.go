@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+)
+
+// RateLimitHandler 接口限流管理处理器
+type RateLimitHandler struct {
+	rateLimiter *service.RateLimiter
+}
+
+// NewRateLimitHandler 创建接口限流管理处理器实例
+func NewRateLimitHandler(rateLimiter *service.RateLimiter) *RateLimitHandler {
+	return &RateLimitHandler{
+		rateLimiter: rateLimiter,
+	}
+}
+
+// ListRateLimits godoc
+// @Summary 查询当前限流计数
+// @Description 查询各路由分组中窗口内有请求记录的限流对象及剩余次数
+// @Tags rate-limits
+// @Produce json
+// @Security BearerAuth
+// @Param group query string false "路由分组，如 auth、public、api"
+// @Param subject query string false "限流对象前缀，如 ip:127.0.0.1、user:1"
+// @Success 200 {object} utils.APIResponse{data=[]model.RateLimitEntry} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /rate-limits [get]
+func (h *RateLimitHandler) ListRateLimits(c *gin.Context) {
+	var query model.RateLimitQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	entries, err := h.rateLimiter.List(c.Request.Context(), &query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, entries)
+}
+
+// ClearRateLimits godoc
+// @Summary 清除限流计数
+// @Description 解除指定对象的限流，未指定对象时清除整个路由分组的计数
+// @Tags rate-limits
+// @Produce json
+// @Security BearerAuth
+// @Param group query string true "路由分组"
+// @Param subject query string false "限流对象，如 ip:127.0.0.1、user:1"
+// @Success 200 {object} utils.APIResponse{data=model.ClearRateLimitResponse} "清除成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /rate-limits [delete]
+func (h *RateLimitHandler) ClearRateLimits(c *gin.Context) {
+	var req model.ClearRateLimitRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	cleared, err := h.rateLimiter.Clear(c.Request.Context(), req.Group, req.Subject)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, model.ClearRateLimitResponse{Cleared: cleared})
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/middleware"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// 各资源的回收站接口（列表、恢复、彻底删除）共用以下流程，处理器只需传入资源名称和对应的服务方法
+
+// listDeleted 分页返回回收站中的资源
+func listDeleted[T any](c *gin.Context, sortFields model.SortFields, list func(query *model.ListQuery) ([]T, int64, error)) {
+	query, ok := bindDeletedListQuery(c, sortFields)
+	if !ok {
+		return
+	}
+
+	items, total, err := list(query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	deletedListSuccess(c, items, query, total)
+}
+
+// restoreDeleted 恢复回收站中路径参数 id 指定的资源
+func restoreDeleted(c *gin.Context, resource string, restore func(id uint) error) {
+	id, ok := parseRecycleBinID(c, resource)
+	if !ok {
+		return
+	}
+
+	middleware.SetAuditInfo(c, "恢复"+resource, c.Param("id"))
+
+	if err := restore(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, resource+"已恢复", nil)
+}
+
+// purgeDeleted 彻底删除回收站中路径参数 id 指定的资源
+func purgeDeleted(c *gin.Context, resource string, purge func(id uint) error) {
+	id, ok := parseRecycleBinID(c, resource)
+	if !ok {
+		return
+	}
+
+	middleware.SetAuditInfo(c, "彻底删除"+resource, c.Param("id"))
+
+	if err := purge(id); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, resource+"已彻底删除", nil)
+}
+
+// parseRecycleBinID 解析路径参数中的资源 ID，无效时直接写入响应并返回 false
+func parseRecycleBinID(c *gin.Context, resource string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的"+resource+"ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// bindDeletedListQuery 解析回收站列表的查询参数，排序字段在原列表的基础上增加 deleted_at
+// 参数错误时直接写入响应并返回 false
+func bindDeletedListQuery(c *gin.Context, sortFields model.SortFields) (*model.ListQuery, bool) {
+	var query model.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return nil, false
+	}
+	if err := query.Normalize(model.DeletedSortFields(sortFields), 100); err != nil {
+		utils.BadRequest(c, err.Error())
+		return nil, false
+	}
+	return &query, true
+}
+
+// deletedListSuccess 返回回收站列表的分页响应
+func deletedListSuccess(c *gin.Context, data interface{}, query *model.ListQuery, total int64) {
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
+	utils.PaginatedSuccess(c, data, utils.PaginationMeta{
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+	})
+}
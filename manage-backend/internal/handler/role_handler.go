@@ -37,7 +37,7 @@ func (h *RoleHandler) CreateRole(c *gin.Context) {
 		return
 	}
 
-	role, err := h.roleService.Create(&req)
+	role, err := h.roleService.Create(c.Request.Context(), &req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -101,7 +101,7 @@ func (h *RoleHandler) UpdateRole(c *gin.Context) {
 		return
 	}
 
-	role, err := h.roleService.Update(uint(id), &req)
+	role, err := h.roleService.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -129,7 +129,7 @@ func (h *RoleHandler) DeleteRole(c *gin.Context) {
 		return
 	}
 
-	if err := h.roleService.Delete(uint(id)); err != nil {
+	if err := h.roleService.Delete(c.Request.Context(), uint(id)); err != nil {
 		utils.HandleError(c, err)
 		return
 	}
@@ -139,42 +139,42 @@ func (h *RoleHandler) DeleteRole(c *gin.Context) {
 
 // ListRoles godoc
 // @Summary 获取角色列表
-// @Description 分页获取角色列表
+// @Description 按条件分页获取角色列表
 // @Tags roles
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "名称或代码关键字"
+// @Param status query string false "状态" Enums(active, inactive)
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, name, code, status, created_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
 // @Success 200 {object} utils.PaginatedResponse{data=[]model.RoleResponse}
 // @Failure 400 {object} utils.APIResponse
 // @Router /roles [get]
 func (h *RoleHandler) ListRoles(c *gin.Context) {
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
-		pageSize = 10
+	var query model.RoleListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	if err := query.Normalize(model.RoleSortFields, 100); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
-	roles, total, err := h.roleService.List(page, pageSize)
+	roles, total, err := h.roleService.List(&query)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
 
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
 	pagination := utils.PaginationMeta{
-		Page:       page,
-		PageSize:   pageSize,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
 		Total:      total,
 		TotalPages: totalPages,
 	}
@@ -227,7 +227,7 @@ func (h *RoleHandler) AssignPermissions(c *gin.Context) {
 		return
 	}
 
-	if err := h.roleService.AssignPermissions(uint(id), &req); err != nil {
+	if err := h.roleService.AssignPermissions(c.Request.Context(), uint(id), &req); err != nil {
 		utils.HandleError(c, err)
 		return
 	}
@@ -291,7 +291,7 @@ func (h *RoleHandler) AssignRolesToUser(c *gin.Context) {
 	// 获取当前操作用户ID
 	assignedBy := c.GetUint("user_id")
 
-	if err := h.roleService.AssignRolesToUser(uint(userID), &req, assignedBy); err != nil {
+	if err := h.roleService.AssignRolesToUser(c.Request.Context(), uint(userID), &req, assignedBy); err != nil {
 		utils.HandleError(c, err)
 		return
 	}
@@ -325,3 +325,60 @@ func (h *RoleHandler) GetUserRoles(c *gin.Context) {
 
 	utils.Success(c, roles)
 }
+
+// ListDeletedRoles godoc
+// @Summary 获取回收站中的角色
+// @Description 分页获取已删除的角色，默认按删除时间倒序
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "名称或代码关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, name, code, status, created_at, deleted_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} utils.PaginatedResponse{data=[]model.RoleResponse}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /roles/deleted [get]
+func (h *RoleHandler) ListDeletedRoles(c *gin.Context) {
+	listDeleted(c, model.RoleSortFields, h.roleService.ListDeleted)
+}
+
+// RestoreRole godoc
+// @Summary 恢复角色
+// @Description 恢复回收站中的角色，并按保留的角色权限重新同步 Casbin 策略
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /roles/{id}/restore [post]
+func (h *RoleHandler) RestoreRole(c *gin.Context) {
+	restoreDeleted(c, "角色", func(id uint) error {
+		return h.roleService.Restore(c.Request.Context(), id)
+	})
+}
+
+// PurgeRole godoc
+// @Summary 彻底删除角色
+// @Description 彻底删除回收站中的角色及其权限关联和 Casbin 策略，之后角色代码可以被重新使用
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "角色ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /roles/{id}/purge [delete]
+func (h *RoleHandler) PurgeRole(c *gin.Context) {
+	purgeDeleted(c, "角色", func(id uint) error {
+		return h.roleService.Purge(c.Request.Context(), id)
+	})
+}
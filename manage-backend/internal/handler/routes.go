@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/casbin/casbin/v2"
@@ -12,10 +13,11 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
 	casbinpkg "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/casbin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/storage"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer) {
+func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer, keySet *auth.KeySet, auditWriter *middleware.AuditWriter, redisClient *cache.RedisClient, securityPolicyService *service.SecurityPolicyService) {
 	cfg := config.Load()
 	
 	// 使用配置初始化 JWT 管理器
@@ -30,10 +32,7 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 		refreshTokenExpire = 720 // 默认 30 天（小时数）
 	}
 	
-	jwtManager := auth.NewJWTManager(cfg.JWT.Secret, accessTokenExpire, refreshTokenExpire)
-
-	// 初始化 Redis 客户端
-	redisClient := cache.NewRedisClient(cfg.Redis)
+	jwtManager := auth.NewJWTManagerWithKeySet(keySet, accessTokenExpire, refreshTokenExpire)
 
 	// 初始化仓储层
 	userRepo := repository.NewUserRepository(db)
@@ -43,20 +42,23 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 	auditLogRepo := repository.NewAuditLogRepository(db)
 	dictTypeRepo := repository.NewDictTypeRepository(db)
 	dictItemRepo := repository.NewDictItemRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
 	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	passwordHistoryRepo := repository.NewPasswordHistoryRepository(db)
 
 	// 初始化服务层
 	sessionService := service.NewSessionService(redisClient, jwtManager)
 	casbinService := service.NewCasbinService(enforcer)
 	roleService := service.NewRoleService(roleRepo, permissionRepo, casbinService)
-	permissionService := service.NewPermissionService(permissionRepo)
+	permissionService := service.NewPermissionService(permissionRepo, casbinService)
 	menuService := service.NewMenuService(menuRepo, permissionRepo)
-	auditLogService := service.NewAuditLogService(auditLogRepo)
+	auditLogService := service.NewAuditLogService(auditLogRepo, auditWriter, cfg.AuditCheckpointKey())
 	dictTypeService := service.NewDictTypeService(dictTypeRepo, dictItemRepo)
 	dictItemService := service.NewDictItemService(dictTypeRepo, dictItemRepo)
 	emailService := service.NewEmailService(cfg)
-	passwordResetRedisService := service.NewPasswordResetRedisService(redisClient)
-	passwordResetService := service.NewPasswordResetService(cfg, userRepo, passwordResetRepo, emailService, auditLogService, passwordResetRedisService)
+	passwordPolicyService := service.NewPasswordPolicyService(securityPolicyService, passwordHistoryRepo)
+	passwordResetRedisService := service.NewPasswordResetRedisService(redisClient, securityPolicyService)
+	passwordResetService := service.NewPasswordResetService(cfg, userRepo, passwordResetRepo, emailService, auditLogService, passwordResetRedisService, passwordPolicyService)
 	
 	// 验证码配置
 	captchaConfig := service.CaptchaConfig{
@@ -86,8 +88,14 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 	}
 	
 	captchaService := service.NewCaptchaService(redisClient.GetClient(), captchaConfig)
-	loginRateLimitService := service.NewLoginRateLimitService(redisClient)
-	userService := service.NewUserService(userRepo, jwtManager, sessionService, captchaService, roleRepo, permissionService, loginRateLimitService)
+	loginRateLimitService := service.NewLoginRateLimitService(redisClient, securityPolicyService)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, redisClient, cfg.TwoFactor)
+	fileStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		panic(fmt.Sprintf("无法初始化文件存储: %v", err))
+	}
+	userService := service.NewUserService(userRepo, jwtManager, sessionService, captchaService, roleRepo, permissionService, loginRateLimitService, casbinService, auditLogService, twoFactorService, passwordPolicyService, fileStorage)
+	rateLimiter := service.NewRateLimiter(redisClient.GetClient(), cfg.RateLimit)
 
 	// 初始化处理器
 	userHandler := NewUserHandler(userService)
@@ -99,14 +107,30 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 	dictTypeHandler := NewDictTypeHandler(dictTypeService)
 	dictItemHandler := NewDictItemHandler(dictItemService)
 	passwordResetHandler := NewPasswordResetHandler(passwordResetService)
+	twoFactorHandler := NewTwoFactorHandler(twoFactorService)
+	rateLimitHandler := NewRateLimitHandler(rateLimiter)
+	securityPolicyHandler := NewSecurityPolicyHandler(securityPolicyService)
+	loginLockHandler := NewLoginLockHandler(loginRateLimitService)
 
 	// 审计日志中间件配置
 	auditConfig := middleware.DefaultAuditLogConfig()
-	router.Use(middleware.AuditLogger(db, auditConfig))
+	router.Use(middleware.AuditLogger(auditWriter, auditConfig))
 
+	// Casbin 鉴权中间件配置
+	casbinConfig := middleware.DefaultCasbinConfig()
+	casbinConfig.APIPrefix = router.BasePath()
+	if len(cfg.Authz.SuperAdminRoles) > 0 {
+		casbinConfig.SuperAdminRoles = cfg.Authz.SuperAdminRoles
+	}
+	if len(cfg.Authz.AllowList) > 0 {
+		casbinConfig.AllowList = cfg.Authz.AllowList
+	}
+	if len(cfg.Authz.PolicyPrefixes) > 0 {
+		casbinConfig.PolicyPrefixes = cfg.Authz.PolicyPrefixes
+	}
 
 	// 用户可用性检查路由（无需认证）
-	userCheck := router.Group("/users")
+	userCheck := router.Group("/users", middleware.RateLimit(rateLimiter, "public"))
 	{
 		userCheck.GET("/check-username/:username", userHandler.CheckUsernameAvailable)
 		userCheck.GET("/check-email/:email", userHandler.CheckEmailAvailable)
@@ -114,12 +138,13 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 	}
 
 	// 认证路由（无需认证）
-	authRoutes := router.Group("/auth")
+	authRoutes := router.Group("/auth", middleware.RateLimit(rateLimiter, "auth"))
 	{
 		authRoutes.GET("/captcha", captchaHandler.GenerateCaptcha)
 		authRoutes.POST("/register", userHandler.Register)
 		authRoutes.POST("/login", userHandler.Login)
 		authRoutes.POST("/refresh", userHandler.RefreshToken)
+		authRoutes.POST("/2fa/verify", userHandler.VerifyTwoFactor)
 		authRoutes.POST("/forgot-password", passwordResetHandler.ForgotPassword)
 		authRoutes.POST("/verify-reset-token", passwordResetHandler.VerifyResetToken)
 		authRoutes.POST("/reset-password", passwordResetHandler.ResetPassword)
@@ -128,6 +153,11 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 	// 受保护的路由（需要认证）
 	protected := router.Group("/")
 	protected.Use(middleware.JWTAuthWithSession(jwtManager, sessionService))
+	protected.Use(middleware.PasswordChangeGuard(sessionService, router.BasePath(), middleware.DefaultPasswordChangeAllowList))
+	protected.Use(middleware.RateLimit(rateLimiter, "api"))
+	if cfg.Authz.Enabled {
+		protected.Use(middleware.CasbinEnforcerWithConfig(enforcer, casbinConfig))
+	}
 	{
 		// 需要认证的认证路由
 		authProtected := protected.Group("/auth")
@@ -140,16 +170,39 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 		{
 			users.GET("/profile", userHandler.GetProfile)
 			users.PUT("/profile", userHandler.UpdateProfile)
+			users.PUT("/profile/password", userHandler.ChangePassword)
+			users.POST("/profile/avatar", userHandler.UploadAvatar)
+			users.GET("/profile/2fa", twoFactorHandler.GetStatus)
+			users.POST("/profile/2fa/enroll", twoFactorHandler.Enroll)
+			users.POST("/profile/2fa/confirm", twoFactorHandler.Confirm)
 			users.GET("/permissions", userHandler.GetUserPermissions)
+			users.GET("/sessions", userHandler.ListMySessions)
+			users.DELETE("/sessions", userHandler.RevokeMyOtherSessions)
+			users.DELETE("/sessions/:session_id", userHandler.RevokeMySession)
 			users.GET("", userHandler.ListUsers)
 			users.POST("", userHandler.CreateUser)
+			users.POST("/import", userHandler.ImportUsers)
+			users.GET("/export", userHandler.ExportUsers)
+			users.POST("/batch", userHandler.BatchUsers)
+			users.GET("/deleted", userHandler.ListDeletedUsers)
 			users.GET("/:id", userHandler.GetUser)
 			users.PUT("/:id", userHandler.UpdateUser)
 			users.DELETE("/:id", userHandler.DeleteUser)
+			users.PUT("/:id/status", userHandler.UpdateUserStatus)
+			users.POST("/:id/restore", userHandler.RestoreUser)
+			users.DELETE("/:id/purge", userHandler.PurgeUser)
 			
 			// 用户角色管理
 			users.GET("/:id/roles", roleHandler.GetUserRoles)
 			users.PUT("/:id/roles", roleHandler.AssignRolesToUser)
+
+			// 用户会话管理
+			users.GET("/:id/sessions", userHandler.ListUserSessions)
+			users.DELETE("/:id/sessions", userHandler.RevokeUserSessions)
+			users.DELETE("/:id/sessions/:session_id", userHandler.RevokeUserSession)
+
+			// 两步验证管理
+			users.DELETE("/:id/2fa", twoFactorHandler.ResetUserTwoFactor)
 		}
 
 		// 角色路由
@@ -157,10 +210,13 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 		{
 			roles.GET("", roleHandler.ListRoles)
 			roles.GET("/all", roleHandler.GetAllRoles)
+			roles.GET("/deleted", roleHandler.ListDeletedRoles)
 			roles.POST("", roleHandler.CreateRole)
 			roles.GET("/:id", roleHandler.GetRole)
 			roles.PUT("/:id", roleHandler.UpdateRole)
 			roles.DELETE("/:id", roleHandler.DeleteRole)
+			roles.POST("/:id/restore", roleHandler.RestoreRole)
+			roles.DELETE("/:id/purge", roleHandler.PurgeRole)
 			roles.GET("/:id/permissions", roleHandler.GetRolePermissions)
 			roles.PUT("/:id/permissions", roleHandler.AssignPermissions)
 		}
@@ -171,10 +227,13 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 			permissions.GET("", permissionHandler.ListPermissions)
 			permissions.GET("/all", permissionHandler.GetAllPermissions)
 			permissions.GET("/tree", permissionHandler.GetPermissionTree)
+			permissions.GET("/deleted", permissionHandler.ListDeletedPermissions)
 			permissions.POST("", permissionHandler.CreatePermission)
 			permissions.GET("/:id", permissionHandler.GetPermission)
 			permissions.PUT("/:id", permissionHandler.UpdatePermission)
 			permissions.DELETE("/:id", permissionHandler.DeletePermission)
+			permissions.POST("/:id/restore", permissionHandler.RestorePermission)
+			permissions.DELETE("/:id/purge", permissionHandler.PurgePermission)
 			permissions.GET("/resource/:resource", permissionHandler.GetPermissionsByResource)
 			permissions.GET("/type/:type", permissionHandler.GetPermissionsByType)
 		}
@@ -185,30 +244,62 @@ func SetupRoutes(router *gin.RouterGroup, db *gorm.DB, enforcer *casbin.Enforcer
 			menus.GET("/tree", menuHandler.GetMenuTree)
 			menus.GET("/tree/visible", menuHandler.GetVisibleMenuTree)
 			menus.GET("/user", menuHandler.GetUserMenuTree)
+			menus.GET("/deleted", menuHandler.ListDeletedMenus)
 			menus.POST("", menuHandler.CreateMenu)
 			menus.GET("/:id", menuHandler.GetMenu)
 			menus.PUT("/:id", menuHandler.UpdateMenu)
 			menus.PUT("/order", menuHandler.UpdateMenuOrder)
 			menus.DELETE("/:id", menuHandler.DeleteMenu)
+			menus.POST("/:id/restore", menuHandler.RestoreMenu)
+			menus.DELETE("/:id/purge", menuHandler.PurgeMenu)
 		}
 
 		// 审计日志路由
 		auditLogs := protected.Group("/audit-logs")
 		{
 			auditLogs.GET("", auditLogHandler.QueryAuditLogs)
+			auditLogs.GET("/export", auditLogHandler.ExportAuditLogs)
+			auditLogs.GET("/verify", auditLogHandler.VerifyAuditLogs)
 			auditLogs.GET("/:id", auditLogHandler.GetAuditLog)
 			auditLogs.POST("/clean", auditLogHandler.CleanOldAuditLogs)
 		}
 
+		// 接口限流管理路由
+		rateLimits := protected.Group("/rate-limits")
+		{
+			rateLimits.GET("", rateLimitHandler.ListRateLimits)
+			rateLimits.DELETE("", rateLimitHandler.ClearRateLimits)
+		}
+
+		// 安全策略路由
+		security := protected.Group("/security")
+		{
+			security.GET("/policy", securityPolicyHandler.GetSecurityPolicy)
+			security.PUT("/policy", securityPolicyHandler.UpdateSecurityPolicy)
+		}
+
+		// 登录锁定管理路由
+		loginLocks := protected.Group("/login-locks")
+		{
+			loginLocks.GET("/accounts", loginLockHandler.ListAccountLocks)
+			loginLocks.DELETE("/accounts/:username", loginLockHandler.UnlockAccount)
+			loginLocks.DELETE("/accounts/:username/failures", loginLockHandler.ResetAccountFailures)
+			loginLocks.GET("/ips", loginLockHandler.ListIPBlocks)
+			loginLocks.DELETE("/ips/:ip", loginLockHandler.UnblockIP)
+		}
+
 		// 字典类型路由
 		dictTypes := protected.Group("/dict-types")
 		{
 			dictTypes.GET("", dictTypeHandler.ListDictTypes)
 			dictTypes.GET("/all", dictTypeHandler.GetAllDictTypes)
+			dictTypes.GET("/deleted", dictTypeHandler.ListDeletedDictTypes)
 			dictTypes.POST("", dictTypeHandler.CreateDictType)
 			dictTypes.GET("/:id", dictTypeHandler.GetDictType)
 			dictTypes.PUT("/:id", dictTypeHandler.UpdateDictType)
 			dictTypes.DELETE("/:id", dictTypeHandler.DeleteDictType)
+			dictTypes.POST("/:id/restore", dictTypeHandler.RestoreDictType)
+			dictTypes.DELETE("/:id/purge", dictTypeHandler.PurgeDictType)
 		}
 
 		// 字典项路由
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+)
+
+// SecurityPolicyHandler 安全策略管理处理器
+type SecurityPolicyHandler struct {
+	securityPolicyService *service.SecurityPolicyService
+}
+
+// NewSecurityPolicyHandler 创建安全策略管理处理器实例
+func NewSecurityPolicyHandler(securityPolicyService *service.SecurityPolicyService) *SecurityPolicyHandler {
+	return &SecurityPolicyHandler{
+		securityPolicyService: securityPolicyService,
+	}
+}
+
+// GetSecurityPolicy godoc
+// @Summary 获取安全策略
+// @Description 获取当前生效的登录锁定、密码重置限流和密码强度策略，source 为 config 表示使用配置文件中的策略
+// @Tags security
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=model.SecurityPolicyResponse} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Router /security/policy [get]
+func (h *SecurityPolicyHandler) GetSecurityPolicy(c *gin.Context) {
+	utils.Success(c, h.securityPolicyService.GetPolicy())
+}
+
+// UpdateSecurityPolicy godoc
+// @Summary 更新安全策略
+// @Description 更新登录锁定、密码重置限流和密码强度策略，保存后立即在所有实例生效，无需重启
+// @Tags security
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param policy body config.SecurityConfig true "完整的安全策略"
+// @Success 200 {object} utils.APIResponse{data=model.SecurityPolicyResponse} "更新成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /security/policy [put]
+func (h *SecurityPolicyHandler) UpdateSecurityPolicy(c *gin.Context) {
+	var policy config.SecurityConfig
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	response, err := h.securityPolicyService.Update(c.Request.Context(), policy, c.GetUint("user_id"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "安全策略已更新", response)
+}
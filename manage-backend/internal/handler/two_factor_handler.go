@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+)
+
+// TwoFactorHandler 两步验证处理器
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler 创建两步验证处理器实例
+func NewTwoFactorHandler(twoFactorService *service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// GetStatus godoc
+// @Summary 获取两步验证状态
+// @Description 获取当前用户是否已启用两步验证及剩余恢复码数量
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=model.TwoFactorStatusResponse} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/profile/2fa [get]
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.GetStatus(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, status)
+}
+
+// Enroll godoc
+// @Summary 绑定两步验证
+// @Description 生成 TOTP 密钥并返回 otpauth 绑定地址，需调用确认接口后才会生效
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=model.TwoFactorEnrollResponse} "生成成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 409 {object} utils.APIResponse "已启用两步验证"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/profile/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	response, err := h.twoFactorService.Enroll(c.Request.Context(), c.GetUint("user_id"), c.GetString("username"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// Confirm godoc
+// @Summary 确认绑定两步验证
+// @Description 使用身份验证器生成的首个验证码确认绑定，成功后启用两步验证并返回一次性恢复码
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorConfirmRequest true "验证码"
+// @Success 200 {object} utils.APIResponse{data=model.TwoFactorRecoveryCodesResponse} "启用成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "验证码错误"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/profile/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req model.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "请求参数格式错误")
+		return
+	}
+
+	response, err := h.twoFactorService.Confirm(c.Request.Context(), c.GetUint("user_id"), req.Code)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// ResetUserTwoFactor godoc
+// @Summary 重置用户的两步验证
+// @Description 管理员清除指定用户的两步验证配置（例如用户丢失设备且恢复码用尽）
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse "重置成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/{id}/2fa [delete]
+func (h *TwoFactorHandler) ResetUserTwoFactor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.twoFactorService.Reset(c.Request.Context(), uint(id)); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, gin.H{"message": "两步验证已重置"})
+}
@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/middleware"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/service"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
 )
 
 type UserHandler struct {
@@ -46,12 +52,12 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary 用户登录
-// @Description 使用用户名、密码和验证码进行登录
+// @Description 使用用户名、密码和验证码进行登录；已启用两步验证的用户将返回登录挑战，需调用 /auth/2fa/verify 完成登录
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body model.LoginRequest true "Login credentials with captcha"
-// @Success 200 {object} utils.APIResponse{data=model.LoginResponse} "登录成功"
+// @Success 200 {object} utils.APIResponse{data=model.LoginResponse} "登录成功（或 model.TwoFactorChallengeResponse）"
 // @Failure 400 {object} utils.APIResponse "请求参数错误"
 // @Failure 401 {object} utils.APIResponse "认证失败或验证码错误"
 // @Failure 500 {object} utils.APIResponse "服务器内部错误"
@@ -72,7 +78,40 @@ func (h *UserHandler) Login(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
-	response, err := h.userService.LoginWithContext(c.Request.Context(), &req, deviceInfo, ipAddress, userAgent)
+	response, challenge, err := h.userService.LoginWithContext(c.Request.Context(), &req, deviceInfo, ipAddress, userAgent)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	if challenge != nil {
+		utils.Success(c, challenge)
+		return
+	}
+
+	utils.Success(c, response)
+}
+
+// VerifyTwoFactor godoc
+// @Summary 两步验证登录
+// @Description 提交登录挑战令牌和身份验证器验证码（或恢复码）完成登录
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.TwoFactorVerifyRequest true "挑战令牌和验证码"
+// @Success 200 {object} utils.APIResponse{data=model.LoginResponse} "登录成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "验证码错误或挑战已过期"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /auth/2fa/verify [post]
+func (h *UserHandler) VerifyTwoFactor(c *gin.Context) {
+	var req model.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "请求参数格式错误")
+		return
+	}
+
+	response, err := h.userService.VerifyTwoFactorLogin(c.Request.Context(), &req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -83,7 +122,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary 刷新访问令牌
-// @Description 使用刷新令牌刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌立即失效
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -100,7 +139,7 @@ func (h *UserHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	response, err := h.userService.RefreshToken(c.Request.Context(), &req)
+	response, err := h.userService.RefreshTokenWithClientInfo(c.Request.Context(), &req, c.ClientIP(), c.GetHeader("User-Agent"))
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -141,13 +180,13 @@ func (h *UserHandler) Logout(c *gin.Context) {
 
 // GetProfile godoc
 // @Summary 获取用户资料
-// @Description 获取当前用户的个人资料
+// @Description 获取当前用户的个人资料，包括显示名称、手机号、部门、语言、时区、头像和个人偏好设置
 // @Tags users
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} model.User
-// @Failure 401 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
+// @Success 200 {object} utils.APIResponse{data=model.UserResponse} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "用户不存在"
 // @Router /users/profile [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := c.GetUint("user_id")
@@ -157,7 +196,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	utils.Success(c, user)
+	utils.Success(c, user.ToResponse())
 }
 
 // GetUserPermissions godoc
@@ -184,42 +223,125 @@ func (h *UserHandler) GetUserPermissions(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary 更新用户资料
-// @Description 更新当前用户的个人资料
+// @Description 更新当前用户的邮箱、显示名称、手机号、部门、语言、时区和个人偏好设置，未传的字段不修改；用户名、角色和状态只能由管理员修改
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param user body model.UpdateUserRequest true "User update data"
-// @Success 200 {object} model.User
-// @Failure 400 {object} map[string]interface{}
-// @Failure 401 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
+// @Param request body model.UpdateProfileRequest true "个人资料"
+// @Success 200 {object} utils.APIResponse{data=model.UserResponse} "更新成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "用户不存在"
+// @Failure 409 {object} utils.APIResponse "邮箱已存在"
 // @Router /users/profile [put]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID := c.GetUint("user_id")
-	var req model.UpdateUserRequest
+	var req model.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(c, "请求参数格式错误")
+		utils.ValidationError(c, err)
 		return
 	}
 
-	user, err := h.userService.Update(userID, &req)
+	user, err := h.userService.UpdateProfile(c.Request.Context(), userID, &req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
 
-	utils.Success(c, user)
+	utils.Success(c, user.ToResponse())
+}
+
+// UploadAvatar godoc
+// @Summary 上传头像
+// @Description 上传当前用户的头像，支持 JPEG、PNG、GIF、WebP 格式（按文件内容识别），最大 2MB，宽高不超过 4096 像素。
+// @Description 图片从中心裁剪为正方形并缩放为 256x256，上传成功后删除旧头像
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "头像图片"
+// @Success 200 {object} utils.APIResponse{data=model.UserResponse} "上传成功"
+// @Failure 400 {object} utils.APIResponse "图片无效"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/profile/avatar [post]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	// 为表单的其他字段和分隔符预留 1MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, model.AvatarMaxFileSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请上传头像图片")
+		return
+	}
+	if fileHeader.Size > model.AvatarMaxFileSize {
+		utils.BadRequest(c, "头像文件不能超过 2MB")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "读取头像文件失败")
+		return
+	}
+	defer file.Close()
+
+	userID := c.GetUint("user_id")
+	middleware.SetAuditInfo(c, "上传头像", strconv.FormatUint(uint64(userID), 10))
+
+	user, err := h.userService.UploadAvatar(c.Request.Context(), userID, file)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, user.ToResponse())
+}
+
+// ChangePassword godoc
+// @Summary 修改密码
+// @Description 当前用户修改密码，新密码需符合密码策略且不能重复使用最近的旧密码；修改后吊销当前会话以外的所有会话，被要求修改密码的用户修改后即可访问其他接口
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ChangePasswordRequest true "原密码和新密码"
+// @Success 200 {object} utils.APIResponse "修改成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误、原密码错误或新密码不符合密码策略"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/profile/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	if err := h.userService.ChangePassword(c.Request.Context(), c.GetUint("user_id"), c.GetString("session_id"), &req); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "密码修改成功", nil)
 }
 
 // ListUsers godoc
 // @Summary 获取用户列表
-// @Description 分页获取用户列表（需要认证）
+// @Description 按条件分页获取当前用户可见的用户列表（需要认证）
 // @Tags users
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
+// @Param keyword query string false "用户名或邮箱关键字"
+// @Param status query string false "状态" Enums(active, inactive, suspended, pending, locked)
+// @Param role query string false "角色代码"
+// @Param created_by query int false "创建者用户ID"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, username, email, role, status, created_at, updated_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
 // @Success 200 {object} utils.PaginatedResponse{data=[]model.UserResponse} "获取成功"
 // @Failure 400 {object} utils.APIResponse "请求参数错误"
 // @Failure 401 {object} utils.APIResponse "未授权"
@@ -227,48 +349,35 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	// 获取当前用户信息
-	currentUserID := c.GetUint("user_id")
-	currentUserRole := c.GetString("role")
-
-	// 如果没有角色信息，从用户服务获取
-	if currentUserRole == "" {
-		user, err := h.userService.GetByID(currentUserID)
-		if err != nil {
-			utils.HandleError(c, err)
-			return
-		}
-		currentUserRole = user.Role
-	}
-
-	// 解析查询参数
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	currentUserID, currentUserRole, err := h.currentUser(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
 	}
 
-	pageSize, err := strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 {
-		pageSize = 10
+	// 解析查询参数（每页最多 50 条）
+	var query model.UserListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
 	}
-	if pageSize > 50 { // 限制最大每页数量
-		pageSize = 50
+	if err := query.Normalize(model.UserSortFields, 50); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
 	}
 
 	// 调用服务层的 ListWithVisibility 方法（带可见性控制）
-	users, total, err := h.userService.ListWithVisibility(currentUserID, currentUserRole, page, pageSize)
+	users, total, err := h.userService.ListWithVisibility(c.Request.Context(), currentUserID, currentUserRole, &query)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
 	}
 
 	// 计算分页信息
-	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	totalPages := int((total + int64(query.PageSize) - 1) / int64(query.PageSize))
 	pagination := utils.PaginationMeta{
-		Page:       page,
-		PageSize:   pageSize,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
 		Total:      total,
 		TotalPages: totalPages,
 	}
@@ -276,6 +385,20 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	utils.PaginatedSuccess(c, users, pagination)
 }
 
+// currentUser 获取当前用户的ID和角色，上下文中没有角色信息时从用户服务获取
+func (h *UserHandler) currentUser(c *gin.Context) (uint, string, error) {
+	currentUserID := c.GetUint("user_id")
+	currentUserRole := c.GetString("role")
+	if currentUserRole == "" {
+		user, err := h.userService.GetByID(currentUserID)
+		if err != nil {
+			return 0, "", err
+		}
+		currentUserRole = user.Role
+	}
+	return currentUserID, currentUserRole, nil
+}
+
 // CreateUser godoc
 // @Summary 创建用户
 // @Description 创建新用户（仅管理员）
@@ -308,6 +431,191 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	utils.Created(c, user)
 }
 
+// ImportUsers godoc
+// @Summary 批量导入用户
+// @Description 上传 CSV 或 XLSX 文件批量创建用户。表头必须包含 username、email、password 列，可选 role、must_change_password 列。
+// @Description 每行按创建用户的规则校验（格式、用户名和邮箱唯一、角色存在、密码策略），默认只校验并返回逐行错误报告；
+// @Description dry_run=false 时在一个事务中导入，存在任一错误行时不导入任何用户
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "用户文件（csv 或 xlsx，最大 5MB，最多 1000 行）"
+// @Param dry_run formData bool false "只校验不导入" default(true)
+// @Success 200 {object} utils.APIResponse{data=model.UserImportReport} "校验或导入结果"
+// @Failure 400 {object} utils.APIResponse "文件无效"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/import [post]
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	// 须在读取任何表单字段之前限制请求体大小，为表单的其他字段和分隔符预留 1MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, model.UserImportMaxFileSize+1<<20)
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "true"))
+	if err != nil {
+		utils.BadRequest(c, "dry_run 只能为 true 或 false")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "请上传导入文件")
+		return
+	}
+	if fileHeader.Size > model.UserImportMaxFileSize {
+		utils.BadRequest(c, "导入文件不能超过 5MB")
+		return
+	}
+	format, err := utils.TableFormatFromFilename(fileHeader.Filename)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.BadRequest(c, "读取导入文件失败")
+		return
+	}
+	defer file.Close()
+
+	table, err := utils.ReadTable(file, format)
+	if err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	if dryRun {
+		middleware.SetAuditInfo(c, "校验批量导入用户", fileHeader.Filename)
+	} else {
+		middleware.SetAuditInfo(c, "批量导入用户", fileHeader.Filename)
+	}
+
+	report, err := h.userService.ImportUsers(c.Request.Context(), table, dryRun, c.GetUint("user_id"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	message := "校验完成"
+	switch {
+	case report.Imported > 0:
+		message = fmt.Sprintf("成功导入%d个用户", report.Imported)
+	case !dryRun:
+		message = "存在校验未通过的行，未导入任何用户"
+	}
+	utils.SuccessWithMessage(c, message, report)
+}
+
+// BatchUsers godoc
+// @Summary 批量操作用户
+// @Description 对多个用户执行同一操作：status 修改账户状态、delete 删除、assign_role 设置角色、remove_role 移除角色（恢复为默认角色）、revoke_sessions 强制下线。
+// @Description 每个用户独立执行并返回各自的结果，不能对当前用户执行批量操作；每个操作成功的用户记录一条审计日志
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.UserBatchRequest true "操作类型和用户ID列表（最多 100 个）"
+// @Success 200 {object} utils.APIResponse{data=model.UserBatchResponse} "操作结果"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "角色不存在"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/batch [post]
+func (h *UserHandler) BatchUsers(c *gin.Context) {
+	var req model.UserBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	middleware.SetAuditInfo(c, "批量操作用户: "+req.Operation, "")
+
+	info := service.AuditLogRequestInfo{
+		UserID:    c.GetUint("user_id"),
+		Username:  c.GetString("username"),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		RequestID: c.GetString("request_id"),
+	}
+	response, err := h.userService.BatchOperate(c.Request.Context(), &req, info.UserID, info)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, fmt.Sprintf("成功%d个，失败%d个", response.Succeeded, response.Failed), response)
+}
+
+// ExportUsers godoc
+// @Summary 导出用户
+// @Description 按与用户列表相同的筛选条件和可见性规则导出用户（含角色），最多导出 10000 个用户
+// @Tags users
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "导出格式" Enums(csv, xlsx) default(csv)
+// @Param keyword query string false "用户名或邮箱关键字"
+// @Param status query string false "状态" Enums(active, inactive, suspended, pending, locked)
+// @Param role query string false "角色代码"
+// @Param created_by query int false "创建者用户ID"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, username, email, role, status, created_at, updated_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.APIResponse "请求参数错误或数据量超过上限"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/export [get]
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	format := c.DefaultQuery("format", utils.TableFormatCSV)
+	if format != utils.TableFormatCSV && format != utils.TableFormatXLSX {
+		utils.BadRequest(c, "无效的导出格式，仅支持 csv 和 xlsx")
+		return
+	}
+
+	currentUserID, currentUserRole, err := h.currentUser(c)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	var query model.UserListRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+	if err := query.Normalize(model.UserSortFields, 50); err != nil {
+		utils.BadRequest(c, err.Error())
+		return
+	}
+
+	rows, err := h.userService.ExportUsers(c.Request.Context(), currentUserID, currentUserRole, &query)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == utils.TableFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	filename := fmt.Sprintf("users-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	// 响应头已发送，出错时只能中断输出并记录
+	if err := utils.WriteTable(c.Writer, format, service.UserExportHeader, rows); err != nil {
+		logger.FromContext(c.Request.Context()).Error("写入用户导出文件失败",
+			zap.String("format", format),
+			zap.Error(err))
+	}
+}
+
 // UpdateUser godoc
 // @Summary 更新用户信息
 // @Description 更新指定用户的信息
@@ -337,7 +645,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Update(uint(id), &req)
+	user, err := h.userService.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -346,6 +654,45 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	utils.Success(c, user)
 }
 
+// UpdateUserStatus godoc
+// @Summary 更新账户状态
+// @Description 更新账户状态、过期时间和下次登录必须修改密码标记，账户被禁用、暂停或过期时立即吊销其所有会话
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body model.UpdateUserStatusRequest true "账户状态"
+// @Success 200 {object} utils.APIResponse{data=model.UserResponse} "更新成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "用户不存在"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/{id}/status [put]
+func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	var req model.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(c, err)
+		return
+	}
+
+	middleware.SetAuditInfo(c, "更新账户状态", c.Param("id"))
+
+	user, err := h.userService.UpdateStatus(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "账户状态已更新", user.ToResponse())
+}
+
 // CheckUsernameAvailable godoc
 // @Summary 检查用户名可用性
 // @Description 检查用户名是否可用于注册
@@ -460,7 +807,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = h.userService.Delete(uint(id))
+	err = h.userService.Delete(c.Request.Context(), uint(id))
 	if err != nil {
 		utils.HandleError(c, err)
 		return
@@ -497,4 +844,219 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	}
 
 	utils.Success(c, user)
-}
\ No newline at end of file
+}
+
+// ListMySessions godoc
+// @Summary 获取我的登录会话
+// @Description 获取当前用户在各设备上的登录会话列表
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=[]model.SessionResponse} "获取成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/sessions [get]
+func (h *UserHandler) ListMySessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	sessionID := c.GetString("session_id")
+
+	sessions, err := h.userService.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, sessions)
+}
+
+// RevokeMySession godoc
+// @Summary 注销我的指定会话
+// @Description 远程登出当前用户的指定设备会话
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} utils.APIResponse "注销成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "会话不存在"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/sessions/{session_id} [delete]
+func (h *UserHandler) RevokeMySession(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if err := h.userService.RevokeSession(c.Request.Context(), userID, c.Param("session_id")); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已注销", nil)
+}
+
+// RevokeMyOtherSessions godoc
+// @Summary 注销我的其他会话
+// @Description 注销当前用户除当前会话以外的所有会话
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=model.RevokeSessionsResponse} "注销成功"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/sessions [delete]
+func (h *UserHandler) RevokeMyOtherSessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	sessionID := c.GetString("session_id")
+
+	revoked, err := h.userService.RevokeAllSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, model.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ListUserSessions godoc
+// @Summary 获取用户的登录会话
+// @Description 管理员获取指定用户的登录会话列表
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse{data=[]model.SessionResponse} "获取成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/{id}/sessions [get]
+func (h *UserHandler) ListUserSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	sessions, err := h.userService.ListSessions(c.Request.Context(), uint(id), c.GetString("session_id"))
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, sessions)
+}
+
+// RevokeUserSession godoc
+// @Summary 注销用户的指定会话
+// @Description 管理员远程登出指定用户的某个会话
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} utils.APIResponse "注销成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 404 {object} utils.APIResponse "会话不存在"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/{id}/sessions/{session_id} [delete]
+func (h *UserHandler) RevokeUserSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.userService.RevokeSession(c.Request.Context(), uint(id), c.Param("session_id")); err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.SuccessWithMessage(c, "会话已注销", nil)
+}
+
+// RevokeUserSessions godoc
+// @Summary 注销用户的全部会话
+// @Description 管理员强制指定用户在所有设备上登出
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse{data=model.RevokeSessionsResponse} "注销成功"
+// @Failure 400 {object} utils.APIResponse "请求参数错误"
+// @Failure 401 {object} utils.APIResponse "未授权"
+// @Failure 500 {object} utils.APIResponse "服务器内部错误"
+// @Router /users/{id}/sessions [delete]
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "无效的用户ID")
+		return
+	}
+
+	// 管理员操作自己时保留当前会话
+	exceptSessionID := ""
+	if uint(id) == c.GetUint("user_id") {
+		exceptSessionID = c.GetString("session_id")
+	}
+
+	revoked, err := h.userService.RevokeAllSessions(c.Request.Context(), uint(id), exceptSessionID)
+	if err != nil {
+		utils.HandleError(c, err)
+		return
+	}
+
+	utils.Success(c, model.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ListDeletedUsers godoc
+// @Summary 获取回收站中的用户
+// @Description 分页获取已删除的用户，默认按删除时间倒序
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param keyword query string false "用户名或邮箱关键字"
+// @Param created_from query string false "创建时间起（RFC3339）"
+// @Param created_to query string false "创建时间止（RFC3339）"
+// @Param sort_by query string false "排序字段" Enums(id, username, email, role, status, created_at, updated_at, deleted_at)
+// @Param sort_order query string false "排序方向" Enums(asc, desc)
+// @Success 200 {object} utils.PaginatedResponse{data=[]model.UserResponse}
+// @Failure 400 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /users/deleted [get]
+func (h *UserHandler) ListDeletedUsers(c *gin.Context) {
+	listDeleted(c, model.UserSortFields, h.userService.ListDeleted)
+}
+
+// RestoreUser godoc
+// @Summary 恢复用户
+// @Description 恢复回收站中的用户
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	restoreDeleted(c, "用户", h.userService.Restore)
+}
+
+// PurgeUser godoc
+// @Summary 彻底删除用户
+// @Description 彻底删除回收站中的用户，同时删除其角色关联、Casbin 用户-角色关系和所有会话，之后用户名和邮箱可以被重新注册
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Failure 500 {object} utils.APIResponse
+// @Router /users/{id}/purge [delete]
+func (h *UserHandler) PurgeUser(c *gin.Context) {
+	purgeDeleted(c, "用户", func(id uint) error {
+		return h.userService.Purge(c.Request.Context(), id)
+	})
+}
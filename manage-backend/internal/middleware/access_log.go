@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccessLog 基于 zap 的访问日志中间件，替代 gin.Logger()
+// 需注册在 RequestID 之后，日志从请求 context 中带上 request_id
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(startTime)),
+			zap.String("ip", c.ClientIP()),
+			zap.Int("size", c.Writer.Size()),
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			if id, ok := userID.(uint); ok {
+				fields = append(fields, zap.Uint("user_id", id))
+			}
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		switch {
+		case status >= 500:
+			logger.FromContext(c.Request.Context()).Error("HTTP 请求", fields...)
+		case status >= 400:
+			logger.FromContext(c.Request.Context()).Warn("HTTP 请求", fields...)
+		default:
+			logger.FromContext(c.Request.Context()).Info("HTTP 请求", fields...)
+		}
+	}
+}
@@ -2,14 +2,16 @@ package middleware
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"strings"
 	"time"
 
+	"github.com/casbin/casbin/v2/util"
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 // AuditLogConfig 审计日志配置
@@ -19,6 +21,31 @@ type AuditLogConfig struct {
 	MaxBodySize     int      // 最大请求体大小（字节）
 	SkipPaths       []string // 跳过的路径
 	SensitivePaths  []string // 敏感路径（强制记录）
+	RedactFields    []string // 需要脱敏的 JSON 字段名（不区分大小写，任意嵌套层级）
+	RedactRules     []AuditRedactRule // 按路径追加的脱敏规则
+}
+
+// AuditRedactRule 按路径配置的请求体脱敏规则
+type AuditRedactRule struct {
+	Path     string   // 请求路径（支持 keyMatch2 通配，如 /api/v1/users/:id）
+	Fields   []string // 该路径下额外需要脱敏的字段名
+	DropBody bool     // 是否完全不记录该路径的请求体
+}
+
+// RedactedValue 脱敏后的占位值
+const RedactedValue = "******"
+
+// 处理器写入上下文、用于覆盖自动生成的审计日志字段的键
+const (
+	AuditActionKey     = "audit_action"
+	AuditResourceIDKey = "audit_resource_id"
+)
+
+// SetAuditInfo 设置本次请求审计日志的操作描述和资源 ID
+// 用于需要比自动生成的描述更明确的管理操作，或路由参数不是 :id 的资源
+func SetAuditInfo(c *gin.Context, action, resourceID string) {
+	c.Set(AuditActionKey, action)
+	c.Set(AuditResourceIDKey, resourceID)
 }
 
 // DefaultAuditLogConfig 默认审计日志配置
@@ -36,11 +63,30 @@ func DefaultAuditLogConfig() AuditLogConfig {
 			"/api/v1/roles",
 			"/api/v1/permissions",
 		},
+		RedactFields: []string{
+			"password",
+			"old_password",
+			"new_password",
+			"confirm_password",
+			"token",
+			"access_token",
+			"refresh_token",
+			"challenge_token",
+			"captcha_code",
+			"secret",
+			"recovery_codes",
+		},
+		RedactRules: []AuditRedactRule{
+			// 两步验证码与恢复码共用 code 字段
+			{Path: "/api/v1/auth/2fa/verify", Fields: []string{"code"}},
+			{Path: "/api/v1/users/profile/2fa/confirm", Fields: []string{"code"}},
+		},
 	}
 }
 
 // AuditLogger 审计日志中间件
-func AuditLogger(db *gorm.DB, config AuditLogConfig) gin.HandlerFunc {
+// 审计日志通过 writer 异步批量写入，不阻塞请求（队列已满时按 writer 的溢出策略处理）
+func AuditLogger(writer *AuditWriter, config AuditLogConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 检查是否启用
 		if !config.Enabled {
@@ -65,11 +111,13 @@ func AuditLogger(db *gorm.DB, config AuditLogConfig) gin.HandlerFunc {
 		if config.LogRequestBody && shouldLogBody(c.Request.Method) {
 			bodyBytes, err := io.ReadAll(c.Request.Body)
 			if err == nil {
+				// 先脱敏再截断，截断后的 JSON 无法解析
+				redacted := redactRequestBody(bodyBytes, c.ContentType(), path, config)
 				// 限制大小
-				if len(bodyBytes) <= config.MaxBodySize {
-					requestBody = string(bodyBytes)
+				if len(redacted) <= config.MaxBodySize {
+					requestBody = redacted
 				} else {
-					requestBody = string(bodyBytes[:config.MaxBodySize]) + "...(truncated)"
+					requestBody = redacted[:config.MaxBodySize] + "...(truncated)"
 				}
 				// 恢复请求体供后续使用
 				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
@@ -77,7 +125,7 @@ func AuditLogger(db *gorm.DB, config AuditLogConfig) gin.HandlerFunc {
 		}
 
 		// 创建自定义 ResponseWriter 以捕获状态码
-		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		blw := &bodyLogWriter{ResponseWriter: c.Writer}
 		c.Writer = blw
 
 		// 处理请求
@@ -117,14 +165,21 @@ func AuditLogger(db *gorm.DB, config AuditLogConfig) gin.HandlerFunc {
 			ErrorMsg:    errorMsg,
 			RequestBody: requestBody,
 			Duration:    duration,
+			RequestID:   c.GetString("request_id"),
+		}
+		if action := c.GetString(AuditActionKey); action != "" {
+			auditLog.Action = action
+		}
+		if resourceID := c.GetString(AuditResourceIDKey); resourceID != "" {
+			auditLog.ResourceID = resourceID
 		}
 
-		// 异步保存审计日志
-		go saveAuditLog(db, auditLog)
+		// 加入批量写入队列
+		writer.Write(auditLog)
 
 		// 记录到应用日志（敏感操作或失败请求）
 		if isSensitiveOperation(path, config.SensitivePaths) || blw.status >= 400 {
-			logger.Info("审计日志",
+			logger.FromContext(c.Request.Context()).Info("审计日志",
 				zap.Uint("user_id", userID),
 				zap.String("username", username),
 				zap.String("action", auditLog.Action),
@@ -138,17 +193,12 @@ func AuditLogger(db *gorm.DB, config AuditLogConfig) gin.HandlerFunc {
 }
 
 // bodyLogWriter 自定义 ResponseWriter 以捕获状态码
+// 不缓存响应体，避免流式导出等大响应占用内存
 type bodyLogWriter struct {
 	gin.ResponseWriter
-	body   *bytes.Buffer
 	status int
 }
 
-func (w *bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
-}
-
 func (w *bodyLogWriter) WriteHeader(statusCode int) {
 	w.status = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
@@ -159,6 +209,68 @@ func shouldLogBody(method string) bool {
 	return method == "POST" || method == "PUT" || method == "PATCH"
 }
 
+// redactRequestBody 对请求体进行脱敏，返回可安全写入审计日志的内容
+// 非 JSON 请求体（表单、文件上传等）无法可靠识别敏感字段，直接丢弃
+func redactRequestBody(body []byte, contentType, path string, config AuditLogConfig) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	if contentType != "" && !strings.Contains(contentType, "json") {
+		return ""
+	}
+
+	fields := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		fields[strings.ToLower(field)] = true
+	}
+	for _, rule := range config.RedactRules {
+		if !util.KeyMatch2(path, rule.Path) {
+			continue
+		}
+		if rule.DropBody {
+			return ""
+		}
+		for _, field := range rule.Fields {
+			fields[strings.ToLower(field)] = true
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return ""
+	}
+
+	redacted, err := json.Marshal(redactValue(data, fields))
+	if err != nil {
+		return ""
+	}
+	return string(redacted)
+}
+
+// redactValue 递归遍历 JSON 值，将命中的字段替换为占位值
+func redactValue(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if fields[strings.ToLower(key)] {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactValue(item, fields)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, fields)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
 // buildAction 构建操作描述
 func buildAction(method, path string) string {
 	action := method + " " + path
@@ -238,18 +350,8 @@ func isSensitiveOperation(path string, sensitivePaths []string) bool {
 	return false
 }
 
-// saveAuditLog 保存审计日志到数据库
-func saveAuditLog(db *gorm.DB, auditLog *model.AuditLog) {
-	if err := db.Create(auditLog).Error; err != nil {
-		logger.Error("保存审计日志失败",
-			zap.Uint("user_id", auditLog.UserID),
-			zap.String("action", auditLog.Action),
-			zap.Error(err))
-	}
-}
-
 // AuditAction 手动记录审计日志（用于非 HTTP 操作）
-func AuditAction(db *gorm.DB, userID uint, username, action, resource string) {
+func AuditAction(writer *AuditWriter, userID uint, username, action, resource string) {
 	auditLog := &model.AuditLog{
 		UserID:   userID,
 		Username: username,
@@ -258,7 +360,7 @@ func AuditAction(db *gorm.DB, userID uint, username, action, resource string) {
 		Status:   200,
 	}
 	
-	go saveAuditLog(db, auditLog)
+	writer.Write(auditLog)
 	
 	logger.Info("审计日志",
 		zap.Uint("user_id", userID),
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRedactRequestBody(t *testing.T) {
+	config := DefaultAuditLogConfig()
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		path        string
+		want        map[string]interface{}
+	}{
+		{
+			name:        "login password and captcha",
+			body:        `{"username":"admin","password":"secret123","captcha_code":"AB12"}`,
+			contentType: "application/json",
+			path:        "/api/v1/auth/login",
+			want: map[string]interface{}{
+				"username":     "admin",
+				"password":     RedactedValue,
+				"captcha_code": RedactedValue,
+			},
+		},
+		{
+			name:        "nested fields and case insensitive",
+			body:        `{"user":{"Password":"x","profile":[{"refresh_token":"t"}]},"age":18}`,
+			contentType: "application/json; charset=utf-8",
+			path:        "/api/v1/users",
+			want: map[string]interface{}{
+				"user": map[string]interface{}{
+					"Password": RedactedValue,
+					"profile":  []interface{}{map[string]interface{}{"refresh_token": RedactedValue}},
+				},
+				"age": float64(18),
+			},
+		},
+		{
+			name:        "per-path rule",
+			body:        `{"challenge_token":"c","code":"123456"}`,
+			contentType: "application/json",
+			path:        "/api/v1/auth/2fa/verify",
+			want: map[string]interface{}{
+				"challenge_token": RedactedValue,
+				"code":            RedactedValue,
+			},
+		},
+		{
+			name:        "code kept on other paths",
+			body:        `{"code":"user_status"}`,
+			contentType: "application/json",
+			path:        "/api/v1/dict-types",
+			want:        map[string]interface{}{"code": "user_status"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactRequestBody([]byte(tt.body), tt.contentType, tt.path, config)
+
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(got), &decoded))
+			assert.Equal(t, tt.want, decoded)
+		})
+	}
+}
+
+func TestRedactRequestBodyDropsUnsafeBodies(t *testing.T) {
+	config := DefaultAuditLogConfig()
+	config.RedactRules = append(config.RedactRules, AuditRedactRule{Path: "/api/v1/users/:id/avatar", DropBody: true})
+
+	assert.Empty(t, redactRequestBody([]byte("username=admin&password=123"), "application/x-www-form-urlencoded", "/api/v1/auth/login", config))
+	assert.Empty(t, redactRequestBody([]byte(`{"password":`), "application/json", "/api/v1/auth/login", config))
+	assert.Empty(t, redactRequestBody([]byte(`{"name":"a"}`), "application/json", "/api/v1/users/1/avatar", config))
+}
+
+// memoryAuditLogStore 内存中的审计日志存储
+type memoryAuditLogStore struct {
+	mu   sync.Mutex
+	logs []*model.AuditLog
+}
+
+func (s *memoryAuditLogStore) Create(log *model.AuditLog) error {
+	return s.CreateBatch([]*model.AuditLog{log})
+}
+
+func (s *memoryAuditLogStore) CreateBatch(logs []*model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, logs...)
+	return nil
+}
+
+func TestAuditLoggerUsesHandlerAuditInfo(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	gin.SetMode(gin.TestMode)
+
+	store := &memoryAuditLogStore{}
+	writer := NewAuditWriter(store, DefaultAuditWriterConfig())
+
+	router := gin.New()
+	router.Use(AuditLogger(writer, DefaultAuditLogConfig()))
+	router.DELETE("/api/v1/login-locks/accounts/:username", func(c *gin.Context) {
+		SetAuditInfo(c, "解锁账户", c.Param("username"))
+		c.Status(http.StatusOK)
+	})
+	router.DELETE("/api/v1/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/login-locks/accounts/alice", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/users/7", nil))
+	require.NoError(t, writer.Close(context.Background()))
+
+	require.Len(t, store.logs, 2)
+	assert.Equal(t, "解锁账户", store.logs[0].Action)
+	assert.Equal(t, "alice", store.logs[0].ResourceID)
+	assert.Equal(t, "login-locks", store.logs[0].Resource)
+	assert.Equal(t, "删除资源: /api/v1/users/7", store.logs[1].Action)
+	assert.Equal(t, "7", store.logs[1].ResourceID)
+}
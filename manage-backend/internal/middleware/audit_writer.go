@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 队列已满时的处理策略
+const (
+	AuditOverflowDrop  = "drop"  // 丢弃新的审计日志，不阻塞请求
+	AuditOverflowBlock = "block" // 阻塞请求直到队列有空位
+)
+
+// AuditWriterConfig 审计日志批量写入配置
+type AuditWriterConfig struct {
+	QueueSize      int           // 队列容量
+	BatchSize      int           // 单次批量写入的最大条数
+	FlushInterval  time.Duration // 未攒满一批时的最长等待时间
+	OverflowPolicy string        // 队列已满时的处理策略: drop, block
+}
+
+// DefaultAuditWriterConfig 默认审计日志批量写入配置
+func DefaultAuditWriterConfig() AuditWriterConfig {
+	return AuditWriterConfig{
+		QueueSize:      1000,
+		BatchSize:      100,
+		FlushInterval:  time.Second,
+		OverflowPolicy: AuditOverflowDrop,
+	}
+}
+
+// AuditLogStore 审计日志存储（由审计日志仓库实现，负责维护哈希链）
+type AuditLogStore interface {
+	Create(log *model.AuditLog) error
+	CreateBatch(logs []*model.AuditLog) error
+}
+
+// AuditWriterStats 审计日志写入统计
+type AuditWriterStats struct {
+	Written uint64 `json:"written"` // 已写入数据库的条数
+	Dropped uint64 `json:"dropped"` // 因队列已满或已关闭而丢弃的条数
+	Failed  uint64 `json:"failed"`  // 写入数据库失败的条数
+	Queued  int    `json:"queued"`  // 当前排队中的条数
+}
+
+// AuditWriter 审计日志批量写入器
+// 请求只负责入队，由单个后台协程按数量或时间批量写入数据库
+type AuditWriter struct {
+	store  AuditLogStore
+	config AuditWriterConfig
+	queue  chan *model.AuditLog
+	done   chan struct{}
+
+	mu     sync.RWMutex // 保护 closed 与 queue 的关闭
+	closed bool
+
+	written atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+// NewAuditWriter 创建审计日志批量写入器并启动后台协程
+func NewAuditWriter(store AuditLogStore, config AuditWriterConfig) *AuditWriter {
+	defaults := DefaultAuditWriterConfig()
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+
+	w := &AuditWriter{
+		store:  store,
+		config: config,
+		queue:  make(chan *model.AuditLog, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 将审计日志加入写入队列
+// drop 策略下队列已满时立即丢弃；block 策略下等待队列空出位置
+func (w *AuditWriter) Write(auditLog *model.AuditLog) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.drop(auditLog, "审计日志写入器已关闭")
+		return
+	}
+
+	if w.config.OverflowPolicy == AuditOverflowBlock {
+		w.queue <- auditLog
+		return
+	}
+
+	select {
+	case w.queue <- auditLog:
+	default:
+		w.drop(auditLog, "审计日志队列已满")
+	}
+}
+
+// Stats 返回写入统计
+func (w *AuditWriter) Stats() AuditWriterStats {
+	return AuditWriterStats{
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+		Queued:  len(w.queue),
+	}
+}
+
+// Close 停止接收新的审计日志，并等待队列中剩余的日志写入完成
+// ctx 到期时直接返回，未写入的日志随进程退出丢失
+func (w *AuditWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		stats := w.Stats()
+		logger.FromContext(ctx).Info("审计日志写入器已关闭",
+			zap.Uint64("written", stats.Written),
+			zap.Uint64("dropped", stats.Dropped),
+			zap.Uint64("failed", stats.Failed))
+		return nil
+	case <-ctx.Done():
+		logger.FromContext(ctx).Warn("审计日志写入器关闭超时", zap.Int("queued", len(w.queue)))
+		return ctx.Err()
+	}
+}
+
+// run 后台写入协程：攒满 BatchSize 或到达 FlushInterval 时写入一批
+func (w *AuditWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*model.AuditLog, 0, w.config.BatchSize)
+	for {
+		select {
+		case auditLog, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, auditLog)
+			if len(batch) >= w.config.BatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush 批量写入一批审计日志
+// 批量写入失败时逐条重试，避免单条异常数据导致整批丢失
+func (w *AuditWriter) flush(batch []*model.AuditLog) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := w.store.CreateBatch(batch)
+	if err == nil {
+		w.written.Add(uint64(len(batch)))
+		return
+	}
+	logger.Warn("批量保存审计日志失败，改为逐条写入", zap.Int("count", len(batch)), zap.Error(err))
+
+	for _, auditLog := range batch {
+		auditLog.ID = 0
+		if err := w.store.Create(auditLog); err != nil {
+			w.failed.Add(1)
+			logger.Error("保存审计日志失败",
+				zap.Uint("user_id", auditLog.UserID),
+				zap.String("action", auditLog.Action),
+				zap.Error(err))
+			continue
+		}
+		w.written.Add(1)
+	}
+}
+
+// drop 丢弃一条审计日志并计数
+// 过载时丢弃量可能很大，只在首次及每 100 条时输出一次告警
+func (w *AuditWriter) drop(auditLog *model.AuditLog, reason string) {
+	dropped := w.dropped.Add(1)
+	if dropped == 1 || dropped%100 == 0 {
+		logger.Warn(reason,
+			zap.Uint64("dropped_total", dropped),
+			zap.String("action", auditLog.Action),
+			zap.String("path", auditLog.Path))
+	}
+}
@@ -2,18 +2,23 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 )
 
 // SessionServiceInterface 会话服务接口（用于中间件扩展）
 // 提供了 Token 黑名单检测和用户活跃状态更新的方法
 type SessionServiceInterface interface {
 	IsTokenBlacklisted(ctx context.Context, jti string) bool // 判断 Token 是否在黑名单中
-	UpdateLastActivity(ctx context.Context, userID uint) error // 更新用户最后活跃时间
+	UpdateLastActivity(ctx context.Context, userID uint, sessionID string) error // 更新会话最后活跃时间，会话不存在时返回 redis.Nil
 	SetUserActive(ctx context.Context, userID uint) error      // 设置用户为活跃状态
 }
 
@@ -51,9 +56,9 @@ func JWTAuthWithSession(jwtManager *auth.JWTManager, sessionService SessionServi
 			return
 		}
 
-		// 如果启用了会话服务，则检查黑名单和更新用户状态
+		// 如果启用了会话服务，则检查黑名单、会话是否仍然存在并更新用户状态
 		if sessionService != nil {
-			ctx := context.Background()
+			ctx := c.Request.Context()
 
 			// 检查 Token 是否已被拉黑
 			if sessionService.IsTokenBlacklisted(ctx, claims.JTI) {
@@ -62,8 +67,21 @@ func JWTAuthWithSession(jwtManager *auth.JWTManager, sessionService SessionServi
 				return
 			}
 
-			// 更新用户活跃状态
-			sessionService.UpdateLastActivity(ctx, claims.UserID)
+			// 会话已被吊销（远程注销、强制下线、账户停用或删除等）时，其签发的访问令牌立即失效；
+			// 无法确认会话是否存在时同样拒绝，避免会话存储故障期间已停用的账户继续访问
+			if err := sessionService.UpdateLastActivity(ctx, claims.UserID, claims.SessionID); err != nil {
+				if errors.Is(err, redis.Nil) {
+					utils.Unauthorized(c, "会话已失效，请重新登录")
+				} else {
+					logger.FromContext(ctx).Error("校验会话失败，拒绝请求",
+						zap.Uint("user_id", claims.UserID),
+						zap.String("session_id", claims.SessionID),
+						zap.Error(err))
+					utils.HandleError(c, apperrors.NewSessionServiceUnavailableError())
+				}
+				c.Abort()
+				return
+			}
 			sessionService.SetUserActive(ctx, claims.UserID)
 		}
 
@@ -72,6 +90,7 @@ func JWTAuthWithSession(jwtManager *auth.JWTManager, sessionService SessionServi
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
 		c.Set("jti", claims.JTI)
+		c.Set("session_id", claims.SessionID)
 		c.Set("access_token", tokenString)
 
 		c.Next()
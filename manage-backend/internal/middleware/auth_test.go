@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeAuthSessionService 内存中的会话服务，记录仍然存在的会话和已拉黑的令牌，err 不为空时模拟 Redis 不可用
+type fakeAuthSessionService struct {
+	sessions    map[string]bool
+	blacklisted map[string]bool
+	err         error
+}
+
+func (s *fakeAuthSessionService) IsTokenBlacklisted(ctx context.Context, jti string) bool {
+	return s.blacklisted[jti]
+}
+
+func (s *fakeAuthSessionService) UpdateLastActivity(ctx context.Context, userID uint, sessionID string) error {
+	if s.err != nil {
+		return s.err
+	}
+	if sessionID != "" && !s.sessions[sessionID] {
+		return redis.Nil
+	}
+	return nil
+}
+
+func (s *fakeAuthSessionService) SetUserActive(ctx context.Context, userID uint) error {
+	return nil
+}
+
+func TestJWTAuthWithSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop()
+	jwtManager := auth.NewJWTManager("test-secret", 15, 24)
+	tokenPair, err := jwtManager.GenerateTokenPairWithSession(1, "alice", "user", "session-1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		sessions *fakeAuthSessionService
+		want     int
+	}{
+		{"active session", &fakeAuthSessionService{sessions: map[string]bool{"session-1": true}}, http.StatusOK},
+		{"blacklisted token", &fakeAuthSessionService{
+			sessions:    map[string]bool{"session-1": true},
+			blacklisted: map[string]bool{tokenPair.AccessJTI: true},
+		}, http.StatusUnauthorized},
+		{"revoked session", &fakeAuthSessionService{sessions: map[string]bool{}}, http.StatusUnauthorized},
+		{"session store unavailable", &fakeAuthSessionService{err: errors.New("connection refused")}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(JWTAuthWithSession(jwtManager, tt.sessions))
+			router.GET("/ping", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set("Authorization", "Bearer "+tokenPair.AccessToken)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
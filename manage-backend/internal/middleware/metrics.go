@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics HTTP 请求指标中间件
+// 按路由模板（如 /api/v1/users/:id）而不是实际路径统计，避免标签基数过大
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(startTime).Seconds())
+	}
+}
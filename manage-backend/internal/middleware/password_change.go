@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PasswordChangeCheckerInterface 查询用户是否必须先修改密码
+type PasswordChangeCheckerInterface interface {
+	IsPasswordChangeRequired(ctx context.Context, userID uint) (bool, error)
+}
+
+// DefaultPasswordChangeAllowList 必须修改密码时仍可访问的接口（"方法 路由"，路由相对 APIPrefix）
+var DefaultPasswordChangeAllowList = []string{
+	"POST /auth/logout",
+	"GET /users/profile",
+	"PUT /users/profile/password",
+}
+
+// PasswordChangeGuard 必须修改密码中间件，需要在 JWT 认证中间件之后使用
+// 用户被要求修改密码时，除 allowList 中的接口外一律拒绝访问；
+// 无法查询标记时同样拒绝（返回 503），避免会话存储故障时绕过强制修改密码
+func PasswordChangeGuard(checker PasswordChangeCheckerInterface, apiPrefix string, allowList []string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(allowList))
+	for _, entry := range allowList {
+		allowed[entry] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		if userID == 0 {
+			c.Next()
+			return
+		}
+
+		route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), apiPrefix)
+		if _, ok := allowed[route]; ok {
+			c.Next()
+			return
+		}
+
+		required, err := checker.IsPasswordChangeRequired(c.Request.Context(), userID)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("查询必须修改密码标记失败，拒绝请求",
+				zap.Uint("user_id", userID),
+				zap.Error(err))
+			utils.HandleError(c, apperrors.NewSessionServiceUnavailableError())
+			c.Abort()
+			return
+		}
+		if !required {
+			c.Next()
+			return
+		}
+
+		utils.HandleError(c, apperrors.NewPasswordChangeRequiredError())
+		c.Abort()
+	}
+}
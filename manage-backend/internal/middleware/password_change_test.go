@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakePasswordChangeChecker 记录必须修改密码的用户，err 不为空时模拟 Redis 不可用
+type fakePasswordChangeChecker struct {
+	required map[uint]bool
+	err      error
+}
+
+func (f fakePasswordChangeChecker) IsPasswordChangeRequired(ctx context.Context, userID uint) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.required[userID], nil
+}
+
+func newPasswordChangeRouter(checker fakePasswordChangeChecker, userID uint) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+	})
+	api.Use(PasswordChangeGuard(checker, "/api/v1", DefaultPasswordChangeAllowList))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api.GET("/users/profile", ok)
+	api.PUT("/users/profile", ok)
+	api.PUT("/users/profile/password", ok)
+	api.GET("/users/:id", ok)
+	return router
+}
+
+func TestPasswordChangeGuard(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	checker := fakePasswordChangeChecker{required: map[uint]bool{1: true}}
+	unavailable := fakePasswordChangeChecker{err: errors.New("connection refused")}
+
+	tests := []struct {
+		name    string
+		checker fakePasswordChangeChecker
+		userID  uint
+		method  string
+		path    string
+		want    int
+	}{
+		{"flagged user can change password", checker, 1, http.MethodPut, "/api/v1/users/profile/password", http.StatusOK},
+		{"flagged user can read profile", checker, 1, http.MethodGet, "/api/v1/users/profile", http.StatusOK},
+		{"flagged user cannot update profile", checker, 1, http.MethodPut, "/api/v1/users/profile", http.StatusForbidden},
+		{"flagged user cannot access other endpoints", checker, 1, http.MethodGet, "/api/v1/users/2", http.StatusForbidden},
+		{"normal user not affected", checker, 2, http.MethodGet, "/api/v1/users/2", http.StatusOK},
+		{"rejected when flag cannot be checked", unavailable, 2, http.MethodGet, "/api/v1/users/2", http.StatusServiceUnavailable},
+		{"allow list still reachable when flag cannot be checked", unavailable, 1, http.MethodPut, "/api/v1/users/profile/password", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newPasswordChangeRouter(tt.checker, tt.userID)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -2,22 +2,61 @@ package middleware
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
 	"github.com/gin-gonic/gin"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
 	"go.uber.org/zap"
 )
 
-// CasbinEnforcer Casbin 权限中间件
+// CasbinConfig Casbin 鉴权中间件配置
+type CasbinConfig struct {
+	APIPrefix       string   // 路由组前缀，如 /api/v1
+	SuperAdminRoles []string // 超级管理员角色代码，拥有该角色的用户跳过鉴权
+	AllowList       []string // 登录即可访问的路径（相对 APIPrefix，支持 keyMatch2 通配）
+	PolicyPrefixes  []string // 策略路径可能使用的前缀，依次尝试匹配
+}
+
+// DefaultCasbinConfig 默认 Casbin 鉴权配置
+func DefaultCasbinConfig() CasbinConfig {
+	return CasbinConfig{
+		APIPrefix:       "/api/v1",
+		SuperAdminRoles: []string{"admin"},
+		AllowList: []string{
+			"/auth/logout",
+			"/users/profile",
+			"/users/profile/password",
+			"/users/profile/avatar",
+			"/users/permissions",
+			"/users/sessions",
+			"/users/sessions/:session_id",
+			"/users/profile/2fa",
+			"/users/profile/2fa/*",
+			"/menus/user",
+		},
+		// 历史策略中同时存在 /api/v1/users、/api/users 和 /users 三种写法
+		PolicyPrefixes: []string{"/api/v1", "/api", ""},
+	}
+}
+
+// CasbinEnforcer Casbin 权限中间件（使用默认配置）
 // 使用 Casbin 进行权限检查，需要先通过 JWT 认证中间件
 func CasbinEnforcer(enforcer *casbin.Enforcer) gin.HandlerFunc {
+	return CasbinEnforcerWithConfig(enforcer, DefaultCasbinConfig())
+}
+
+// CasbinEnforcerWithConfig 带配置的 Casbin 权限中间件
+// 检查顺序：白名单 -> 超级管理员 -> role:* 策略
+func CasbinEnforcerWithConfig(enforcer *casbin.Enforcer, config CasbinConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取用户ID（由 JWT 中间件设置）
 		userID, exists := c.Get("user_id")
 		if !exists {
-			logger.Warn("权限检查失败：未找到用户ID",
+			logger.FromContext(c.Request.Context()).Warn("权限检查失败：未找到用户ID",
 				zap.String("path", c.Request.URL.Path),
 				zap.String("method", c.Request.Method))
 			utils.Unauthorized(c, "未授权访问")
@@ -27,26 +66,55 @@ func CasbinEnforcer(enforcer *casbin.Enforcer) gin.HandlerFunc {
 
 		// 构建 Casbin 主体
 		sub := fmt.Sprintf("user:%d", userID.(uint))
-		obj := c.Request.URL.Path
+		relPath := strings.TrimPrefix(c.Request.URL.Path, config.APIPrefix)
 		act := c.Request.Method
 
-		// 执行权限检查
-		ok, err := enforcer.Enforce(sub, obj, act)
-		if err != nil {
-			logger.Error("权限检查失败",
-				zap.Uint("user_id", userID.(uint)),
-				zap.String("path", obj),
-				zap.String("method", act),
-				zap.Error(err))
-			utils.InternalServerError(c, "权限检查失败")
-			c.Abort()
+		// 白名单路径：登录用户的自助接口
+		if isAllowListed(relPath, config.AllowList) {
+			c.Next()
 			return
 		}
 
-		if !ok {
-			logger.Warn("权限不足",
+		// 超级管理员直接放行
+		for _, roleCode := range config.SuperAdminRoles {
+			ok, err := enforcer.HasRoleForUser(sub, fmt.Sprintf("role:%s", roleCode))
+			if err == nil && ok {
+				c.Next()
+				return
+			}
+		}
+
+		// 执行权限检查，依次尝试各个策略前缀
+		prefixes := config.PolicyPrefixes
+		if len(prefixes) == 0 {
+			prefixes = []string{config.APIPrefix}
+		}
+		allowed := false
+		for _, prefix := range prefixes {
+			obj := prefix + relPath
+			ok, err := enforcer.Enforce(sub, obj, act)
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Error("权限检查失败",
+					zap.Uint("user_id", userID.(uint)),
+					zap.String("path", obj),
+					zap.String("method", act),
+					zap.Error(err))
+				metrics.CasbinDecisionsTotal.WithLabelValues("error").Inc()
+				utils.InternalServerError(c, "权限检查失败")
+				c.Abort()
+				return
+			}
+			if ok {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			metrics.CasbinDecisionsTotal.WithLabelValues("deny").Inc()
+			logger.FromContext(c.Request.Context()).Warn("权限不足",
 				zap.Uint("user_id", userID.(uint)),
-				zap.String("path", obj),
+				zap.String("path", c.Request.URL.Path),
 				zap.String("method", act))
 			utils.Forbidden(c, "您没有权限访问此资源")
 			c.Abort()
@@ -54,15 +122,26 @@ func CasbinEnforcer(enforcer *casbin.Enforcer) gin.HandlerFunc {
 		}
 
 		// 权限检查通过，继续处理请求
-		logger.Debug("权限检查通过",
+		metrics.CasbinDecisionsTotal.WithLabelValues("allow").Inc()
+		logger.FromContext(c.Request.Context()).Debug("权限检查通过",
 			zap.Uint("user_id", userID.(uint)),
-			zap.String("path", obj),
+			zap.String("path", c.Request.URL.Path),
 			zap.String("method", act))
 
 		c.Next()
 	}
 }
 
+// isAllowListed 判断路径是否在白名单中
+func isAllowListed(path string, allowList []string) bool {
+	for _, pattern := range allowList {
+		if util.KeyMatch2(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 // RequirePermission 要求特定权限的中间件（用于单个路由）
 // 使用方式：router.GET("/api/users", middleware.RequirePermission(enforcer, "user:read"), handler)
 func RequirePermission(enforcer *casbin.Enforcer, permission string) gin.HandlerFunc {
@@ -70,7 +149,7 @@ func RequirePermission(enforcer *casbin.Enforcer, permission string) gin.Handler
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			logger.Warn("权限检查失败：未找到用户ID",
+			logger.FromContext(c.Request.Context()).Warn("权限检查失败：未找到用户ID",
 				zap.String("permission", permission))
 			utils.Unauthorized(c, "未授权访问")
 			c.Abort()
@@ -85,17 +164,19 @@ func RequirePermission(enforcer *casbin.Enforcer, permission string) gin.Handler
 		// 执行权限检查
 		ok, err := enforcer.Enforce(sub, obj, act)
 		if err != nil {
-			logger.Error("权限检查失败",
+			logger.FromContext(c.Request.Context()).Error("权限检查失败",
 				zap.Uint("user_id", userID.(uint)),
 				zap.String("permission", permission),
 				zap.Error(err))
+			metrics.CasbinDecisionsTotal.WithLabelValues("error").Inc()
 			utils.InternalServerError(c, "权限检查失败")
 			c.Abort()
 			return
 		}
 
 		if !ok {
-			logger.Warn("权限不足",
+			metrics.CasbinDecisionsTotal.WithLabelValues("deny").Inc()
+			logger.FromContext(c.Request.Context()).Warn("权限不足",
 				zap.Uint("user_id", userID.(uint)),
 				zap.String("permission", permission),
 				zap.String("path", obj))
@@ -104,6 +185,7 @@ func RequirePermission(enforcer *casbin.Enforcer, permission string) gin.Handler
 			return
 		}
 
+		metrics.CasbinDecisionsTotal.WithLabelValues("allow").Inc()
 		c.Next()
 	}
 }
@@ -115,7 +197,7 @@ func RequireRole(enforcer *casbin.Enforcer, roleCode string) gin.HandlerFunc {
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			logger.Warn("角色检查失败：未找到用户ID",
+			logger.FromContext(c.Request.Context()).Warn("角色检查失败：未找到用户ID",
 				zap.String("required_role", roleCode))
 			utils.Unauthorized(c, "未授权访问")
 			c.Abort()
@@ -129,7 +211,7 @@ func RequireRole(enforcer *casbin.Enforcer, roleCode string) gin.HandlerFunc {
 		// 检查用户是否拥有该角色
 		ok, err := enforcer.HasRoleForUser(sub, role)
 		if err != nil {
-			logger.Error("角色检查失败",
+			logger.FromContext(c.Request.Context()).Error("角色检查失败",
 				zap.Uint("user_id", userID.(uint)),
 				zap.String("required_role", roleCode),
 				zap.Error(err))
@@ -139,7 +221,7 @@ func RequireRole(enforcer *casbin.Enforcer, roleCode string) gin.HandlerFunc {
 		}
 
 		if !ok {
-			logger.Warn("角色不足",
+			logger.FromContext(c.Request.Context()).Warn("角色不足",
 				zap.Uint("user_id", userID.(uint)),
 				zap.String("required_role", roleCode))
 			utils.Forbidden(c, fmt.Sprintf("需要 %s 角色", roleCode))
@@ -147,7 +229,7 @@ func RequireRole(enforcer *casbin.Enforcer, roleCode string) gin.HandlerFunc {
 			return
 		}
 
-		logger.Debug("角色检查通过",
+		logger.FromContext(c.Request.Context()).Debug("角色检查通过",
 			zap.Uint("user_id", userID.(uint)),
 			zap.String("role", roleCode))
 
@@ -162,7 +244,7 @@ func RequireAnyRole(enforcer *casbin.Enforcer, roleCodes []string) gin.HandlerFu
 		// 获取用户ID
 		userID, exists := c.Get("user_id")
 		if !exists {
-			logger.Warn("角色检查失败：未找到用户ID")
+			logger.FromContext(c.Request.Context()).Warn("角色检查失败：未找到用户ID")
 			utils.Unauthorized(c, "未授权访问")
 			c.Abort()
 			return
@@ -177,7 +259,7 @@ func RequireAnyRole(enforcer *casbin.Enforcer, roleCodes []string) gin.HandlerFu
 			role := fmt.Sprintf("role:%s", roleCode)
 			ok, err := enforcer.HasRoleForUser(sub, role)
 			if err != nil {
-				logger.Error("角色检查失败",
+				logger.FromContext(c.Request.Context()).Error("角色检查失败",
 					zap.Uint("user_id", userID.(uint)),
 					zap.String("role", roleCode),
 					zap.Error(err))
@@ -190,7 +272,7 @@ func RequireAnyRole(enforcer *casbin.Enforcer, roleCodes []string) gin.HandlerFu
 		}
 
 		if !hasRole {
-			logger.Warn("角色不足",
+			logger.FromContext(c.Request.Context()).Warn("角色不足",
 				zap.Uint("user_id", userID.(uint)),
 				zap.Strings("required_roles", roleCodes))
 			utils.Forbidden(c, "您没有权限访问此资源")
@@ -198,7 +280,7 @@ func RequireAnyRole(enforcer *casbin.Enforcer, roleCodes []string) gin.HandlerFu
 			return
 		}
 
-		logger.Debug("角色检查通过",
+		logger.FromContext(c.Request.Context()).Debug("角色检查通过",
 			zap.Uint("user_id", userID.(uint)))
 
 		c.Next()
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestEnforcer 使用项目的 RBAC 模型创建内存中的 Enforcer
+// 用户 1 为超级管理员，用户 2 拥有 editor 角色，用户 3 没有任何角色
+func newTestEnforcer(t *testing.T) *casbin.Enforcer {
+	t.Helper()
+	enforcer, err := casbin.NewEnforcer("../../pkg/casbin/model.conf")
+	require.NoError(t, err)
+
+	_, err = enforcer.AddGroupingPolicies([][]string{
+		{"user:1", "role:admin"},
+		{"user:2", "role:editor"},
+	})
+	require.NoError(t, err)
+	// 历史策略中三种前缀写法并存
+	_, err = enforcer.AddPolicies([][]string{
+		{"role:editor", "/api/v1/users", "GET"},
+		{"role:editor", "/api/roles/:id", "(GET|PUT)"},
+		{"role:editor", "/menus/*", "GET"},
+	})
+	require.NoError(t, err)
+	return enforcer
+}
+
+func newCasbinRouter(enforcer *casbin.Enforcer, userID uint) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+	})
+	api.Use(CasbinEnforcer(enforcer))
+	api.Any("/*path", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCasbinEnforcerWithConfig(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	enforcer := newTestEnforcer(t)
+
+	tests := []struct {
+		name   string
+		userID uint
+		method string
+		path   string
+		want   int
+	}{
+		{"missing user", 0, http.MethodGet, "/api/v1/users", http.StatusUnauthorized},
+		{"allow list without policy", 3, http.MethodGet, "/api/v1/users/profile", http.StatusOK},
+		{"allow list wildcard", 3, http.MethodPost, "/api/v1/users/profile/2fa/enable", http.StatusOK},
+		{"super admin bypass", 1, http.MethodDelete, "/api/v1/roles/9", http.StatusOK},
+		{"policy with /api/v1 prefix", 2, http.MethodGet, "/api/v1/users", http.StatusOK},
+		{"policy with /api prefix", 2, http.MethodPut, "/api/v1/roles/3", http.StatusOK},
+		{"policy without prefix", 2, http.MethodGet, "/api/v1/menus/tree", http.StatusOK},
+		{"method not granted", 2, http.MethodDelete, "/api/v1/roles/3", http.StatusForbidden},
+		{"path not granted", 2, http.MethodGet, "/api/v1/audit-logs", http.StatusForbidden},
+		{"user without roles", 3, http.MethodGet, "/api/v1/users", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newCasbinRouter(enforcer, tt.userID).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeRateLimiter 内存中的固定次数限流器，记录每次请求的限流对象
+type fakeRateLimiter struct {
+	rules    map[string]config.RateLimitRule
+	counts   map[string]int
+	subjects []string
+	apiKeys  map[string]string // 已登记的 API Key -> 标识
+	err      error
+}
+
+func (l *fakeRateLimiter) Rule(group string) (config.RateLimitRule, bool) {
+	rule, ok := l.rules[group]
+	return rule, ok
+}
+
+func (l *fakeRateLimiter) APIKeyID(apiKey string) (string, bool) {
+	id, ok := l.apiKeys[apiKey]
+	return id, ok
+}
+
+func (l *fakeRateLimiter) Allow(ctx context.Context, group, subject string) (*model.RateLimitResult, error) {
+	l.subjects = append(l.subjects, subject)
+	if l.err != nil {
+		return nil, l.err
+	}
+	rule := l.rules[group]
+	l.counts[subject]++
+	remaining := rule.Limit - l.counts[subject]
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &model.RateLimitResult{
+		Allowed:   l.counts[subject] <= rule.Limit,
+		Limit:     rule.Limit,
+		Remaining: remaining,
+		ResetIn:   1500 * time.Millisecond,
+	}, nil
+}
+
+func newRateLimitRouter(limiter *fakeRateLimiter, group string, userID uint) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+	})
+	router.Use(RateLimit(limiter, group))
+	router.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRateLimit(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	t.Run("headers and rejection", func(t *testing.T) {
+		limiter := &fakeRateLimiter{
+			rules:  map[string]config.RateLimitRule{"api": {Limit: 2, WindowSeconds: 60, KeyBy: model.RateLimitKeyByIP}},
+			counts: map[string]int{},
+		}
+		router := newRateLimitRouter(limiter, "api", 0)
+
+		var w *httptest.ResponseRecorder
+		for i := 0; i < 3; i++ {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+		assert.NotEmpty(t, w.Header().Get(RateLimitResetHeader))
+		assert.Equal(t, "2", w.Header().Get(RetryAfterHeader))
+	})
+
+	t.Run("key by user", func(t *testing.T) {
+		limiter := &fakeRateLimiter{
+			rules:  map[string]config.RateLimitRule{"api": {Limit: 10, WindowSeconds: 60, KeyBy: model.RateLimitKeyByUser}},
+			counts: map[string]int{},
+		}
+		w := httptest.NewRecorder()
+		newRateLimitRouter(limiter, "api", 7).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "9", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, []string{"user:7"}, limiter.subjects)
+	})
+
+	t.Run("key by api key only trusts registered keys", func(t *testing.T) {
+		limiter := &fakeRateLimiter{
+			rules:   map[string]config.RateLimitRule{"api": {Limit: 10, WindowSeconds: 60, KeyBy: model.RateLimitKeyByAPIKey}},
+			counts:  map[string]int{},
+			apiKeys: map[string]string{"secret-key": "0123456789abcdef"},
+		}
+		withKey := func(apiKey string) *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			req.Header.Set(APIKeyHeader, apiKey)
+			return req
+		}
+
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(httptest.NewRecorder(), withKey("secret-key"))
+		// 随意构造的 Key 不能获得独立的计数
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(httptest.NewRecorder(), withKey("random-1"))
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(httptest.NewRecorder(), withKey("random-2"))
+		newRateLimitRouter(limiter, "api", 7).ServeHTTP(httptest.NewRecorder(), withKey("random-3"))
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Equal(t, []string{"key:0123456789abcdef", "ip:192.0.2.1", "ip:192.0.2.1", "user:7", "ip:192.0.2.1"}, limiter.subjects)
+		assert.Equal(t, 3, limiter.counts["ip:192.0.2.1"])
+	})
+
+	t.Run("group without rule", func(t *testing.T) {
+		limiter := &fakeRateLimiter{rules: map[string]config.RateLimitRule{}, counts: map[string]int{}}
+		w := httptest.NewRecorder()
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(RateLimitLimitHeader))
+		assert.Empty(t, limiter.subjects)
+	})
+
+	t.Run("fail open when redis unavailable", func(t *testing.T) {
+		limiter := &fakeRateLimiter{
+			rules:  map[string]config.RateLimitRule{"api": {Limit: 1, WindowSeconds: 60, KeyBy: model.RateLimitKeyByIP}},
+			counts: map[string]int{},
+			err:    errors.New("connection refused"),
+		}
+		w := httptest.NewRecorder()
+		newRateLimitRouter(limiter, "api", 0).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+
+	var contextID string
+	router.GET("/ping", func(c *gin.Context) {
+		contextID = logger.RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		reuse  bool
+	}{
+		{name: "reuse client id", header: "gateway-1234", reuse: true},
+		{name: "generate when missing", header: ""},
+		{name: "replace invalid id", header: "bad id\nforged=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			responseID := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, responseID)
+			assert.Equal(t, responseID, contextID)
+			if tt.reuse {
+				assert.Equal(t, tt.header, responseID)
+			} else {
+				assert.NotEqual(t, tt.header, responseID)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditLogCheckpoint 审计日志检查点
+// 清理旧审计日志前写入，记录被清理的最后一条日志的哈希并签名，
+// 使剩余日志的第一条仍可通过 PrevHash 追溯到可信的锚点
+type AuditLogCheckpoint struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	LastEntryID   uint      `json:"last_entry_id" gorm:"not null"`                       // 被清理的最后一条日志ID
+	LastEntryHash string    `json:"last_entry_hash" gorm:"size:64;not null;uniqueIndex"` // 被清理的最后一条日志哈希
+	LastEntryAt   time.Time `json:"last_entry_at" gorm:"not null"`                       // 被清理的最后一条日志时间
+	PurgedCount   int64     `json:"purged_count" gorm:"not null"`                        // 本次清理的日志条数
+	Cutoff        time.Time `json:"cutoff" gorm:"not null"`                              // 清理截止时间
+	Signature     string    `json:"signature" gorm:"size:64;not null"`                   // HMAC-SHA256 签名
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AuditLogVerifyResult 审计日志哈希链校验结果
+type AuditLogVerifyResult struct {
+	Valid         bool      `json:"valid"`                     // 是否完整
+	Checked       int64     `json:"checked"`                   // 已校验的日志条数
+	Unchained     int64     `json:"unchained"`                 // 启用哈希链之前的历史日志条数（不参与校验）
+	Anchor        string    `json:"anchor"`                    // 起点锚定方式: genesis, previous_entry, checkpoint
+	FirstBrokenID *uint     `json:"first_broken_id,omitempty"` // 第一条校验失败的日志ID
+	Reason        string    `json:"reason,omitempty"`          // 校验失败原因
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+}
+
+// 哈希链起点锚定方式
+const (
+	AuditAnchorGenesis       = "genesis"        // 链的第一条日志
+	AuditAnchorPreviousEntry = "previous_entry" // 校验范围之前的日志
+	AuditAnchorCheckpoint    = "checkpoint"     // 清理时写入的检查点
+)
+
+// auditLogHashPayload 参与哈希计算的审计日志字段（字段顺序固定）
+type auditLogHashPayload struct {
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+	ResourceID  string `json:"resource_id"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	IP          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+	Status      int    `json:"status"`
+	ErrorMsg    string `json:"error_msg"`
+	RequestBody string `json:"request_body"`
+	Duration    int64  `json:"duration"`
+	CreatedAt   string `json:"created_at"`
+	PrevHash    string `json:"prev_hash"`
+	RequestID   string `json:"request_id,omitempty"` // 为空时不参与序列化，保证新增该字段前的日志哈希不变
+}
+
+// ComputeHash 计算审计日志的 SHA-256 哈希（十六进制）
+// CreatedAt 统一转换为 UTC 并截断到微秒，与数据库 timestamptz(6) 的精度一致
+func (l *AuditLog) ComputeHash() string {
+	payload, _ := json.Marshal(auditLogHashPayload{
+		UserID:      l.UserID,
+		Username:    l.Username,
+		Action:      l.Action,
+		Resource:    l.Resource,
+		ResourceID:  l.ResourceID,
+		Method:      l.Method,
+		Path:        l.Path,
+		IP:          l.IP,
+		UserAgent:   l.UserAgent,
+		Status:      l.Status,
+		ErrorMsg:    l.ErrorMsg,
+		RequestBody: l.RequestBody,
+		Duration:    l.Duration,
+		CreatedAt:   l.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		PrevHash:    l.PrevHash,
+		RequestID:   l.RequestID,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeSignature 使用密钥计算检查点的 HMAC-SHA256 签名（十六进制）
+func (c *AuditLogCheckpoint) ComputeSignature(key []byte) string {
+	payload, _ := json.Marshal(struct {
+		LastEntryID   uint   `json:"last_entry_id"`
+		LastEntryHash string `json:"last_entry_hash"`
+		LastEntryAt   string `json:"last_entry_at"`
+		PurgedCount   int64  `json:"purged_count"`
+		Cutoff        string `json:"cutoff"`
+	}{
+		LastEntryID:   c.LastEntryID,
+		LastEntryHash: c.LastEntryHash,
+		LastEntryAt:   c.LastEntryAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		PurgedCount:   c.PurgedCount,
+		Cutoff:        c.Cutoff.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature 校验检查点签名
+func (c *AuditLogCheckpoint) VerifySignature(key []byte) bool {
+	return hmac.Equal([]byte(c.Signature), []byte(c.ComputeSignature(key)))
+}
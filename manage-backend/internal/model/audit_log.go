@@ -22,6 +22,9 @@ type AuditLog struct {
 	ErrorMsg    string         `json:"error_msg"`
 	RequestBody string         `json:"request_body" gorm:"type:text"`
 	Duration    int64          `json:"duration"` // 请求耗时（毫秒）
+	PrevHash    string         `json:"prev_hash" gorm:"size:64;not null;default:''"` // 上一条审计日志的哈希
+	Hash        string         `json:"hash" gorm:"size:64;not null;default:''"`      // 本条审计日志的哈希（含 PrevHash）
+	RequestID   string         `json:"request_id" gorm:"size:64;not null;default:'';index"` // 请求ID（X-Request-ID），用于关联应用日志
 	CreatedAt   time.Time      `json:"created_at" gorm:"index"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -42,6 +45,9 @@ type AuditLogResponse struct {
 	ErrorMsg    string    `json:"error_msg"`
 	RequestBody string    `json:"request_body"`
 	Duration    int64     `json:"duration"`
+	PrevHash    string    `json:"prev_hash"`
+	Hash        string    `json:"hash"`
+	RequestID   string    `json:"request_id"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -53,6 +59,7 @@ type AuditLogQuery struct {
 	Resource   string    `json:"resource" form:"resource"`
 	Method     string    `json:"method" form:"method"`
 	Status     *int      `json:"status" form:"status"`
+	RequestID  string    `json:"request_id" form:"request_id"`
 	StartTime  time.Time `json:"start_time" form:"start_time"`
 	EndTime    time.Time `json:"end_time" form:"end_time"`
 	Page       int       `json:"page" form:"page"`
@@ -23,15 +23,16 @@ type DictType struct {
 
 // DictTypeResponse 字典类型响应结构体
 type DictTypeResponse struct {
-	ID          uint      `json:"id"`
-	Code        string    `json:"code"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	SortOrder   int       `json:"sort_order"`
-	IsSystem    bool      `json:"is_system"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	SortOrder   int        `json:"sort_order"`
+	IsSystem    bool       `json:"is_system"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // 删除时间，仅回收站列表返回
 }
 
 // CreateDictTypeRequest 创建字典类型请求
@@ -106,18 +107,35 @@ type UpdateDictItemRequest struct {
 	IsDefault   bool                   `json:"is_default" binding:"omitempty"`
 }
 
-// DictTypeListRequest 字典类型列表查询请求
+// DictTypeSortFields 字典类型列表允许排序的字段
+var DictTypeSortFields = SortFields{
+	"id":         "id",
+	"code":       "code",
+	"name":       "name",
+	"sort_order": "sort_order",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+// DictItemSortFields 字典项列表允许排序的字段
+var DictItemSortFields = SortFields{
+	"id":         "id",
+	"label":      "label",
+	"value":      "value",
+	"sort_order": "sort_order",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+// DictTypeListRequest 字典类型列表查询请求，关键字匹配代码和名称
 type DictTypeListRequest struct {
-	Page     int    `form:"page" binding:"omitempty,min=1"`
-	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
-	Status   string `form:"status" binding:"omitempty,oneof=active inactive"`
-	Keyword  string `form:"keyword" binding:"omitempty,max=50"`
+	ListQuery
+	Status string `form:"status" binding:"omitempty,oneof=active inactive"`
 }
 
-// DictItemListRequest 字典项列表查询请求
+// DictItemListRequest 字典项列表查询请求，关键字匹配标签和值
 type DictItemListRequest struct {
-	Page         int    `form:"page" binding:"omitempty,min=1"`
-	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	ListQuery
 	DictTypeCode string `form:"dict_type_code" binding:"omitempty,max=50"`
 	Status       string `form:"status" binding:"omitempty,oneof=active inactive"`
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// 健康状态
+const (
+	HealthStatusOK       = "ok"       // 存活检查，与原有 /health 接口保持一致
+	HealthStatusUp       = "up"       // 正常
+	HealthStatusDegraded = "degraded" // 可选组件异常，仍可处理请求
+	HealthStatusDown     = "down"     // 异常
+	HealthStatusDraining = "draining" // 正在优雅关闭
+)
+
+// ComponentHealth 单个依赖组件的检查结果
+type ComponentHealth struct {
+	Status    string  `json:"status"`          // up, degraded, down
+	LatencyMs float64 `json:"latency_ms"`      // 检查耗时（毫秒）
+	Error     string  `json:"error,omitempty"` // 失败原因
+}
+
+// HealthReport 就绪检查结果
+type HealthReport struct {
+	Status     string                     `json:"status"`     // up, degraded, down, draining
+	Components map[string]ComponentHealth `json:"components"` // 各依赖组件的检查结果
+	CheckedAt  time.Time                  `json:"checked_at"` // 检查时间（结果会短暂缓存）
+}
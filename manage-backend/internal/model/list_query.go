@@ -0,0 +1,71 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultPageSize 未指定每页数量时的默认值
+const DefaultPageSize = 10
+
+// SortFields 允许排序的字段白名单，键为请求参数中的字段名，值为数据库列名
+type SortFields map[string]string
+
+// ListQuery 通用列表查询参数：分页、关键字、创建时间范围和排序
+// 各列表接口的查询请求嵌入该结构，再补充各自的筛选字段
+type ListQuery struct {
+	Page        int       `form:"page" json:"page"`
+	PageSize    int       `form:"page_size" json:"page_size"`
+	Keyword     string    `form:"keyword" json:"keyword" binding:"omitempty,max=50"`
+	CreatedFrom time.Time `form:"created_from" json:"created_from"` // 创建时间起（RFC3339）
+	CreatedTo   time.Time `form:"created_to" json:"created_to"`     // 创建时间止（RFC3339）
+	SortBy      string    `form:"sort_by" json:"sort_by"`
+	SortOrder   string    `form:"sort_order" json:"sort_order" binding:"omitempty,oneof=asc desc"`
+}
+
+// Normalize 补全分页默认值，校验排序字段和创建时间范围
+// 每页数量超过 maxPageSize 时截断
+func (q *ListQuery) Normalize(sortFields SortFields, maxPageSize int) error {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = DefaultPageSize
+	}
+	if q.PageSize > maxPageSize {
+		q.PageSize = maxPageSize
+	}
+	if q.SortBy != "" {
+		if _, ok := sortFields[q.SortBy]; !ok {
+			return fmt.Errorf("不支持按 %s 排序", q.SortBy)
+		}
+	}
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && q.CreatedFrom.After(q.CreatedTo) {
+		return errors.New("created_from 不能晚于 created_to")
+	}
+	return nil
+}
+
+// Offset 返回分页偏移量
+func (q *ListQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
+
+// OrderBy 返回排序子句，未指定或不在白名单中的排序字段使用 defaultOrder
+// 追加 id 作为次要排序，保证排序值相同时翻页结果稳定
+func (q *ListQuery) OrderBy(sortFields SortFields, defaultOrder string) string {
+	column, ok := sortFields[q.SortBy]
+	if !ok {
+		return defaultOrder
+	}
+	direction := "ASC"
+	if strings.EqualFold(q.SortOrder, "desc") {
+		direction = "DESC"
+	}
+	if column == "id" {
+		return "id " + direction
+	}
+	return column + " " + direction + ", id " + direction
+}
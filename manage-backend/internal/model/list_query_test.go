@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListQueryNormalize(t *testing.T) {
+	t.Run("defaults and max page size", func(t *testing.T) {
+		q := ListQuery{PageSize: 500}
+		assert.NoError(t, q.Normalize(UserSortFields, 50))
+		assert.Equal(t, 1, q.Page)
+		assert.Equal(t, 50, q.PageSize)
+
+		q = ListQuery{Page: 3}
+		assert.NoError(t, q.Normalize(UserSortFields, 50))
+		assert.Equal(t, DefaultPageSize, q.PageSize)
+		assert.Equal(t, 20, q.Offset())
+	})
+
+	t.Run("sort field must be whitelisted", func(t *testing.T) {
+		q := ListQuery{SortBy: "password"}
+		assert.Error(t, q.Normalize(UserSortFields, 50))
+	})
+
+	t.Run("created range must be ordered", func(t *testing.T) {
+		now := time.Now()
+		q := ListQuery{CreatedFrom: now, CreatedTo: now.Add(-time.Hour)}
+		assert.Error(t, q.Normalize(UserSortFields, 50))
+	})
+}
+
+func TestListQueryOrderBy(t *testing.T) {
+	const defaultOrder = "created_at DESC"
+
+	assert.Equal(t, defaultOrder, (&ListQuery{}).OrderBy(UserSortFields, defaultOrder))
+	assert.Equal(t, "username ASC, id ASC", (&ListQuery{SortBy: "username"}).OrderBy(UserSortFields, defaultOrder))
+	assert.Equal(t, "created_at DESC, id DESC", (&ListQuery{SortBy: "created_at", SortOrder: "desc"}).OrderBy(UserSortFields, defaultOrder))
+	assert.Equal(t, "id DESC", (&ListQuery{SortBy: "id", SortOrder: "desc"}).OrderBy(UserSortFields, defaultOrder))
+	assert.Equal(t, defaultOrder, (&ListQuery{SortBy: "password"}).OrderBy(UserSortFields, defaultOrder))
+}
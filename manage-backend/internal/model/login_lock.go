@@ -0,0 +1,34 @@
+package model
+
+// AccountLockEntry 账户的登录锁定状态
+// 列表包含已锁定的账户和当前窗口内有登录失败记录的账户
+type AccountLockEntry struct {
+	Username  string `json:"username"`   // 用户名
+	Locked    bool   `json:"locked"`     // 是否已锁定
+	Reason    string `json:"reason"`     // 锁定原因
+	LockTTL   int64  `json:"lock_ttl"`   // 剩余锁定秒数
+	FailCount int    `json:"fail_count"` // 当前窗口内的连续失败次数（锁定时清零）
+	MaxFails  int    `json:"max_fails"`  // 锁定前允许的连续失败次数
+	LockCount int    `json:"lock_count"` // 保留期内的锁定次数（exponential 锁定方式）
+}
+
+// AccountLockQuery 账户锁定状态查询条件
+type AccountLockQuery struct {
+	Username   string `form:"username"`    // 用户名，支持前缀匹配
+	LockedOnly bool   `form:"locked_only"` // 只返回已锁定的账户
+}
+
+// IPBlockEntry 登录 IP 的限流状态
+type IPBlockEntry struct {
+	IP          string `json:"ip"`           // 客户端 IP
+	Attempts    int    `json:"attempts"`     // 当前窗口内的登录次数
+	MaxAttempts int    `json:"max_attempts"` // 窗口内允许的最大登录次数
+	Blocked     bool   `json:"blocked"`      // 是否已被封禁
+	ResetIn     int64  `json:"reset_in"`     // 距离窗口结束的秒数
+}
+
+// IPBlockQuery 登录 IP 限流状态查询条件
+type IPBlockQuery struct {
+	IP          string `form:"ip"`           // IP，支持前缀匹配，如 10.0.
+	BlockedOnly bool   `form:"blocked_only"` // 只返回已封禁的 IP
+}
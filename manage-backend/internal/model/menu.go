@@ -42,6 +42,17 @@ type MenuResponse struct {
 	Children       []MenuResponse `json:"children,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty"` // 删除时间，仅回收站列表返回
+}
+
+// MenuSortFields 菜单列表允许排序的字段
+var MenuSortFields = SortFields{
+	"id":         "id",
+	"name":       "name",
+	"title":      "title",
+	"order_num":  "order_num",
+	"status":     "status",
+	"created_at": "created_at",
 }
 
 // CreateMenuRequest 创建菜单请求
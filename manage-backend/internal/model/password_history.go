@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// PasswordHistory 用户历史密码（bcrypt 哈希），用于禁止重复使用最近的旧密码
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	UserID       uint      `json:"user_id" gorm:"not null;index:idx_password_histories_user_created,priority:1"`
+	PasswordHash string    `json:"-" gorm:"not null;size:255"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index:idx_password_histories_user_created,priority:2"`
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
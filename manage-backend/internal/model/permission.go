@@ -25,18 +25,19 @@ type Permission struct {
 
 // PermissionResponse 权限响应结构体
 type PermissionResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Code        string    `json:"code"`
-	Resource    string    `json:"resource"`
-	Action      string    `json:"action"`
-	Path        string    `json:"path"`
-	Method      string    `json:"method"`
-	Description string    `json:"description"`
-	Type        string    `json:"type"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Code        string     `json:"code"`
+	Resource    string     `json:"resource"`
+	Action      string     `json:"action"`
+	Path        string     `json:"path"`
+	Method      string     `json:"method"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // 删除时间，仅回收站列表返回
 }
 
 // CreatePermissionRequest 创建权限请求
@@ -60,6 +61,25 @@ type UpdatePermissionRequest struct {
 	Status      string `json:"status" binding:"omitempty,oneof=active inactive"`
 }
 
+// PermissionSortFields 权限列表允许排序的字段
+var PermissionSortFields = SortFields{
+	"id":         "id",
+	"name":       "name",
+	"code":       "code",
+	"resource":   "resource",
+	"type":       "type",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+// PermissionListRequest 权限列表查询请求，关键字匹配名称、代码和路径
+type PermissionListRequest struct {
+	ListQuery
+	Resource string `form:"resource" binding:"omitempty,max=50"`
+	Type     string `form:"type" binding:"omitempty,max=20"`
+	Status   string `form:"status" binding:"omitempty,oneof=active inactive"`
+}
+
 // PermissionTree 权限树结构（按资源分组）
 type PermissionTree struct {
 	Resource    string               `json:"resource"`
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// 限流维度
+const (
+	RateLimitKeyByIP     = "ip"      // 按客户端 IP
+	RateLimitKeyByUser   = "user"    // 按登录用户，未登录时按 IP
+	RateLimitKeyByAPIKey = "api_key" // 按已登记的 X-API-Key，Key 无效时按登录用户，未登录时按 IP
+)
+
+// RateLimitResult 一次限流判断的结果
+type RateLimitResult struct {
+	Allowed   bool          // 是否放行
+	Limit     int           // 窗口内允许的最大请求数
+	Remaining int           // 窗口内剩余可用次数
+	ResetIn   time.Duration // 距离窗口内最早一次请求过期（释放一个名额）的时间
+}
+
+// RateLimitEntry 当前限流计数
+type RateLimitEntry struct {
+	Group     string `json:"group"`     // 路由分组
+	Subject   string `json:"subject"`   // 限流对象，如 ip:127.0.0.1、user:1
+	Count     int    `json:"count"`     // 当前窗口内的请求数
+	Limit     int    `json:"limit"`     // 窗口内允许的最大请求数
+	Remaining int    `json:"remaining"` // 剩余可用次数
+	ResetIn   int64  `json:"reset_in"`  // 距离计数全部过期的秒数
+	Limited   bool   `json:"limited"`   // 是否已被限流
+}
+
+// RateLimitQuery 限流计数查询条件
+type RateLimitQuery struct {
+	Group   string `form:"group"`   // 路由分组，为空时查询全部分组
+	Subject string `form:"subject"` // 限流对象，支持前缀匹配，如 ip:10.0.
+}
+
+// ClearRateLimitRequest 清除限流计数请求
+type ClearRateLimitRequest struct {
+	Group   string `form:"group" binding:"required"` // 路由分组
+	Subject string `form:"subject"`                  // 限流对象，为空时清除该分组的全部计数
+}
+
+// ClearRateLimitResponse 清除限流计数响应
+type ClearRateLimitResponse struct {
+	Cleared int64 `json:"cleared"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeletedTime 返回软删除时间，未删除时返回 nil
+func DeletedTime(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	t := deletedAt.Time
+	return &t
+}
+
+// DeletedSortFields 回收站列表允许排序的字段：在原列表排序字段的基础上增加删除时间
+func DeletedSortFields(fields SortFields) SortFields {
+	result := make(SortFields, len(fields)+1)
+	for key, column := range fields {
+		result[key] = column
+	}
+	result["deleted_at"] = "deleted_at"
+	return result
+}
@@ -21,14 +21,15 @@ type Role struct {
 
 // RoleResponse 角色响应结构体
 type RoleResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Code        string    `json:"code"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	IsSystem    bool      `json:"is_system"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Code        string     `json:"code"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	IsSystem    bool       `json:"is_system"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // 删除时间，仅回收站列表返回
 }
 
 // CreateRoleRequest 创建角色请求
@@ -46,6 +47,21 @@ type UpdateRoleRequest struct {
 	Status      string `json:"status" binding:"omitempty,oneof=active inactive"`
 }
 
+// RoleSortFields 角色列表允许排序的字段
+var RoleSortFields = SortFields{
+	"id":         "id",
+	"name":       "name",
+	"code":       "code",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+// RoleListRequest 角色列表查询请求，关键字匹配名称和代码
+type RoleListRequest struct {
+	ListQuery
+	Status string `form:"status" binding:"omitempty,oneof=active inactive"`
+}
+
 // AssignRolePermissionsRequest 分配角色权限请求
 type AssignRolePermissionsRequest struct {
 	PermissionIDs []uint `json:"permission_ids" binding:"required"`
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+)
+
+// 系统设置键
+const (
+	SettingKeySecurityPolicy = "security_policy" // 登录锁定与限流策略
+)
+
+// SystemSetting 系统设置（运行时可修改的配置，值为 JSON）
+type SystemSetting struct {
+	Key       string    `json:"key" gorm:"primaryKey;size:64"`
+	Value     string    `json:"value" gorm:"type:text;not null"`
+	UpdatedBy uint      `json:"updated_by" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SystemSetting) TableName() string {
+	return "system_settings"
+}
+
+// 安全策略来源
+const (
+	SecurityPolicySourceConfig   = "config"   // 配置文件（未通过管理接口修改过）
+	SecurityPolicySourceOverride = "override" // 管理员通过接口修改并持久化的策略
+)
+
+// SecurityPolicyResponse 当前生效的安全策略
+type SecurityPolicyResponse struct {
+	Policy    config.SecurityConfig `json:"policy"`
+	Source    string                `json:"source"`               // config, override
+	UpdatedBy uint                  `json:"updated_by,omitempty"` // 最后修改人ID
+	UpdatedAt *time.Time            `json:"updated_at,omitempty"` // 最后修改时间
+}
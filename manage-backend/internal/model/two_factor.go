@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+)
+
+// UserTwoFactor 用户两步验证（TOTP）配置
+// Secret 在确认前处于待绑定状态（Enabled=false），确认首个验证码后才会生效
+type UserTwoFactor struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	UserID        uint       `json:"user_id" gorm:"not null;uniqueIndex"`
+	Secret        string     `json:"-" gorm:"not null;size:64"`
+	Enabled       bool       `json:"enabled" gorm:"not null;default:false"`
+	RecoveryCodes string     `json:"-" gorm:"type:text"` // 恢复码哈希（JSON 数组），使用后移除
+	ConfirmedAt   *time.Time `json:"confirmed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// TwoFactorStatusResponse 两步验证状态响应
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	ConfirmedAt            *time.Time `json:"confirmed_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollResponse 两步验证绑定响应
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`           // Base32 密钥，供无法扫码时手动输入
+	ProvisioningURI string `json:"provisioning_uri"` // otpauth:// 地址，前端据此生成二维码
+}
+
+// TwoFactorConfirmRequest 确认绑定请求
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TwoFactorRecoveryCodesResponse 恢复码响应（仅在生成时返回一次）
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallengeResponse 登录需要两步验证时返回的挑战
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"` // 始终为 true
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int64  `json:"expires_in"` // 挑战有效时长（秒）
+}
+
+// TwoFactorVerifyRequest 两步验证登录请求
+// Code 可以是身份验证器中的 6 位验证码，也可以是一次性恢复码
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
@@ -3,38 +3,98 @@ package model
 import (
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+// 用户状态，与字典 user_status 的取值一致
+const (
+	UserStatusActive    = "active"    // 启用
+	UserStatusInactive  = "inactive"  // 禁用
+	UserStatusSuspended = "suspended" // 暂停使用
+	UserStatusPending   = "pending"   // 待审核
+	UserStatusLocked    = "locked"    // 已锁定
+)
+
 type User struct {
-	ID        uint           `json:"id" gorm:"primarykey"`
-	Username  string         `json:"username" gorm:"uniqueIndex;not null"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	Role      string         `json:"role" gorm:"default:user"`
-	Status    string         `json:"status" gorm:"default:active"`
-	CreatedBy *uint          `json:"created_by" gorm:"index"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                 uint           `json:"id" gorm:"primarykey"`
+	Username           string         `json:"username" gorm:"uniqueIndex;not null"`
+	Email              string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password           string         `json:"-" gorm:"not null"`
+	Role               string         `json:"role" gorm:"default:user"`
+	Status             string         `json:"status" gorm:"default:active"`
+	ExpiresAt          *time.Time     `json:"expires_at"`                                         // 账户到期时间，为空表示永不过期
+	MustChangePassword bool           `json:"must_change_password" gorm:"not null;default:false"` // 登录后必须先修改密码
+	DisplayName        string         `json:"display_name" gorm:"size:50"`                        // 显示名称
+	Phone              string         `json:"phone" gorm:"size:20"`                               // 手机号
+	Department         string         `json:"department" gorm:"size:100"`                         // 部门
+	Locale             string         `json:"locale" gorm:"size:20;default:zh-CN"`                // 语言，如 zh-CN、en-US
+	Timezone           string         `json:"timezone" gorm:"size:50;default:Asia/Shanghai"`      // IANA 时区，如 Asia/Shanghai
+	AvatarURL          string         `json:"avatar_url" gorm:"size:255"`                         // 头像访问地址
+	AvatarKey          string         `json:"-" gorm:"size:255"`                                  // 头像在文件存储中的键，更换头像时删除旧文件
+	Preferences        datatypes.JSON `json:"preferences" gorm:"type:jsonb"`                      // 个人偏好设置（前端自定义的 JSON 对象）
+	CreatedBy          *uint          `json:"created_by" gorm:"index"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// IsExpired 判断账户在 now 时是否已过期
+func (u *User) IsExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
 }
 
 // UserResponse 用户响应结构体（不包含敏感信息）
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	Status    string    `json:"status"`
-	CreatedBy *uint     `json:"created_by,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                 uint           `json:"id"`
+	Username           string         `json:"username"`
+	Email              string         `json:"email"`
+	Role               string         `json:"role"`
+	Status             string         `json:"status"`
+	ExpiresAt          *time.Time     `json:"expires_at"`
+	MustChangePassword bool           `json:"must_change_password"`
+	DisplayName        string         `json:"display_name"`
+	Phone              string         `json:"phone"`
+	Department         string         `json:"department"`
+	Locale             string         `json:"locale"`
+	Timezone           string         `json:"timezone"`
+	AvatarURL          string         `json:"avatar_url"`
+	Preferences        datatypes.JSON `json:"preferences"`
+	CreatedBy          *uint          `json:"created_by,omitempty"`
+	Locked             bool           `json:"locked"` // 是否因连续登录失败被锁定
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          *time.Time     `json:"deleted_at,omitempty"` // 删除时间，仅回收站列表返回
+}
+
+// ToResponse 转换为用户响应
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:                 u.ID,
+		Username:           u.Username,
+		Email:              u.Email,
+		Role:               u.Role,
+		Status:             u.Status,
+		ExpiresAt:          u.ExpiresAt,
+		MustChangePassword: u.MustChangePassword,
+		DisplayName:        u.DisplayName,
+		Phone:              u.Phone,
+		Department:         u.Department,
+		Locale:             u.Locale,
+		Timezone:           u.Timezone,
+		AvatarURL:          u.AvatarURL,
+		Preferences:        u.Preferences,
+		CreatedBy:          u.CreatedBy,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+		DeletedAt:          DeletedTime(u.DeletedAt),
+	}
 }
 
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	Role     string `json:"role"`
 }
 
@@ -42,7 +102,39 @@ type UpdateUserRequest struct {
 	Username string `json:"username" binding:"omitempty,min=3,max=50"`
 	Email    string `json:"email" binding:"omitempty,email"`
 	Role     string `json:"role"`
-	Status   string `json:"status"`
+	Status   string `json:"status" binding:"omitempty,oneof=active inactive suspended pending locked"`
+}
+
+// UserSortFields 用户列表允许排序的字段
+var UserSortFields = SortFields{
+	"id":         "id",
+	"username":   "username",
+	"email":      "email",
+	"role":       "role",
+	"status":     "status",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// UserListRequest 用户列表查询请求，关键字匹配用户名和邮箱
+type UserListRequest struct {
+	ListQuery
+	Status    string `form:"status" binding:"omitempty,oneof=active inactive suspended pending locked"`
+	Role      string `form:"role" binding:"omitempty,max=50"` // 角色代码，匹配主角色或已分配的角色
+	CreatedBy *uint  `form:"created_by"`
+}
+
+// UpdateUserStatusRequest 更新账户状态请求，字段整体替换，expires_at 为空表示永不过期
+type UpdateUserStatusRequest struct {
+	Status             string     `json:"status" binding:"required,oneof=active inactive suspended pending locked"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	MustChangePassword bool       `json:"must_change_password"`
+}
+
+// ChangePasswordRequest 修改当前用户密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
 }
 
 type LoginRequest struct {
@@ -69,6 +161,7 @@ type RefreshTokenRequest struct {
 // RefreshTokenResponse 刷新token响应
 type RefreshTokenResponse struct {
 	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
 	TokenType    string `json:"token_type"`
 }
@@ -78,6 +171,22 @@ type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
+// SessionResponse 登录会话响应（不包含令牌）
+type SessionResponse struct {
+	SessionID    string    `json:"session_id"`
+	DeviceInfo   string    `json:"device_info"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	LoginTime    time.Time `json:"login_time"`
+	LastActivity time.Time `json:"last_activity"`
+	Current      bool      `json:"current"` // 是否为当前请求所属会话
+}
+
+// RevokeSessionsResponse 批量吊销会话响应
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
 // CheckAvailabilityRequest 检查可用性请求
 type CheckAvailabilityRequest struct {
 	Username      string `json:"username,omitempty"`
@@ -0,0 +1,35 @@
+package model
+
+// 用户批量操作类型
+const (
+	UserBatchOpStatus         = "status"          // 修改账户状态
+	UserBatchOpDelete         = "delete"          // 软删除
+	UserBatchOpAssignRole     = "assign_role"     // 设置角色
+	UserBatchOpRemoveRole     = "remove_role"     // 移除角色，恢复为默认角色
+	UserBatchOpRevokeSessions = "revoke_sessions" // 强制下线
+)
+
+// UserBatchRequest 用户批量操作请求，单次最多 100 个用户
+type UserBatchRequest struct {
+	Operation string `json:"operation" binding:"required,oneof=status delete assign_role remove_role revoke_sessions"`
+	IDs       []uint `json:"ids" binding:"required,min=1,max=100,dive,gt=0"`
+	Status    string `json:"status" binding:"omitempty,oneof=active inactive suspended pending locked"` // status 操作必填
+	Role      string `json:"role" binding:"omitempty,max=50"`                                           // assign_role、remove_role 操作必填，角色代码
+}
+
+// UserBatchResult 单个用户的批量操作结果
+type UserBatchResult struct {
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"` // 失败原因
+}
+
+// UserBatchResponse 用户批量操作结果
+// 每个用户独立执行，部分失败不影响其他用户
+type UserBatchResponse struct {
+	Operation string            `json:"operation"`
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []UserBatchResult `json:"results"`
+}
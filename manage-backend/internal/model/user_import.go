@@ -0,0 +1,36 @@
+package model
+
+// 批量导入导出限制
+const (
+	UserImportMaxFileSize = 5 << 20 // 导入文件大小上限（字节）
+	UserImportMaxRows     = 1000    // 单次导入的数据行上限
+	UserExportMaxRows     = 10000   // 单次导出的用户数上限
+)
+
+// 导入文件的列名（表头不区分大小写）
+const (
+	UserImportColumnUsername           = "username"
+	UserImportColumnEmail              = "email"
+	UserImportColumnPassword           = "password"
+	UserImportColumnRole               = "role"                 // 可选，为空时使用默认角色 user
+	UserImportColumnMustChangePassword = "must_change_password" // 可选，true 表示首次登录必须修改密码
+)
+
+// UserImportRowError 导入文件中一行数据的校验错误
+type UserImportRowError struct {
+	Row      int      `json:"row"`      // 文件中的行号（表头为第 1 行）
+	Username string   `json:"username"` // 该行的用户名
+	Email    string   `json:"email"`    // 该行的邮箱
+	Messages []string `json:"messages"` // 错误原因
+}
+
+// UserImportReport 批量导入结果
+// 存在任一错误行时不会导入任何用户
+type UserImportReport struct {
+	DryRun   bool                 `json:"dry_run"`  // 是否只校验不导入
+	Total    int                  `json:"total"`    // 数据行数（不含表头和空行）
+	Valid    int                  `json:"valid"`    // 校验通过的行数
+	Invalid  int                  `json:"invalid"`  // 校验未通过的行数
+	Imported int                  `json:"imported"` // 实际导入的用户数
+	Errors   []UserImportRowError `json:"errors"`   // 每行的错误明细
+}
@@ -0,0 +1,21 @@
+package model
+
+// 个人资料与头像限制
+const (
+	AvatarMaxFileSize     = 2 << 20 // 头像文件大小上限（字节）
+	AvatarMaxDimension    = 4096    // 头像原图的最大宽高（像素），防止解码超大图片占用过多内存
+	AvatarSize            = 256     // 头像裁剪为正方形后缩放到的边长（像素）
+	ProfilePreferencesMax = 8 << 10 // 个人偏好设置序列化后的大小上限（字节）
+)
+
+// UpdateProfileRequest 更新当前用户个人资料请求
+// 未传的字段不修改；显示名称、手机号、部门传空字符串表示清空；preferences 整体替换，传 {} 表示清空
+type UpdateProfileRequest struct {
+	Email       *string                `json:"email" binding:"omitempty,email"`
+	DisplayName *string                `json:"display_name" binding:"omitempty,max=50"`
+	Phone       *string                `json:"phone" binding:"omitempty,max=20"`
+	Department  *string                `json:"department" binding:"omitempty,max=100"`
+	Locale      *string                `json:"locale" binding:"omitempty,max=20"`   // 如 zh-CN、en-US
+	Timezone    *string                `json:"timezone" binding:"omitempty,max=50"` // IANA 时区，如 Asia/Shanghai
+	Preferences map[string]interface{} `json:"preferences"`
+}
@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"gorm.io/gorm"
 )
 
+// auditChainLockKey 审计日志哈希链的事务级咨询锁
+// 所有写入与清理都在该锁内进行，保证 PrevHash 按 ID 顺序首尾相连
+const auditChainLockKey = 7310001
+
 // AuditLogRepository 审计日志数据仓库
 type AuditLogRepository struct {
 	db *gorm.DB
@@ -15,9 +21,42 @@ func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
 	return &AuditLogRepository{db: db}
 }
 
-// Create 创建审计日志
+// Create 创建审计日志（追加到哈希链末尾）
 func (r *AuditLogRepository) Create(log *model.AuditLog) error {
-	return r.db.Create(log).Error
+	return r.CreateBatch([]*model.AuditLog{log})
+}
+
+// CreateBatch 批量创建审计日志，依次计算哈希并追加到哈希链末尾
+func (r *AuditLogRepository) CreateBatch(logs []*model.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockAuditChain(tx); err != nil {
+			return err
+		}
+
+		var last model.AuditLog
+		if err := tx.Unscoped().Select("id", "hash").Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		prevHash := last.Hash
+		for _, log := range logs {
+			if log.CreatedAt.IsZero() {
+				log.CreatedAt = now
+			}
+			// 与数据库精度保持一致，保证读回后哈希不变
+			log.CreatedAt = log.CreatedAt.Truncate(time.Microsecond)
+			log.PrevHash = prevHash
+			log.Hash = log.ComputeHash()
+			prevHash = log.Hash
+		}
+
+		return tx.Create(&logs).Error
+	})
 }
 
 // GetByID 根据ID获取审计日志
@@ -35,9 +74,95 @@ func (r *AuditLogRepository) Query(query *model.AuditLogQuery) ([]model.AuditLog
 	var logs []model.AuditLog
 	var total int64
 
-	db := r.db.Model(&model.AuditLog{})
+	db := applyAuditLogFilters(r.db.Model(&model.AuditLog{}), query)
+
+	// 获取总数
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (query.Page - 1) * query.PageSize
+	err := db.Order("created_at DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Find(&logs).Error
+
+	return logs, total, err
+}
+
+// StreamQuery 按 ID 游标分批读取符合条件的审计日志，每批调用一次 fn
+// 不使用 OFFSET，避免大数据量导出时逐页变慢，且内存中只保留一批数据
+func (r *AuditLogRepository) StreamQuery(query *model.AuditLogQuery, batchSize int, fn func([]model.AuditLog) error) error {
+	return streamAuditLogs(func() *gorm.DB {
+		return applyAuditLogFilters(r.db.Model(&model.AuditLog{}), query)
+	}, batchSize, fn)
+}
+
+// StreamChain 按 ID 顺序分批读取时间范围内的全部审计日志（包含已软删除的记录），用于哈希链校验
+func (r *AuditLogRepository) StreamChain(startTime, endTime time.Time, batchSize int, fn func([]model.AuditLog) error) error {
+	return streamAuditLogs(func() *gorm.DB {
+		db := r.db.Unscoped().Model(&model.AuditLog{})
+		if !startTime.IsZero() {
+			db = db.Where("created_at >= ?", startTime)
+		}
+		if !endTime.IsZero() {
+			db = db.Where("created_at <= ?", endTime)
+		}
+		return db
+	}, batchSize, fn)
+}
+
+// GetPreviousEntry 获取指定ID之前的最后一条审计日志（包含已软删除的记录）
+func (r *AuditLogRepository) GetPreviousEntry(id uint) (*model.AuditLog, error) {
+	var log model.AuditLog
+	err := r.db.Unscoped().Where("id < ?", id).Order("id DESC").First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// GetCheckpointByHash 根据被清理的最后一条日志哈希获取检查点
+func (r *AuditLogRepository) GetCheckpointByHash(hash string) (*model.AuditLogCheckpoint, error) {
+	var checkpoint model.AuditLogCheckpoint
+	err := r.db.Where("last_entry_hash = ?", hash).First(&checkpoint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// streamAuditLogs 按 ID 游标分批读取，build 每次返回新的查询条件
+func streamAuditLogs(build func() *gorm.DB, batchSize int, fn func([]model.AuditLog) error) error {
+	var lastID uint
+	for {
+		var logs []model.AuditLog
+		err := build().
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&logs).Error
+		if err != nil {
+			return err
+		}
+		if len(logs) == 0 {
+			return nil
+		}
+
+		if err := fn(logs); err != nil {
+			return err
+		}
 
-	// 构建查询条件
+		if len(logs) < batchSize {
+			return nil
+		}
+		lastID = logs[len(logs)-1].ID
+	}
+}
+
+// applyAuditLogFilters 构建审计日志查询条件
+func applyAuditLogFilters(db *gorm.DB, query *model.AuditLogQuery) *gorm.DB {
 	if query.UserID != nil {
 		db = db.Where("user_id = ?", *query.UserID)
 	}
@@ -56,30 +181,106 @@ func (r *AuditLogRepository) Query(query *model.AuditLogQuery) ([]model.AuditLog
 	if query.Status != nil {
 		db = db.Where("status = ?", *query.Status)
 	}
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
 	if !query.StartTime.IsZero() {
 		db = db.Where("created_at >= ?", query.StartTime)
 	}
 	if !query.EndTime.IsZero() {
 		db = db.Where("created_at <= ?", query.EndTime)
 	}
+	return db
+}
 
-	// 获取总数
-	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, err
+// AuditLogArchiver 清理审计日志时使用的归档器
+// Stage 在删除事务内归档一批日志，失败则该批不会被删除；事务提交后调用 Commit 完成归档，回滚后调用 Discard 丢弃
+type AuditLogArchiver interface {
+	Stage(logs []model.AuditLog) error
+	Commit() error
+	Discard()
+}
+
+// DeleteOldLogs 物理删除指定天数之前的审计日志，返回删除条数
+// 按 ID 前缀分批删除，每批一个短事务，避免长时间锁表，且剩余日志始终是连续的哈希链；
+// 每批删除前先交给 archiver（可为 nil）暂存归档，再写入签名检查点记录该批最后一条日志的哈希，作为剩余日志的校验锚点
+func (r *AuditLogRepository) DeleteOldLogs(days int, batchSize int, checkpointKey []byte, archiver AuditLogArchiver) (int64, error) {
+	cutoff := time.Now().AddDate(0, 0, -days).Truncate(time.Microsecond)
+
+	// 只处理开始清理时已过期的日志，清理过程中新写入的日志不受影响
+	var boundary model.AuditLog
+	if err := r.db.Unscoped().Select("id").Where("created_at < ?", cutoff).Order("id DESC").Limit(1).Find(&boundary).Error; err != nil {
+		return 0, err
+	}
+	if boundary.ID == 0 {
+		return 0, nil
 	}
 
-	// 分页查询
-	offset := (query.Page - 1) * query.PageSize
-	err := db.Order("created_at DESC").
-		Offset(offset).
-		Limit(query.PageSize).
-		Find(&logs).Error
+	var deleted int64
+	for {
+		var batchDeleted int64
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := lockAuditChain(tx); err != nil {
+				return err
+			}
 
-	return logs, total, err
+			var logs []model.AuditLog
+			if err := tx.Unscoped().Where("id <= ?", boundary.ID).Order("id ASC").Limit(batchSize).Find(&logs).Error; err != nil {
+				return err
+			}
+			if len(logs) == 0 {
+				return nil
+			}
+
+			if archiver != nil {
+				if err := archiver.Stage(logs); err != nil {
+					return err
+				}
+			}
+
+			// 启用哈希链之前的历史日志没有哈希，无需检查点
+			last := logs[len(logs)-1]
+			if last.Hash != "" {
+				checkpoint := &model.AuditLogCheckpoint{
+					LastEntryID:   last.ID,
+					LastEntryHash: last.Hash,
+					LastEntryAt:   last.CreatedAt,
+					PurgedCount:   int64(len(logs)),
+					Cutoff:        cutoff,
+				}
+				checkpoint.Signature = checkpoint.ComputeSignature(checkpointKey)
+				if err := tx.Create(checkpoint).Error; err != nil {
+					return err
+				}
+			}
+
+			result := tx.Unscoped().Where("id <= ?", last.ID).Delete(&model.AuditLog{})
+			if result.Error != nil {
+				return result.Error
+			}
+			batchDeleted = result.RowsAffected
+			return nil
+		})
+		if err != nil {
+			if archiver != nil {
+				archiver.Discard()
+			}
+			return deleted, err
+		}
+
+		deleted += batchDeleted
+		if archiver != nil {
+			if err := archiver.Commit(); err != nil {
+				return deleted, err
+			}
+		}
+		if batchDeleted < int64(batchSize) {
+			return deleted, nil
+		}
+	}
 }
 
-// DeleteOldLogs 删除旧的审计日志
-func (r *AuditLogRepository) DeleteOldLogs(days int) error {
-	return r.db.Where("created_at < NOW() - INTERVAL ? DAY", days).
-		Delete(&model.AuditLog{}).Error
+// lockAuditChain 获取审计日志哈希链的事务级咨询锁（事务结束时自动释放）
+func lockAuditChain(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error
 }
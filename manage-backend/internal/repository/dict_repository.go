@@ -54,34 +54,15 @@ func (r *DictTypeRepository) Delete(id uint) error {
 }
 
 // List 获取字典类型列表（分页）
-func (r *DictTypeRepository) List(offset, limit int, status, keyword string) ([]model.DictType, int64, error) {
-	var dictTypes []model.DictType
-	var total int64
-
-	query := r.db.Model(&model.DictType{})
+func (r *DictTypeRepository) List(req *model.DictTypeListRequest) ([]model.DictType, int64, error) {
+	query := applyListQuery(r.db.Model(&model.DictType{}), &req.ListQuery, "code", "name")
 
 	// 状态筛选
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	// 关键字搜索
-	if keyword != "" {
-		query = query.Where("code LIKE ? OR name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
 	}
 
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// 分页查询
-	err := query.Order("sort_order ASC, created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&dictTypes).Error
-
-	return dictTypes, total, err
+	return findPage[model.DictType](query, &req.ListQuery, model.DictTypeSortFields, "sort_order ASC, created_at DESC")
 }
 
 // GetAll 获取所有字典类型（不分页）
@@ -93,22 +74,51 @@ func (r *DictTypeRepository) GetAll() ([]model.DictType, error) {
 	return dictTypes, err
 }
 
-// CheckCodeExists 检查代码是否存在
+// CheckCodeExists 检查代码是否存在（包括已删除的字典类型，它们仍占用唯一索引）
 func (r *DictTypeRepository) CheckCodeExists(code string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.DictType{}).Where("code = ?", code).Count(&count).Error
+	err := r.db.Unscoped().Model(&model.DictType{}).Where("code = ?", code).Count(&count).Error
 	return count > 0, err
 }
 
-// CheckCodeExistsExcludeID 检查代码是否存在（排除指定ID）
+// CheckCodeExistsExcludeID 检查代码是否存在（排除指定ID，包括已删除的字典类型）
 func (r *DictTypeRepository) CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.DictType{}).
+	err := r.db.Unscoped().Model(&model.DictType{}).
 		Where("code = ? AND id != ?", code, excludeID).
 		Count(&count).Error
 	return count > 0, err
 }
 
+// ListDeleted 获取已删除的字典类型列表（分页）
+func (r *DictTypeRepository) ListDeleted(query *model.ListQuery) ([]model.DictType, int64, error) {
+	return listDeleted[model.DictType](r.db, query, model.DeletedSortFields(model.DictTypeSortFields), "code", "name")
+}
+
+// GetDeletedByID 根据ID获取已删除的字典类型
+func (r *DictTypeRepository) GetDeletedByID(id uint) (*model.DictType, error) {
+	return getDeleted[model.DictType](r.db, id)
+}
+
+// Restore 恢复已删除的字典类型
+func (r *DictTypeRepository) Restore(id uint) error {
+	return restoreDeleted[model.DictType](r.db, id)
+}
+
+// Purge 彻底删除字典类型及其下所有字典项（包括已删除的字典项）
+func (r *DictTypeRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var dictType model.DictType
+		if err := tx.Unscoped().First(&dictType, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("dict_type_code = ?", dictType.Code).Delete(&model.DictItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.DictType{}, id).Error
+	})
+}
+
 // ==================== DictItemRepository ====================
 
 // DictItemRepository 字典项数据仓库
@@ -157,34 +167,20 @@ func (r *DictItemRepository) Delete(id uint) error {
 }
 
 // List 获取字典项列表（分页）
-func (r *DictItemRepository) List(offset, limit int, typeCode, status string) ([]model.DictItem, int64, error) {
-	var dictItems []model.DictItem
-	var total int64
-
-	query := r.db.Model(&model.DictItem{})
+func (r *DictItemRepository) List(req *model.DictItemListRequest) ([]model.DictItem, int64, error) {
+	query := applyListQuery(r.db.Model(&model.DictItem{}), &req.ListQuery, "label", "value")
 
 	// 类型代码筛选
-	if typeCode != "" {
-		query = query.Where("dict_type_code = ?", typeCode)
+	if req.DictTypeCode != "" {
+		query = query.Where("dict_type_code = ?", req.DictTypeCode)
 	}
 
 	// 状态筛选
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
 	}
 
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// 分页查询
-	err := query.Order("sort_order ASC, created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&dictItems).Error
-
-	return dictItems, total, err
+	return findPage[model.DictItem](query, &req.ListQuery, model.DictItemSortFields, "sort_order ASC, created_at DESC")
 }
 
 // GetByTypeCode 根据类型代码获取所有字典项
@@ -245,6 +241,22 @@ func (r *DictItemRepository) CountByTypeCode(typeCode string) (int64, error) {
 	return count, err
 }
 
+// ConvertDictTypeToResponse 转换字典类型为响应格式
+func ConvertDictTypeToResponse(dictType *model.DictType) *model.DictTypeResponse {
+	return &model.DictTypeResponse{
+		ID:          dictType.ID,
+		Code:        dictType.Code,
+		Name:        dictType.Name,
+		Description: dictType.Description,
+		Status:      dictType.Status,
+		SortOrder:   dictType.SortOrder,
+		IsSystem:    dictType.IsSystem,
+		CreatedAt:   dictType.CreatedAt,
+		UpdatedAt:   dictType.UpdatedAt,
+		DeletedAt:   model.DeletedTime(dictType.DeletedAt),
+	}
+}
+
 // ConvertDictItemToResponse 转换字典项为响应格式
 func ConvertDictItemToResponse(item *model.DictItem) *model.DictItemResponse {
 	response := &model.DictItemResponse{
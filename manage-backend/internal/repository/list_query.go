@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// likeEscaper 转义 LIKE 通配符，关键字按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applyListQuery 应用通用列表查询的关键字和创建时间范围条件
+// keywordColumns 为关键字模糊匹配（不区分大小写）的列，任一列匹配即可
+func applyListQuery(db *gorm.DB, query *model.ListQuery, keywordColumns ...string) *gorm.DB {
+	if query.Keyword != "" && len(keywordColumns) > 0 {
+		pattern := "%" + likeEscaper.Replace(query.Keyword) + "%"
+		conditions := make([]string, len(keywordColumns))
+		args := make([]interface{}, len(keywordColumns))
+		for i, column := range keywordColumns {
+			conditions[i] = column + " ILIKE ?"
+			args[i] = pattern
+		}
+		db = db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	if !query.CreatedFrom.IsZero() {
+		db = db.Where("created_at >= ?", query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		db = db.Where("created_at <= ?", query.CreatedTo)
+	}
+	return db
+}
+
+// findPage 统计总数后按白名单排序分页查询
+func findPage[T any](db *gorm.DB, query *model.ListQuery, sortFields model.SortFields, defaultOrder string) ([]T, int64, error) {
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []T
+	err := db.Order(query.OrderBy(sortFields, defaultOrder)).
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&items).Error
+	return items, total, err
+}
@@ -111,3 +111,30 @@ func (r *MenuRepository) HasChildren(id uint) (bool, error) {
 	return count > 0, err
 }
 
+// HasAnyChildren 检查菜单是否有子菜单（包括已删除的子菜单）
+func (r *MenuRepository) HasAnyChildren(id uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.Menu{}).Where("parent_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// ListDeleted 分页获取已删除的菜单
+func (r *MenuRepository) ListDeleted(query *model.ListQuery) ([]model.Menu, int64, error) {
+	return listDeleted[model.Menu](r.db, query, model.DeletedSortFields(model.MenuSortFields), "name", "title", "path")
+}
+
+// GetDeletedByID 根据 ID 获取已删除的菜单
+func (r *MenuRepository) GetDeletedByID(id uint) (*model.Menu, error) {
+	return getDeleted[model.Menu](r.db, id)
+}
+
+// Restore 恢复已删除的菜单
+func (r *MenuRepository) Restore(id uint) error {
+	return restoreDeleted[model.Menu](r.db, id)
+}
+
+// Purge 彻底删除菜单
+func (r *MenuRepository) Purge(id uint) error {
+	return r.db.Unscoped().Delete(&model.Menu{}, id).Error
+}
+
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepository 密码历史数据仓库
+type PasswordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository 创建 PasswordHistoryRepository 实例
+func NewPasswordHistoryRepository(db *gorm.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: db}
+}
+
+// Create 记录一条历史密码
+func (r *PasswordHistoryRepository) Create(ctx context.Context, history *model.PasswordHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// ListRecent 按时间倒序获取用户最近的历史密码
+func (r *PasswordHistoryRepository) ListRecent(ctx context.Context, userID uint, limit int) ([]model.PasswordHistory, error) {
+	var histories []model.PasswordHistory
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&histories).Error
+	return histories, err
+}
+
+// Prune 只保留用户最近 keep 条历史密码
+func (r *PasswordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	recent := r.db.Model(&model.PasswordHistory{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(keep)
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND id NOT IN (?)", userID, recent).
+		Delete(&model.PasswordHistory{}).Error
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"gorm.io/gorm"
 )
@@ -52,19 +54,18 @@ func (r *PermissionRepository) Delete(id uint) error {
 }
 
 // List 分页获取权限列表
-func (r *PermissionRepository) List(offset, limit int) ([]model.Permission, int64, error) {
-	var permissions []model.Permission
-	var total int64
-
-	// 获取总数
-	err := r.db.Model(&model.Permission{}).Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+func (r *PermissionRepository) List(req *model.PermissionListRequest) ([]model.Permission, int64, error) {
+	query := applyListQuery(r.db.Model(&model.Permission{}), &req.ListQuery, "name", "code", "path")
+	if req.Resource != "" {
+		query = query.Where("resource = ?", req.Resource)
 	}
-
-	// 分页查询
-	err = r.db.Offset(offset).Limit(limit).Order("resource, action").Find(&permissions).Error
-	return permissions, total, err
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	return findPage[model.Permission](query, &req.ListQuery, model.PermissionSortFields, "resource, action")
 }
 
 // GetAll 获取所有权限
@@ -88,24 +89,24 @@ func (r *PermissionRepository) GetByType(permType string) ([]model.Permission, e
 	return permissions, err
 }
 
-// CheckCodeExists 检查权限代码是否已存在
+// CheckCodeExists 检查权限代码是否已存在（包括已删除的权限，它们仍占用唯一索引）
 func (r *PermissionRepository) CheckCodeExists(code string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Permission{}).Where("code = ?", code).Count(&count).Error
+	err := r.db.Unscoped().Model(&model.Permission{}).Where("code = ?", code).Count(&count).Error
 	return count > 0, err
 }
 
-// CheckCodeExistsExcludeID 检查权限代码是否已存在（排除指定ID）
+// CheckCodeExistsExcludeID 检查权限代码是否已存在（排除指定ID，包括已删除的权限）
 func (r *PermissionRepository) CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Permission{}).Where("code = ? AND id != ?", code, excludeID).Count(&count).Error
+	err := r.db.Unscoped().Model(&model.Permission{}).Where("code = ? AND id != ?", code, excludeID).Count(&count).Error
 	return count > 0, err
 }
 
 // GetByIDs 根据 ID 列表批量获取权限
-func (r *PermissionRepository) GetByIDs(ids []uint) ([]model.Permission, error) {
+func (r *PermissionRepository) GetByIDs(ctx context.Context, ids []uint) ([]model.Permission, error) {
 	var permissions []model.Permission
-	err := r.db.Where("id IN ?", ids).Find(&permissions).Error
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&permissions).Error
 	return permissions, err
 }
 
@@ -115,3 +116,38 @@ func (r *PermissionRepository) GetByCodes(codes []string) ([]model.Permission, e
 	err := r.db.Where("code IN ?", codes).Find(&permissions).Error
 	return permissions, err
 }
+
+// ListDeleted 分页获取已删除的权限
+func (r *PermissionRepository) ListDeleted(query *model.ListQuery) ([]model.Permission, int64, error) {
+	return listDeleted[model.Permission](r.db, query, model.DeletedSortFields(model.PermissionSortFields), "name", "code", "path")
+}
+
+// GetDeletedByID 根据 ID 获取已删除的权限
+func (r *PermissionRepository) GetDeletedByID(id uint) (*model.Permission, error) {
+	return getDeleted[model.Permission](r.db, id)
+}
+
+// Restore 恢复已删除的权限
+func (r *PermissionRepository) Restore(id uint) error {
+	return restoreDeleted[model.Permission](r.db, id)
+}
+
+// GetRoleCodesByPermissionID 获取拥有指定权限的所有未删除角色的代码
+func (r *PermissionRepository) GetRoleCodesByPermissionID(permissionID uint) ([]string, error) {
+	var codes []string
+	err := r.db.Model(&model.RolePermission{}).
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Where("role_permissions.permission_id = ?", permissionID).
+		Pluck("roles.code", &codes).Error
+	return codes, err
+}
+
+// Purge 彻底删除权限及其角色关联
+func (r *PermissionRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("permission_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Permission{}, id).Error
+	})
+}
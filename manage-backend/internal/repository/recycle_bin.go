@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// 回收站：软删除记录的查询、恢复和彻底删除
+// 软删除的记录仍占用唯一索引，彻底删除后其用户名、代码等才能被重新使用
+
+// listDeleted 分页获取已软删除的记录，默认按删除时间倒序
+func listDeleted[T any](db *gorm.DB, query *model.ListQuery, sortFields model.SortFields, keywordColumns ...string) ([]T, int64, error) {
+	var zero T
+	q := db.Unscoped().Model(&zero).Where("deleted_at IS NOT NULL")
+	q = applyListQuery(q, query, keywordColumns...)
+	return findPage[T](q, query, sortFields, "deleted_at DESC")
+}
+
+// getDeleted 根据 ID 获取已软删除的记录，记录不存在或未删除时返回 gorm.ErrRecordNotFound
+func getDeleted[T any](db *gorm.DB, id uint) (*T, error) {
+	var item T
+	if err := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// restoreDeleted 恢复已软删除的记录，记录不存在或未删除时返回 gorm.ErrRecordNotFound
+func restoreDeleted[T any](db *gorm.DB, id uint) error {
+	var zero T
+	result := db.Unscoped().Model(&zero).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
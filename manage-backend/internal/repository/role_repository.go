@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"gorm.io/gorm"
 )
@@ -17,14 +19,14 @@ func NewRoleRepository(db *gorm.DB) *RoleRepository {
 }
 
 // Create 创建角色
-func (r *RoleRepository) Create(role *model.Role) error {
-	return r.db.Create(role).Error
+func (r *RoleRepository) Create(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
 }
 
 // GetByID 根据 ID 获取角色
-func (r *RoleRepository) GetByID(id uint) (*model.Role, error) {
+func (r *RoleRepository) GetByID(ctx context.Context, id uint) (*model.Role, error) {
 	var role model.Role
-	err := r.db.First(&role, id).Error
+	err := r.db.WithContext(ctx).First(&role, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,29 +44,22 @@ func (r *RoleRepository) GetByCode(code string) (*model.Role, error) {
 }
 
 // Update 更新角色信息
-func (r *RoleRepository) Update(role *model.Role) error {
-	return r.db.Save(role).Error
+func (r *RoleRepository) Update(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Save(role).Error
 }
 
 // Delete 删除角色
-func (r *RoleRepository) Delete(id uint) error {
-	return r.db.Delete(&model.Role{}, id).Error
+func (r *RoleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Role{}, id).Error
 }
 
-// List 分页获取角色列表
-func (r *RoleRepository) List(offset, limit int) ([]model.Role, int64, error) {
-	var roles []model.Role
-	var total int64
-
-	// 获取总数
-	err := r.db.Model(&model.Role{}).Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+// List 按条件分页获取角色列表
+func (r *RoleRepository) List(req *model.RoleListRequest) ([]model.Role, int64, error) {
+	query := applyListQuery(r.db.Model(&model.Role{}), &req.ListQuery, "name", "code")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
 	}
-
-	// 分页查询
-	err = r.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&roles).Error
-	return roles, total, err
+	return findPage[model.Role](query, &req.ListQuery, model.RoleSortFields, "created_at DESC")
 }
 
 // GetAll 获取所有角色
@@ -74,20 +69,48 @@ func (r *RoleRepository) GetAll() ([]model.Role, error) {
 	return roles, err
 }
 
-// CheckCodeExists 检查角色代码是否已存在
-func (r *RoleRepository) CheckCodeExists(code string) (bool, error) {
+// CheckCodeExists 检查角色代码是否已存在（包括已删除的角色，它们仍占用唯一索引）
+func (r *RoleRepository) CheckCodeExists(ctx context.Context, code string) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Role{}).Where("code = ?", code).Count(&count).Error
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.Role{}).Where("code = ?", code).Count(&count).Error
 	return count > 0, err
 }
 
-// CheckCodeExistsExcludeID 检查角色代码是否已存在（排除指定ID）
+// CheckCodeExistsExcludeID 检查角色代码是否已存在（排除指定ID，包括已删除的角色）
 func (r *RoleRepository) CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&model.Role{}).Where("code = ? AND id != ?", code, excludeID).Count(&count).Error
+	err := r.db.Unscoped().Model(&model.Role{}).Where("code = ? AND id != ?", code, excludeID).Count(&count).Error
 	return count > 0, err
 }
 
+// ListDeleted 分页获取已删除的角色
+func (r *RoleRepository) ListDeleted(query *model.ListQuery) ([]model.Role, int64, error) {
+	return listDeleted[model.Role](r.db, query, model.DeletedSortFields(model.RoleSortFields), "name", "code")
+}
+
+// GetDeletedByID 根据 ID 获取已删除的角色
+func (r *RoleRepository) GetDeletedByID(id uint) (*model.Role, error) {
+	return getDeleted[model.Role](r.db, id)
+}
+
+// Restore 恢复已删除的角色
+func (r *RoleRepository) Restore(ctx context.Context, id uint) error {
+	return restoreDeleted[model.Role](r.db.WithContext(ctx), id)
+}
+
+// Purge 彻底删除角色及其权限关联和用户关联
+func (r *RoleRepository) Purge(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Role{}, id).Error
+	})
+}
+
 // GetUserRoles 获取用户的所有角色
 func (r *RoleRepository) GetUserRoles(userID uint) ([]model.Role, error) {
 	var roles []model.Role
@@ -98,14 +121,41 @@ func (r *RoleRepository) GetUserRoles(userID uint) ([]model.Role, error) {
 	return roles, err
 }
 
+// GetRoleCodesByUserIDs 批量获取用户通过 user_roles 分配的角色代码，按用户ID分组
+func (r *RoleRepository) GetRoleCodesByUserIDs(userIDs []uint) (map[uint][]string, error) {
+	result := make(map[uint][]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		UserID uint
+		Code   string
+	}
+	err := r.db.Model(&model.UserRole{}).
+		Select("user_roles.user_id, roles.code").
+		Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.deleted_at IS NULL").
+		Where("user_roles.user_id IN ?", userIDs).
+		Order("user_roles.user_id, roles.code").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = append(result[row.UserID], row.Code)
+	}
+	return result, nil
+}
+
 // AssignRoleToUser 为用户分配角色
-func (r *RoleRepository) AssignRoleToUser(userID, roleID uint, assignedBy uint) error {
+func (r *RoleRepository) AssignRoleToUser(ctx context.Context, userID, roleID uint, assignedBy uint) error {
 	userRole := model.UserRole{
 		UserID:     userID,
 		RoleID:     roleID,
 		AssignedBy: assignedBy,
 	}
-	return r.db.Create(&userRole).Error
+	return r.db.WithContext(ctx).Create(&userRole).Error
 }
 
 // RemoveRoleFromUser 移除用户的角色
@@ -114,22 +164,22 @@ func (r *RoleRepository) RemoveRoleFromUser(userID, roleID uint) error {
 }
 
 // RemoveAllRolesFromUser 移除用户的所有角色
-func (r *RoleRepository) RemoveAllRolesFromUser(userID uint) error {
-	return r.db.Where("user_id = ?", userID).Delete(&model.UserRole{}).Error
+func (r *RoleRepository) RemoveAllRolesFromUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserRole{}).Error
 }
 
 // GetUsersByRole 获取拥有指定角色的所有用户ID
-func (r *RoleRepository) GetUsersByRole(roleID uint) ([]uint, error) {
+func (r *RoleRepository) GetUsersByRole(ctx context.Context, roleID uint) ([]uint, error) {
 	var userIDs []uint
-	err := r.db.Model(&model.UserRole{}).
+	err := r.db.WithContext(ctx).Model(&model.UserRole{}).
 		Where("role_id = ?", roleID).
 		Pluck("user_id", &userIDs).Error
 	return userIDs, err
 }
 
 // UpdateRolePermissions 更新角色的权限关联
-func (r *RoleRepository) UpdateRolePermissions(roleID uint, permissionIDs []uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *RoleRepository) UpdateRolePermissions(ctx context.Context, roleID uint, permissionIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 删除该角色的所有权限关联
 		if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
 			return err
@@ -154,9 +204,9 @@ func (r *RoleRepository) UpdateRolePermissions(roleID uint, permissionIDs []uint
 }
 
 // GetRolePermissionIDs 获取角色的所有权限ID
-func (r *RoleRepository) GetRolePermissionIDs(roleID uint) ([]uint, error) {
+func (r *RoleRepository) GetRolePermissionIDs(ctx context.Context, roleID uint) ([]uint, error) {
 	var permissionIDs []uint
-	err := r.db.Model(&model.RolePermission{}).
+	err := r.db.WithContext(ctx).Model(&model.RolePermission{}).
 		Where("role_id = ?", roleID).
 		Pluck("permission_id", &permissionIDs).Error
 	return permissionIDs, err
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SystemSettingRepository 系统设置数据仓库
+type SystemSettingRepository struct {
+	db *gorm.DB
+}
+
+// NewSystemSettingRepository 创建 SystemSettingRepository 实例
+func NewSystemSettingRepository(db *gorm.DB) *SystemSettingRepository {
+	return &SystemSettingRepository{db: db}
+}
+
+// Get 根据键获取系统设置
+func (r *SystemSettingRepository) Get(ctx context.Context, key string) (*model.SystemSetting, error) {
+	var setting model.SystemSetting
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&setting).Error; err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// Save 创建或更新系统设置
+func (r *SystemSettingRepository) Save(ctx context.Context, setting *model.SystemSetting) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository 两步验证数据仓库
+// 封装对 UserTwoFactor 模型的所有数据库操作
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 创建 TwoFactorRepository 实例
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetByUserID 根据用户ID获取两步验证配置
+func (r *TwoFactorRepository) GetByUserID(ctx context.Context, userID uint) (*model.UserTwoFactor, error) {
+	var twoFactor model.UserTwoFactor
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&twoFactor).Error
+	if err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// Save 创建或更新两步验证配置
+func (r *TwoFactorRepository) Save(ctx context.Context, twoFactor *model.UserTwoFactor) error {
+	return r.db.WithContext(ctx).Save(twoFactor).Error
+}
+
+// UpdateRecoveryCodes 更新恢复码（仅当恢复码未被并发修改时才会成功）
+// 返回: bool - 是否更新成功, error - 操作是否成功
+func (r *TwoFactorRepository) UpdateRecoveryCodes(ctx context.Context, id uint, oldCodes, newCodes string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserTwoFactor{}).
+		Where("id = ? AND recovery_codes = ?", id, oldCodes).
+		Update("recovery_codes", newCodes)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteByUserID 删除指定用户的两步验证配置
+func (r *TwoFactorRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.UserTwoFactor{}).Error
+}
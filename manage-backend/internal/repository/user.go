@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"gorm.io/gorm"
 )
 
+// createBatchSize 批量插入时每条 INSERT 语句的行数
+const createBatchSize = 100
+
 // UserRepository 用户数据仓库
 // 封装对 User 模型的所有数据库操作
 type UserRepository struct {
@@ -31,9 +35,9 @@ func (r *UserRepository) Create(user *model.User) error {
 // GetByID 根据 ID 获取用户
 // 参数: id - 用户ID
 // 返回: *model.User - 用户对象, error - 查询是否成功
-func (r *UserRepository) GetByID(id uint) (*model.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
 		return nil, fmt.Errorf("根据ID查询用户失败 [id=%d]: %w", id, err)
 	}
 	return &user, nil
@@ -42,9 +46,9 @@ func (r *UserRepository) GetByID(id uint) (*model.User, error) {
 // GetByUsername 根据用户名获取用户
 // 参数: username - 用户名
 // 返回: *model.User - 用户对象, error - 查询是否成功
-func (r *UserRepository) GetByUsername(username string) (*model.User, error) {
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, fmt.Errorf("根据用户名查询用户失败 [username=%s]: %w", username, err)
 	}
 	return &user, nil
@@ -71,6 +75,18 @@ func (r *UserRepository) Update(user *model.User) error {
 	return nil
 }
 
+// UpdateColumns 只更新用户的指定字段（及 updated_at），其余字段保持数据库中的值
+// 用于按读取时的副本修改部分字段，避免覆盖期间其他人对状态、密码等字段的修改
+// 参数: user - 用户对象（需包含ID）, columns - 要更新的字段名
+// 返回: error - 操作是否成功
+func (r *UserRepository) UpdateColumns(ctx context.Context, user *model.User, columns ...string) error {
+	selected := append([]string{"updated_at"}, columns...)
+	if err := r.db.WithContext(ctx).Model(user).Select(selected).Updates(user).Error; err != nil {
+		return fmt.Errorf("更新用户字段失败 [id=%d, columns=%v]: %w", user.ID, columns, err)
+	}
+	return nil
+}
+
 // Delete 删除用户
 // 参数: id - 用户ID
 // 返回: error - 操作是否成功
@@ -101,12 +117,35 @@ func (r *UserRepository) List(offset, limit int) ([]model.User, int64, error) {
 }
 
 // ListByVisibility 根据用户角色和可见性规则分页获取用户列表
-// 参数: currentUserID - 当前用户ID, currentUserRole - 当前用户角色, offset - 偏移量, limit - 每页数量
+// 筛选条件在可见范围内生效，不会扩大可见范围
+// 参数: currentUserID - 当前用户ID, currentUserRole - 当前用户角色, req - 筛选、排序和分页条件
 // 返回: []model.User - 用户列表, int64 - 总记录数, error - 查询是否成功
-func (r *UserRepository) ListByVisibility(currentUserID uint, currentUserRole string, offset, limit int) ([]model.User, int64, error) {
+func (r *UserRepository) ListByVisibility(currentUserID uint, currentUserRole string, req *model.UserListRequest) ([]model.User, int64, error) {
+	query := r.visibleUsersQuery(currentUserID, currentUserRole, req)
+
+	users, total, err := findPage[model.User](query, &req.ListQuery, model.UserSortFields, "created_at DESC")
+	if err != nil {
+		return nil, 0, fmt.Errorf("分页查询可见用户列表失败 [userID=%d, role=%s, page=%d, page_size=%d]: %w", currentUserID, currentUserRole, req.Page, req.PageSize, err)
+	}
+	return users, total, nil
+}
+
+// ListAllByVisibility 按可见性规则和筛选条件获取不分页的用户列表，用于导出
+// 参数: currentUserID - 当前用户ID, currentUserRole - 当前用户角色, req - 筛选和排序条件, limit - 最多返回条数
+// 返回: []model.User - 用户列表, error - 查询是否成功
+func (r *UserRepository) ListAllByVisibility(currentUserID uint, currentUserRole string, req *model.UserListRequest, limit int) ([]model.User, error) {
 	var users []model.User
-	var total int64
+	if err := r.visibleUsersQuery(currentUserID, currentUserRole, req).
+		Order(req.OrderBy(model.UserSortFields, "created_at DESC")).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("查询可见用户列表失败 [userID=%d, role=%s, limit=%d]: %w", currentUserID, currentUserRole, limit, err)
+	}
+	return users, nil
+}
 
+// visibleUsersQuery 构建当前用户可见范围内、满足筛选条件的用户查询
+func (r *UserRepository) visibleUsersQuery(currentUserID uint, currentUserRole string, req *model.UserListRequest) *gorm.DB {
 	query := r.db.Model(&model.User{})
 
 	// 超级管理员可以看到所有用户
@@ -126,70 +165,180 @@ func (r *UserRepository) ListByVisibility(currentUserID uint, currentUserRole st
 		query = query.Where("id = ?", currentUserID)
 	}
 
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, fmt.Errorf("统计可见用户总数失败 [userID=%d, role=%s]: %w", currentUserID, currentUserRole, err)
+	// 筛选条件
+	query = applyListQuery(query, &req.ListQuery, "username", "email")
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Role != "" {
+		// 主角色或通过 user_roles 分配的角色
+		query = query.Where("(role = ? OR EXISTS (SELECT 1 FROM user_roles JOIN roles ON roles.id = user_roles.role_id WHERE user_roles.user_id = users.id AND roles.code = ? AND roles.deleted_at IS NULL))", req.Role, req.Role)
+	}
+	if req.CreatedBy != nil {
+		query = query.Where("created_by = ?", *req.CreatedBy)
 	}
+	return query
+}
 
-	// 分页查询
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error; err != nil {
-		return nil, 0, fmt.Errorf("分页查询可见用户列表失败 [userID=%d, role=%s, offset=%d, limit=%d]: %w", currentUserID, currentUserRole, offset, limit, err)
+// ListByUsernamesOrEmails 获取用户名或邮箱在给定列表中的用户（包括已删除的用户，它们仍占用唯一索引）
+// 参数: usernames - 用户名列表, emails - 邮箱列表
+// 返回: []model.User - 用户列表, error - 查询是否成功
+func (r *UserRepository) ListByUsernamesOrEmails(usernames, emails []string) ([]model.User, error) {
+	var users []model.User
+	if len(usernames) == 0 && len(emails) == 0 {
+		return users, nil
 	}
-	return users, total, nil
+	if err := r.db.Unscoped().
+		Where("username IN ?", usernames).
+		Or("email IN ?", emails).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("按用户名或邮箱批量查询用户失败 [usernames=%d, emails=%d]: %w", len(usernames), len(emails), err)
+	}
+	return users, nil
 }
 
-// CheckUsernameExists 检查用户名是否已存在
+// CreateBatch 在一个事务中批量创建用户并分配角色，任一用户失败时全部回滚
+// 参数: users - 用户列表（创建后回填ID）, roleIDs - 与 users 一一对应的角色ID, assignedBy - 分配者ID
+// 返回: error - 操作是否成功
+func (r *UserRepository) CreateBatch(users []model.User, roleIDs []uint, assignedBy uint) error {
+	if len(users) != len(roleIDs) {
+		return fmt.Errorf("批量创建用户失败: 用户数 %d 与角色数 %d 不一致", len(users), len(roleIDs))
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(users, createBatchSize).Error; err != nil {
+			return err
+		}
+
+		userRoles := make([]model.UserRole, len(users))
+		for i := range users {
+			userRoles[i] = model.UserRole{
+				UserID:     users[i].ID,
+				RoleID:     roleIDs[i],
+				AssignedBy: assignedBy,
+			}
+		}
+		return tx.CreateInBatches(userRoles, createBatchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("批量创建用户失败 [count=%d]: %w", len(users), err)
+	}
+	return nil
+}
+
+// CheckUsernameExists 检查用户名是否已存在（包括已删除的用户，它们仍占用唯一索引）
 // 参数: username - 用户名
 // 返回: bool - 是否存在, error - 查询是否成功
 func (r *UserRepository) CheckUsernameExists(username string) (bool, error) {
 	var count int64
-	if err := r.db.Model(&model.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+	if err := r.db.Unscoped().Model(&model.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("检查用户名是否存在失败 [username=%s]: %w", username, err)
 	}
 	return count > 0, nil
 }
 
-// CheckEmailExists 检查邮箱是否已存在
+// CheckEmailExists 检查邮箱是否已存在（包括已删除的用户）
 // 参数: email - 邮箱地址
 // 返回: bool - 是否存在, error - 查询是否成功
 func (r *UserRepository) CheckEmailExists(email string) (bool, error) {
 	var count int64
-	if err := r.db.Model(&model.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+	if err := r.db.Unscoped().Model(&model.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("检查邮箱是否存在失败 [email=%s]: %w", email, err)
 	}
 	return count > 0, nil
 }
 
-// CheckUsernameExistsExcludeID 检查用户名是否已存在（排除指定ID）
+// CheckUsernameExistsExcludeID 检查用户名是否已存在（排除指定ID，包括已删除的用户）
 // 参数: username - 用户名, excludeID - 排除的用户ID（用于更新时排除自己）
 // 返回: bool - 是否存在, error - 查询是否成功
 func (r *UserRepository) CheckUsernameExistsExcludeID(username string, excludeID uint) (bool, error) {
 	var count int64
-	if err := r.db.Model(&model.User{}).Where("username = ? AND id != ?", username, excludeID).Count(&count).Error; err != nil {
+	if err := r.db.Unscoped().Model(&model.User{}).Where("username = ? AND id != ?", username, excludeID).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("检查用户名是否存在失败 [username=%s, excludeID=%d]: %w", username, excludeID, err)
 	}
 	return count > 0, nil
 }
 
-// CheckEmailExistsExcludeID 检查邮箱是否已存在（排除指定ID）
+// CheckEmailExistsExcludeID 检查邮箱是否已存在（排除指定ID，包括已删除的用户）
 // 参数: email - 邮箱地址, excludeID - 排除的用户ID（用于更新时排除自己）
 // 返回: bool - 是否存在, error - 查询是否成功
 func (r *UserRepository) CheckEmailExistsExcludeID(email string, excludeID uint) (bool, error) {
 	var count int64
-	if err := r.db.Model(&model.User{}).Where("email = ? AND id != ?", email, excludeID).Count(&count).Error; err != nil {
+	if err := r.db.Unscoped().Model(&model.User{}).Where("email = ? AND id != ?", email, excludeID).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("检查邮箱是否存在失败 [email=%s, excludeID=%d]: %w", email, excludeID, err)
 	}
 	return count > 0, nil
 }
 
-// UpdatePassword 更新用户密码
+// UpdatePassword 更新用户密码，同时清除必须修改密码的标记
 // 参数: userID - 用户ID, hashedPassword - 加密后的密码
 // 返回: error - 操作是否成功
 func (r *UserRepository) UpdatePassword(userID uint, hashedPassword string) error {
 	if err := r.db.Model(&model.User{}).
 		Where("id = ?", userID).
-		Update("password", hashedPassword).Error; err != nil {
+		Updates(map[string]interface{}{
+			"password":             hashedPassword,
+			"must_change_password": false,
+		}).Error; err != nil {
 		return fmt.Errorf("更新用户密码失败 [userID=%d]: %w", userID, err)
 	}
 	return nil
 }
+
+// ListDeleted 分页获取已删除的用户
+// 参数: query - 关键字、创建时间范围、排序和分页条件
+// 返回: []model.User - 用户列表, int64 - 总记录数, error - 查询是否成功
+func (r *UserRepository) ListDeleted(query *model.ListQuery) ([]model.User, int64, error) {
+	users, total, err := listDeleted[model.User](r.db, query, model.DeletedSortFields(model.UserSortFields), "username", "email")
+	if err != nil {
+		return nil, 0, fmt.Errorf("分页查询已删除用户失败 [page=%d, page_size=%d]: %w", query.Page, query.PageSize, err)
+	}
+	return users, total, nil
+}
+
+// GetDeletedByID 根据 ID 获取已删除的用户
+// 参数: id - 用户ID
+// 返回: *model.User - 用户对象, error - 查询是否成功（用户不存在或未删除时包装 gorm.ErrRecordNotFound）
+func (r *UserRepository) GetDeletedByID(id uint) (*model.User, error) {
+	user, err := getDeleted[model.User](r.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("根据ID查询已删除用户失败 [id=%d]: %w", id, err)
+	}
+	return user, nil
+}
+
+// Restore 恢复已删除的用户
+// 参数: id - 用户ID
+// 返回: error - 操作是否成功（用户不存在或未删除时包装 gorm.ErrRecordNotFound）
+func (r *UserRepository) Restore(id uint) error {
+	if err := restoreDeleted[model.User](r.db, id); err != nil {
+		return fmt.Errorf("恢复用户失败 [id=%d]: %w", id, err)
+	}
+	return nil
+}
+
+// Purge 彻底删除用户及其角色关联、密码历史、密码重置令牌和两步验证配置
+// 参数: id - 用户ID
+// 返回: error - 操作是否成功
+func (r *UserRepository) Purge(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, related := range []interface{}{
+			&model.UserRole{},
+			&model.PasswordHistory{},
+			&model.PasswordResetToken{},
+			&model.UserTwoFactor{},
+		} {
+			if err := tx.Where("user_id = ?", id).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&model.User{}, id).Error
+	})
+	if err != nil {
+		return fmt.Errorf("彻底删除用户失败 [id=%d]: %w", id, err)
+	}
+	return nil
+}
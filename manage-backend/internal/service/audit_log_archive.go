@@ -0,0 +1,114 @@
+package service
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+)
+
+// AuditLogArchive 审计日志归档器（gzip 压缩的 NDJSON，每行一条审计日志）
+// 每批日志单独成文件，文件名为该批的 ID 范围：删除事务内先写入临时文件并落盘，
+// 事务提交后再重命名为正式文件。事务回滚后重试同一批时生成同名文件，不会重复归档
+type AuditLogArchive struct {
+	dir   string
+	files []string
+	count int64
+
+	pendingTemp  string
+	pendingPath  string
+	pendingCount int64
+}
+
+// NewAuditLogArchive 创建归档器，目录在第一次归档时创建
+func NewAuditLogArchive(dir string) *AuditLogArchive {
+	return &AuditLogArchive{dir: dir}
+}
+
+// Stage 将一批审计日志写入临时文件，并同步到磁盘后才返回，保证删除前归档已落盘
+func (a *AuditLogArchive) Stage(logs []model.AuditLog) error {
+	a.Discard()
+	if len(logs) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(a.dir, 0750); err != nil {
+		return fmt.Errorf("创建归档目录失败: %w", err)
+	}
+
+	file, err := os.CreateTemp(a.dir, ".audit-logs-*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建归档文件失败: %w", err)
+	}
+	if err := writeArchiveFile(file, logs); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("关闭归档文件失败: %w", err)
+	}
+
+	a.pendingTemp = file.Name()
+	a.pendingPath = filepath.Join(a.dir, fmt.Sprintf("audit-logs-%010d-%010d.ndjson.gz", logs[0].ID, logs[len(logs)-1].ID))
+	a.pendingCount = int64(len(logs))
+	return nil
+}
+
+// Commit 删除事务提交后调用，将临时文件重命名为正式归档文件
+func (a *AuditLogArchive) Commit() error {
+	if a.pendingTemp == "" {
+		return nil
+	}
+	if err := os.Rename(a.pendingTemp, a.pendingPath); err != nil {
+		return fmt.Errorf("保存归档文件失败（临时文件 %s 已保留）: %w", a.pendingTemp, err)
+	}
+
+	a.files = append(a.files, a.pendingPath)
+	a.count += a.pendingCount
+	a.pendingTemp, a.pendingPath, a.pendingCount = "", "", 0
+	return nil
+}
+
+// Discard 删除事务回滚后调用，删除尚未提交的临时文件
+func (a *AuditLogArchive) Discard() {
+	if a.pendingTemp == "" {
+		return
+	}
+	os.Remove(a.pendingTemp)
+	a.pendingTemp, a.pendingPath, a.pendingCount = "", "", 0
+}
+
+// Files 返回已归档的文件路径
+func (a *AuditLogArchive) Files() []string {
+	return a.files
+}
+
+// Count 返回已归档的条数
+func (a *AuditLogArchive) Count() int64 {
+	return a.count
+}
+
+// writeArchiveFile 将审计日志以 gzip 压缩的 NDJSON 写入文件并同步到磁盘
+func writeArchiveFile(file *os.File, logs []model.AuditLog) error {
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+	encoder.SetEscapeHTML(false)
+	for i := range logs {
+		if err := encoder.Encode(&logs[i]); err != nil {
+			return fmt.Errorf("写入归档文件失败: %w", err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("写入归档文件失败: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("同步归档文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readArchiveFile(t *testing.T, path string) []model.AuditLog {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var restored []model.AuditLog
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var log model.AuditLog
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &log))
+		restored = append(restored, log)
+	}
+	require.NoError(t, scanner.Err())
+	return restored
+}
+
+func TestAuditLogArchive(t *testing.T) {
+	t.Run("no file without logs", func(t *testing.T) {
+		dir := t.TempDir()
+		archive := NewAuditLogArchive(dir)
+		require.NoError(t, archive.Stage(nil))
+		require.NoError(t, archive.Commit())
+		assert.Empty(t, archive.Files())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("writes one gzipped ndjson file per committed batch", func(t *testing.T) {
+		repo := newChain(3)
+		dir := t.TempDir()
+		archive := NewAuditLogArchive(dir)
+		require.NoError(t, archive.Stage(repo.logs[:2]))
+		require.NoError(t, archive.Commit())
+		require.NoError(t, archive.Stage(repo.logs[2:]))
+		require.NoError(t, archive.Commit())
+		assert.Equal(t, int64(3), archive.Count())
+		require.Equal(t, []string{
+			filepath.Join(dir, "audit-logs-0000000001-0000000002.ndjson.gz"),
+			filepath.Join(dir, "audit-logs-0000000003-0000000003.ndjson.gz"),
+		}, archive.Files())
+
+		restored := readArchiveFile(t, archive.Files()[0])
+		require.Len(t, restored, 2)
+		assert.Equal(t, repo.logs[1].Hash, restored[1].Hash)
+		assert.Equal(t, restored[0].Hash, restored[1].PrevHash)
+	})
+
+	t.Run("rolled back batch is discarded and retried without duplicates", func(t *testing.T) {
+		repo := newChain(2)
+		dir := t.TempDir()
+		archive := NewAuditLogArchive(dir)
+
+		require.NoError(t, archive.Stage(repo.logs))
+		archive.Discard()
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		require.NoError(t, archive.Stage(repo.logs))
+		require.NoError(t, archive.Commit())
+		// 重试同一批时覆盖同名文件
+		retry := NewAuditLogArchive(dir)
+		require.NoError(t, retry.Stage(repo.logs))
+		require.NoError(t, retry.Commit())
+
+		assert.Equal(t, archive.Files(), retry.Files())
+		entries, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Len(t, readArchiveFile(t, retry.Files()[0]), 2)
+	})
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/repository"
 	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
@@ -13,20 +17,50 @@ import (
 // AuditLogRepositoryInterface 定义审计日志仓库接口
 type AuditLogRepositoryInterface interface {
 	Create(log *model.AuditLog) error
+	CreateBatch(logs []*model.AuditLog) error
 	GetByID(id uint) (*model.AuditLog, error)
 	Query(query *model.AuditLogQuery) ([]model.AuditLog, int64, error)
-	DeleteOldLogs(days int) error
+	StreamQuery(query *model.AuditLogQuery, batchSize int, fn func([]model.AuditLog) error) error
+	StreamChain(startTime, endTime time.Time, batchSize int, fn func([]model.AuditLog) error) error
+	GetPreviousEntry(id uint) (*model.AuditLog, error)
+	GetCheckpointByHash(hash string) (*model.AuditLogCheckpoint, error)
+	DeleteOldLogs(days int, batchSize int, checkpointKey []byte, archiver repository.AuditLogArchiver) (int64, error)
+}
+
+// AuditLogWriter 审计日志异步写入器（由 middleware.AuditWriter 实现）
+// 服务层记录的审计日志与请求审计日志经同一队列按顺序写入，哈希链不会因并发写入而交错
+type AuditLogWriter interface {
+	Write(auditLog *model.AuditLog)
 }
 
 // AuditLogService 审计日志业务服务
 type AuditLogService struct {
-	auditLogRepo AuditLogRepositoryInterface
+	auditLogRepo  AuditLogRepositoryInterface
+	auditWriter   AuditLogWriter // 为空时（如命令行工具）直接写入数据库
+	checkpointKey []byte         // 清理检查点的签名密钥
 }
 
 // NewAuditLogService 创建 AuditLogService 实例
-func NewAuditLogService(auditLogRepo AuditLogRepositoryInterface) *AuditLogService {
+func NewAuditLogService(auditLogRepo AuditLogRepositoryInterface, auditWriter AuditLogWriter, checkpointKey []byte) *AuditLogService {
 	return &AuditLogService{
-		auditLogRepo: auditLogRepo,
+		auditLogRepo:  auditLogRepo,
+		auditWriter:   auditWriter,
+		checkpointKey: checkpointKey,
+	}
+}
+
+// Record 记录一条审计日志，经审计日志写入器异步写入
+func (s *AuditLogService) Record(auditLog *model.AuditLog) {
+	if s.auditWriter != nil {
+		s.auditWriter.Write(auditLog)
+		return
+	}
+
+	if err := s.auditLogRepo.Create(auditLog); err != nil {
+		logger.Error("保存审计日志失败",
+			zap.Uint("user_id", auditLog.UserID),
+			zap.String("action", auditLog.Action),
+			zap.Error(err))
 	}
 }
 
@@ -79,25 +113,245 @@ func (s *AuditLogService) Query(query *model.AuditLogQuery) ([]model.AuditLogRes
 	return responses, total, nil
 }
 
-// CleanOldLogs 清理旧的审计日志
-func (s *AuditLogService) CleanOldLogs(days int) error {
+// AuditLogRequestInfo 发起操作的请求信息，用于在服务层为单个请求写入多条审计日志
+type AuditLogRequestInfo struct {
+	UserID    uint
+	Username  string
+	IP        string
+	UserAgent string
+	Method    string
+	Path      string
+	RequestID string
+}
+
+// RecordResourceActions 为批量操作中每个受影响的资源各写入一条审计日志，detail 写入请求体字段
+// 请求本身仍由审计中间件记录一条日志
+func (s *AuditLogService) RecordResourceActions(info AuditLogRequestInfo, action, resource string, resourceIDs []string, detail string) {
+	if len(resourceIDs) == 0 {
+		return
+	}
+
+	for _, resourceID := range resourceIDs {
+		s.Record(&model.AuditLog{
+			UserID:      info.UserID,
+			Username:    info.Username,
+			Action:      action,
+			Resource:    resource,
+			ResourceID:  resourceID,
+			Method:      info.Method,
+			Path:        info.Path,
+			IP:          info.IP,
+			UserAgent:   info.UserAgent,
+			Status:      200,
+			RequestBody: detail,
+			RequestID:   info.RequestID,
+		})
+	}
+}
+
+// exportBatchSize 导出时每批从数据库读取的条数
+const exportBatchSize = 500
+
+// AuditLogExportInfo 审计日志导出操作信息（用于记录导出本身的审计日志）
+type AuditLogExportInfo struct {
+	UserID    uint
+	Username  string
+	IP        string
+	UserAgent string
+	Path      string
+	RequestID string
+	Format    string
+	Query     *model.AuditLogQuery
+}
+
+// Export 按条件流式导出审计日志，每条记录调用一次 fn，返回导出的条数
+// 分页参数会被忽略；fn 返回错误时中止导出（通常是客户端断开连接）
+func (s *AuditLogService) Export(query *model.AuditLogQuery, fn func(*model.AuditLogResponse) error) (int64, error) {
+	var count int64
+	err := s.auditLogRepo.StreamQuery(query, exportBatchSize, func(logs []model.AuditLog) error {
+		for i := range logs {
+			if err := fn(s.toAuditLogResponse(&logs[i])); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("导出审计日志失败",
+			zap.Int64("exported", count),
+			zap.Error(err),
+			zap.String("operation", "export_audit_logs"))
+		return count, apperrors.NewAuditLogExportFailedError()
+	}
+
+	return count, nil
+}
+
+// RecordExport 记录审计日志导出操作，导出条件和条数写入请求体字段
+func (s *AuditLogService) RecordExport(info AuditLogExportInfo, count int64, exportErr error) {
+	detail, _ := json.Marshal(map[string]interface{}{
+		"format": info.Format,
+		"filter": info.Query,
+		"count":  count,
+	})
+
+	auditLog := &model.AuditLog{
+		UserID:      info.UserID,
+		Username:    info.Username,
+		Action:      "导出审计日志",
+		Resource:    "audit-logs",
+		Method:      "GET",
+		Path:        info.Path,
+		IP:          info.IP,
+		UserAgent:   info.UserAgent,
+		Status:      200,
+		RequestBody: string(detail),
+		RequestID:   info.RequestID,
+	}
+	if exportErr != nil {
+		auditLog.Status = 500
+		auditLog.ErrorMsg = fmt.Sprintf("导出中断: %v", exportErr)
+	}
+
+	s.Record(auditLog)
+}
+
+// defaultCleanBatchSize 清理审计日志时每批删除的条数
+const defaultCleanBatchSize = 1000
+
+// CleanOldLogs 分批清理旧的审计日志，返回删除条数
+// archiver 不为空时，每批日志在删除前先交给 archiver 归档，归档失败则该批不会被删除
+func (s *AuditLogService) CleanOldLogs(days int, batchSize int, archiver repository.AuditLogArchiver) (int64, error) {
 	if days < 1 {
 		days = 90 // 默认保留90天
 	}
+	if batchSize < 1 {
+		batchSize = defaultCleanBatchSize
+	}
 
-	err := s.auditLogRepo.DeleteOldLogs(days)
+	deleted, err := s.auditLogRepo.DeleteOldLogs(days, batchSize, s.checkpointKey, archiver)
 	if err != nil {
 		logger.Error("清理旧审计日志失败",
 			zap.Int("days", days),
+			zap.Int64("deleted", deleted),
 			zap.Error(err),
 			zap.String("operation", "clean_old_logs"))
-		return apperrors.NewAuditLogCleanFailedError()
+		return deleted, apperrors.NewAuditLogCleanFailedError()
 	}
 
 	logger.Info("清理旧审计日志成功",
 		zap.Int("days", days),
+		zap.Int64("deleted", deleted),
 		zap.String("operation", "clean_old_logs"))
-	return nil
+	return deleted, nil
+}
+
+// Verify 校验时间范围内审计日志的哈希链，返回第一处断裂
+// 起点依次尝试锚定到：范围之前的日志、清理检查点、链的第一条日志
+func (s *AuditLogService) Verify(startTime, endTime time.Time) (*model.AuditLogVerifyResult, error) {
+	result := &model.AuditLogVerifyResult{
+		Valid:     true,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	var (
+		started      bool
+		chained      bool   // 是否已进入哈希链（之前的历史日志没有哈希）
+		expectedPrev string // 下一条日志应有的 PrevHash
+	)
+
+	errBroken := errors.New("哈希链断裂")
+	fail := func(id uint, reason string) error {
+		result.Valid = false
+		result.FirstBrokenID = &id
+		result.Reason = reason
+		return errBroken
+	}
+
+	err := s.auditLogRepo.StreamChain(startTime, endTime, exportBatchSize, func(logs []model.AuditLog) error {
+		for i := range logs {
+			log := &logs[i]
+
+			if !started {
+				started = true
+				anchor, prevHash, reason, err := s.resolveAnchor(log)
+				if err != nil {
+					return err
+				}
+				if reason != "" {
+					return fail(log.ID, reason)
+				}
+				result.Anchor = anchor
+				expectedPrev = prevHash
+				chained = prevHash != ""
+			}
+
+			if log.Hash == "" {
+				if chained {
+					return fail(log.ID, "缺少哈希")
+				}
+				result.Unchained++
+				continue
+			}
+			chained = true
+
+			if log.PrevHash != expectedPrev {
+				return fail(log.ID, "与上一条日志的哈希不一致（日志被删除或插入）")
+			}
+			if log.ComputeHash() != log.Hash {
+				return fail(log.ID, "日志内容被篡改")
+			}
+
+			expectedPrev = log.Hash
+			result.Checked++
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBroken) {
+		logger.Error("校验审计日志哈希链失败",
+			zap.Error(err),
+			zap.String("operation", "verify_audit_logs"))
+		return nil, apperrors.NewAuditLogVerifyFailedError()
+	}
+
+	if !result.Valid {
+		logger.Warn("审计日志哈希链校验未通过",
+			zap.Uint("first_broken_id", *result.FirstBrokenID),
+			zap.String("reason", result.Reason),
+			zap.String("operation", "verify_audit_logs"))
+	}
+	return result, nil
+}
+
+// resolveAnchor 确定校验范围第一条日志的锚点，返回锚定方式和期望的 PrevHash
+// reason 非空表示无法锚定（日志链的起点被篡改）
+func (s *AuditLogService) resolveAnchor(first *model.AuditLog) (anchor, prevHash, reason string, err error) {
+	prev, err := s.auditLogRepo.GetPreviousEntry(first.ID)
+	if err == nil {
+		return model.AuditAnchorPreviousEntry, prev.Hash, "", nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", "", err
+	}
+
+	// 之前没有日志：要么是链的第一条，要么之前的日志已被清理
+	if first.PrevHash == "" {
+		return model.AuditAnchorGenesis, "", "", nil
+	}
+
+	checkpoint, err := s.auditLogRepo.GetCheckpointByHash(first.PrevHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", "之前的日志已被删除且没有对应的清理检查点", nil
+		}
+		return "", "", "", err
+	}
+	if !checkpoint.VerifySignature(s.checkpointKey) {
+		return "", "", "清理检查点签名无效", nil
+	}
+	return model.AuditAnchorCheckpoint, checkpoint.LastEntryHash, "", nil
 }
 
 // toAuditLogResponse 转换为响应结构
@@ -117,6 +371,9 @@ func (s *AuditLogService) toAuditLogResponse(log *model.AuditLog) *model.AuditLo
 		ErrorMsg:    log.ErrorMsg,
 		RequestBody: log.RequestBody,
 		Duration:    log.Duration,
+		PrevHash:    log.PrevHash,
+		Hash:        log.Hash,
+		RequestID:   log.RequestID,
 		CreatedAt:   log.CreatedAt,
 	}
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeAuditLogRepo 内存中的审计日志仓库，仅实现哈希链校验所需的方法
+type fakeAuditLogRepo struct {
+	AuditLogRepositoryInterface
+	logs        []model.AuditLog
+	checkpoints []model.AuditLogCheckpoint
+}
+
+func (r *fakeAuditLogRepo) append(action string) {
+	log := model.AuditLog{
+		ID:        uint(len(r.logs) + 1),
+		Action:    action,
+		Status:    200,
+		CreatedAt: time.Date(2025, 10, 1, 0, 0, len(r.logs), 123456000, time.UTC),
+	}
+	if len(r.logs) > 0 {
+		log.PrevHash = r.logs[len(r.logs)-1].Hash
+	}
+	log.Hash = log.ComputeHash()
+	r.logs = append(r.logs, log)
+}
+
+func (r *fakeAuditLogRepo) CreateBatch(logs []*model.AuditLog) error {
+	for _, log := range logs {
+		log.ID = uint(len(r.logs) + 1)
+		r.logs = append(r.logs, *log)
+	}
+	return nil
+}
+
+func (r *fakeAuditLogRepo) StreamChain(startTime, endTime time.Time, batchSize int, fn func([]model.AuditLog) error) error {
+	return fn(r.logs)
+}
+
+func (r *fakeAuditLogRepo) GetPreviousEntry(id uint) (*model.AuditLog, error) {
+	for i := len(r.logs) - 1; i >= 0; i-- {
+		if r.logs[i].ID < id {
+			return &r.logs[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeAuditLogRepo) GetCheckpointByHash(hash string) (*model.AuditLogCheckpoint, error) {
+	for i := range r.checkpoints {
+		if r.checkpoints[i].LastEntryHash == hash {
+			return &r.checkpoints[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// purge 模拟清理前 n 条日志并写入检查点
+func (r *fakeAuditLogRepo) purge(n int, key []byte) {
+	last := r.logs[n-1]
+	checkpoint := model.AuditLogCheckpoint{
+		LastEntryID:   last.ID,
+		LastEntryHash: last.Hash,
+		LastEntryAt:   last.CreatedAt,
+		PurgedCount:   int64(n),
+		Cutoff:        last.CreatedAt.Add(time.Second),
+	}
+	checkpoint.Signature = checkpoint.ComputeSignature(key)
+	r.checkpoints = append(r.checkpoints, checkpoint)
+	r.logs = r.logs[n:]
+}
+
+func newChain(n int) *fakeAuditLogRepo {
+	repo := &fakeAuditLogRepo{}
+	for i := 0; i < n; i++ {
+		repo.append("用户登录")
+	}
+	return repo
+}
+
+func TestVerifyAuditLogChain(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	key := []byte("checkpoint-secret")
+
+	t.Run("intact chain", func(t *testing.T) {
+		result, err := NewAuditLogService(newChain(5), nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(5), result.Checked)
+		assert.Equal(t, model.AuditAnchorGenesis, result.Anchor)
+	})
+
+	t.Run("tampered content", func(t *testing.T) {
+		repo := newChain(5)
+		repo.logs[2].Action = "删除资源"
+
+		result, err := NewAuditLogService(repo, nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotNil(t, result.FirstBrokenID)
+		assert.Equal(t, uint(3), *result.FirstBrokenID)
+	})
+
+	t.Run("deleted entry", func(t *testing.T) {
+		repo := newChain(5)
+		repo.logs = append(repo.logs[:2], repo.logs[3:]...)
+
+		result, err := NewAuditLogService(repo, nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint(4), *result.FirstBrokenID)
+	})
+
+	t.Run("purged with checkpoint", func(t *testing.T) {
+		repo := newChain(5)
+		repo.purge(2, key)
+
+		result, err := NewAuditLogService(repo, nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(3), result.Checked)
+		assert.Equal(t, model.AuditAnchorCheckpoint, result.Anchor)
+	})
+
+	t.Run("forged checkpoint", func(t *testing.T) {
+		repo := newChain(5)
+		repo.purge(2, []byte("other-secret"))
+
+		result, err := NewAuditLogService(repo, nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint(3), *result.FirstBrokenID)
+	})
+
+	t.Run("purged without checkpoint", func(t *testing.T) {
+		repo := newChain(5)
+		repo.logs = repo.logs[2:]
+
+		result, err := NewAuditLogService(repo, nil, key).Verify(time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, uint(3), *result.FirstBrokenID)
+	})
+}
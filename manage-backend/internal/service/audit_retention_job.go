@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/repository"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AuditRetentionJob 审计日志定时归档与清理任务
+type AuditRetentionJob struct {
+	auditLogService *AuditLogService
+	config          config.AuditRetentionConfig
+	stop            chan struct{}
+	done            chan struct{}
+}
+
+// NewAuditRetentionJob 创建审计日志定时归档与清理任务
+func NewAuditRetentionJob(auditLogService *AuditLogService, cfg config.AuditRetentionConfig) *AuditRetentionJob {
+	if cfg.Days < 1 {
+		cfg.Days = 90
+	}
+	if cfg.IntervalHours < 1 {
+		cfg.IntervalHours = 24
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = defaultCleanBatchSize
+	}
+
+	return &AuditRetentionJob{
+		auditLogService: auditLogService,
+		config:          cfg,
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// Start 启动后台任务：启动时先执行一次，之后按间隔执行
+func (j *AuditRetentionJob) Start() {
+	logger.Info("审计日志保留任务已启动",
+		zap.Int("days", j.config.Days),
+		zap.Int("interval_hours", j.config.IntervalHours),
+		zap.String("archive_dir", j.config.ArchiveDir))
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(time.Duration(j.config.IntervalHours) * time.Hour)
+		defer ticker.Stop()
+
+		j.RunOnce()
+		for {
+			select {
+			case <-ticker.C:
+				j.RunOnce()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台任务，等待正在执行的一次归档完成
+func (j *AuditRetentionJob) Stop(ctx context.Context) error {
+	close(j.stop)
+
+	select {
+	case <-j.done:
+		logger.FromContext(ctx).Info("审计日志保留任务已停止")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunOnce 执行一次归档与清理
+func (j *AuditRetentionJob) RunOnce() {
+	startTime := time.Now()
+
+	var archive *AuditLogArchive
+	var archiver repository.AuditLogArchiver
+	if j.config.ArchiveDir != "" {
+		archive = NewAuditLogArchive(j.config.ArchiveDir)
+		archiver = archive
+	}
+
+	deleted, err := j.auditLogService.CleanOldLogs(j.config.Days, j.config.BatchSize, archiver)
+
+	var archived int64
+	var archiveFiles []string
+	if archive != nil {
+		archived = archive.Count()
+		archiveFiles = archive.Files()
+	}
+
+	fields := []zap.Field{
+		zap.Int("days", j.config.Days),
+		zap.Int64("archived", archived),
+		zap.Int64("deleted", deleted),
+		zap.Strings("archive_files", archiveFiles),
+		zap.Duration("duration", time.Since(startTime)),
+	}
+	if err != nil {
+		logger.Error("审计日志保留任务执行失败", append(fields, zap.Error(err))...)
+		return
+	}
+	logger.Info("审计日志保留任务执行完成", fields...)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
 	"github.com/mojocn/base64Captcha"
 	"github.com/redis/go-redis/v9"
 )
@@ -88,6 +89,7 @@ func (s *CaptchaService) GenerateCaptcha() (*CaptchaResponse, error) {
 	// 记录生成的验证码答案（用于调试，生产环境应移除）
 	// log.Printf("Generated captcha ID: %s, Answer: %s", id, answer)
 	_ = answer // 避免未使用变量警告
+	metrics.CaptchaGeneratedTotal.Inc()
 	
 	return &CaptchaResponse{
 		CaptchaID:   id,
@@ -98,10 +100,17 @@ func (s *CaptchaService) GenerateCaptcha() (*CaptchaResponse, error) {
 // VerifyCaptcha 验证验证码
 func (s *CaptchaService) VerifyCaptcha(captchaID, captchaValue string) bool {
 	if captchaID == "" || captchaValue == "" {
+		metrics.CaptchaVerificationsTotal.WithLabelValues("invalid").Inc()
 		return false
 	}
 	
-	return s.store.Verify(captchaID, captchaValue, true) // true 表示验证后清除
+	ok := s.store.Verify(captchaID, captchaValue, true) // true 表示验证后清除
+	if ok {
+		metrics.CaptchaVerificationsTotal.WithLabelValues("valid").Inc()
+	} else {
+		metrics.CaptchaVerificationsTotal.WithLabelValues("invalid").Inc()
+	}
+	return ok
 }
 
 // RedisCaptchaStore Redis 验证码存储实现
@@ -18,10 +18,14 @@ type DictTypeRepositoryInterface interface {
 	GetByCode(code string) (*model.DictType, error)
 	Update(dictType *model.DictType) error
 	Delete(id uint) error
-	List(offset, limit int, status, keyword string) ([]model.DictType, int64, error)
+	List(req *model.DictTypeListRequest) ([]model.DictType, int64, error)
 	GetAll() ([]model.DictType, error)
 	CheckCodeExists(code string) (bool, error)
 	CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error)
+	ListDeleted(query *model.ListQuery) ([]model.DictType, int64, error)
+	GetDeletedByID(id uint) (*model.DictType, error)
+	Restore(id uint) error
+	Purge(id uint) error
 }
 
 // DictItemRepositoryInterface 定义字典项仓库接口
@@ -31,7 +35,7 @@ type DictItemRepositoryInterface interface {
 	GetByTypeCodeAndValue(typeCode, value string) (*model.DictItem, error)
 	Update(dictItem *model.DictItem) error
 	Delete(id uint) error
-	List(offset, limit int, typeCode, status string) ([]model.DictItem, int64, error)
+	List(req *model.DictItemListRequest) ([]model.DictItem, int64, error)
 	GetByTypeCode(typeCode string, activeOnly bool) ([]model.DictItem, error)
 	CheckValueExists(typeCode, value string) (bool, error)
 	CheckValueExistsExcludeID(typeCode, value string, excludeID uint) (bool, error)
@@ -283,28 +287,28 @@ func (s *DictTypeService) Delete(id uint) error {
 }
 
 // List 获取字典类型列表
-func (s *DictTypeService) List(page, pageSize int, status, keyword string) ([]model.DictType, int64, error) {
+func (s *DictTypeService) List(req *model.DictTypeListRequest) ([]model.DictType, int64, error) {
 	logger.Debug("查询字典类型列表",
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
-		zap.String("status", status),
-		zap.String("keyword", keyword),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
+		zap.String("status", req.Status),
+		zap.String("keyword", req.Keyword),
+		zap.String("sort_by", req.SortBy),
 		zap.String("operation", "list_dict_types"))
 
-	offset := (page - 1) * pageSize
-	dictTypes, total, err := s.dictTypeRepo.List(offset, pageSize, status, keyword)
+	dictTypes, total, err := s.dictTypeRepo.List(req)
 	if err != nil {
 		logger.Error("查询字典类型列表失败",
-			zap.Int("page", page),
-			zap.Int("page_size", pageSize),
+			zap.Int("page", req.Page),
+			zap.Int("page_size", req.PageSize),
 			zap.Error(err),
 			zap.String("operation", "list_dict_types"))
 		return nil, 0, apperrors.NewDictTypeListFailedError()
 	}
 
 	logger.Debug("字典类型列表查询成功",
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
 		zap.Int64("total", total),
 		zap.Int("returned_count", len(dictTypes)),
 		zap.String("operation", "list_dict_types"))
@@ -327,6 +331,93 @@ func (s *DictTypeService) GetAll() ([]model.DictType, error) {
 	return dictTypes, nil
 }
 
+// ListDeleted 获取回收站中的字典类型列表
+func (s *DictTypeService) ListDeleted(query *model.ListQuery) ([]model.DictTypeResponse, int64, error) {
+	dictTypes, total, err := s.dictTypeRepo.ListDeleted(query)
+	if err != nil {
+		logger.Error("查询已删除字典类型列表失败",
+			zap.Int("page", query.Page),
+			zap.Int("page_size", query.PageSize),
+			zap.Error(err),
+			zap.String("operation", "list_deleted_dict_types"))
+		return nil, 0, apperrors.NewRecycleBinListFailedError()
+	}
+
+	responses := make([]model.DictTypeResponse, len(dictTypes))
+	for i := range dictTypes {
+		responses[i] = *repository.ConvertDictTypeToResponse(&dictTypes[i])
+	}
+
+	return responses, total, nil
+}
+
+// Restore 从回收站恢复字典类型，其下已删除的字典项不会随之恢复
+func (s *DictTypeService) Restore(id uint) error {
+	dictType, err := s.getDeletedDictType(id, "restore_dict_type")
+	if err != nil {
+		return err
+	}
+
+	if err := s.dictTypeRepo.Restore(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewRecycleBinNotFoundError("字典类型不存在或未被删除")
+		}
+		logger.Error("恢复字典类型失败",
+			zap.Uint("id", id),
+			zap.Error(err),
+			zap.String("operation", "restore_dict_type"))
+		return apperrors.NewRecycleBinRestoreFailedError()
+	}
+
+	logger.Info("字典类型恢复成功",
+		zap.Uint("id", id),
+		zap.String("code", dictType.Code),
+		zap.String("operation", "restore_dict_type"))
+
+	return nil
+}
+
+// Purge 彻底删除回收站中的字典类型及其下所有字典项
+// 彻底删除后字典类型代码可以被重新使用
+func (s *DictTypeService) Purge(id uint) error {
+	dictType, err := s.getDeletedDictType(id, "purge_dict_type")
+	if err != nil {
+		return err
+	}
+
+	if err := s.dictTypeRepo.Purge(id); err != nil {
+		logger.Error("彻底删除字典类型失败",
+			zap.Uint("id", id),
+			zap.String("code", dictType.Code),
+			zap.Error(err),
+			zap.String("operation", "purge_dict_type"))
+		return apperrors.NewRecycleBinPurgeFailedError()
+	}
+
+	logger.Info("字典类型彻底删除成功",
+		zap.Uint("id", id),
+		zap.String("code", dictType.Code),
+		zap.String("operation", "purge_dict_type"))
+
+	return nil
+}
+
+// getDeletedDictType 获取回收站中的字典类型
+func (s *DictTypeService) getDeletedDictType(id uint, operation string) (*model.DictType, error) {
+	dictType, err := s.dictTypeRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewRecycleBinNotFoundError("字典类型不存在或未被删除")
+		}
+		logger.Error("查询已删除字典类型失败",
+			zap.Uint("id", id),
+			zap.Error(err),
+			zap.String("operation", operation))
+		return nil, apperrors.NewDictTypeGetFailedError()
+	}
+	return dictType, nil
+}
+
 // ==================== DictItemService ====================
 
 // DictItemService 字典项业务服务
@@ -595,28 +686,29 @@ func (s *DictItemService) Delete(id uint) error {
 }
 
 // List 获取字典项列表
-func (s *DictItemService) List(page, pageSize int, typeCode, status string) ([]model.DictItem, int64, error) {
+func (s *DictItemService) List(req *model.DictItemListRequest) ([]model.DictItem, int64, error) {
 	logger.Debug("查询字典项列表",
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
-		zap.String("dict_type_code", typeCode),
-		zap.String("status", status),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
+		zap.String("dict_type_code", req.DictTypeCode),
+		zap.String("status", req.Status),
+		zap.String("keyword", req.Keyword),
+		zap.String("sort_by", req.SortBy),
 		zap.String("operation", "list_dict_items"))
 
-	offset := (page - 1) * pageSize
-	dictItems, total, err := s.dictItemRepo.List(offset, pageSize, typeCode, status)
+	dictItems, total, err := s.dictItemRepo.List(req)
 	if err != nil {
 		logger.Error("查询字典项列表失败",
-			zap.Int("page", page),
-			zap.Int("page_size", pageSize),
+			zap.Int("page", req.Page),
+			zap.Int("page_size", req.PageSize),
 			zap.Error(err),
 			zap.String("operation", "list_dict_items"))
 		return nil, 0, apperrors.NewDictItemListFailedError()
 	}
 
 	logger.Debug("字典项列表查询成功",
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
 		zap.Int64("total", total),
 		zap.Int("returned_count", len(dictItems)),
 		zap.String("operation", "list_dict_items"))
@@ -24,17 +24,7 @@ type emailService struct {
 // NewEmailService 创建邮件服务实例
 func NewEmailService(cfg *config.Config) EmailService {
 	// 创建SMTP拨号器（可复用）
-	dialer := gomail.NewDialer(
-		cfg.Email.SMTPHost,
-		cfg.Email.SMTPPort,
-		cfg.Email.Username,
-		cfg.Email.Password,
-	)
-
-	// 如果使用465端口（SSL），需要设置
-	if cfg.Email.SMTPPort == 465 {
-		dialer.SSL = true
-	}
+	dialer := NewSMTPDialer(cfg.Email)
 
 	logger.Info("邮件服务初始化成功",
 		zap.String("smtp_host", cfg.Email.SMTPHost),
@@ -47,6 +37,23 @@ func NewEmailService(cfg *config.Config) EmailService {
 	}
 }
 
+// NewSMTPDialer 根据邮件配置创建 SMTP 拨号器
+func NewSMTPDialer(cfg config.EmailConfig) *gomail.Dialer {
+	dialer := gomail.NewDialer(
+		cfg.SMTPHost,
+		cfg.SMTPPort,
+		cfg.Username,
+		cfg.Password,
+	)
+
+	// 如果使用465端口（SSL），需要设置
+	if cfg.SMTPPort == 465 {
+		dialer.SSL = true
+	}
+
+	return dialer
+}
+
 // SendPasswordResetEmail 发送密码重置邮件
 func (s *emailService) SendPasswordResetEmail(to, token, username string) error {
 	logger.Info("开始发送密码重置邮件",
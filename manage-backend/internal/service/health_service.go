@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/casbin/casbin/v2"
+	"go.uber.org/zap"
+	"gopkg.in/gomail.v2"
+	"gorm.io/gorm"
+)
+
+// HealthCheck 依赖组件检查项
+// Optional 为 true 的组件失败时只标记为降级，不影响就绪状态
+type HealthCheck struct {
+	Name     string
+	Optional bool
+	Check    func(ctx context.Context) error
+}
+
+// HealthService 就绪检查服务
+// 并发执行所有检查项，结果缓存 cacheTTL，避免频繁的探针请求压到数据库和 Redis
+type HealthService struct {
+	checks   []HealthCheck
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	cached *model.HealthReport
+}
+
+// NewHealthService 创建就绪检查服务
+func NewHealthService(checks []HealthCheck, timeout, cacheTTL time.Duration) *HealthService {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &HealthService{
+		checks:   checks,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Check 返回就绪检查结果，缓存未过期时直接返回上次结果
+func (s *HealthService) Check(ctx context.Context) *model.HealthReport {
+	// 加锁期间执行检查，同一时刻只有一个请求真正访问依赖组件
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.cached.CheckedAt) < s.cacheTTL {
+		return s.cached
+	}
+
+	// 结果会被其他请求复用，不受本次请求取消的影响
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	report := &model.HealthReport{
+		Status:     model.HealthStatusUp,
+		Components: make(map[string]model.ComponentHealth, len(s.checks)),
+		CheckedAt:  time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			startTime := time.Now()
+			err := check.Check(ctx)
+			component := model.ComponentHealth{
+				Status:    model.HealthStatusUp,
+				LatencyMs: float64(time.Since(startTime).Microseconds()) / 1000,
+			}
+			if err != nil {
+				component.Status = model.HealthStatusDown
+				if check.Optional {
+					component.Status = model.HealthStatusDegraded
+				}
+				component.Error = err.Error()
+			}
+
+			resultMu.Lock()
+			report.Components[check.Name] = component
+			if component.Status == model.HealthStatusDown {
+				report.Status = model.HealthStatusDown
+			} else if component.Status == model.HealthStatusDegraded && report.Status == model.HealthStatusUp {
+				report.Status = model.HealthStatusDegraded
+			}
+			resultMu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	if report.Status != model.HealthStatusUp {
+		logger.FromContext(ctx).Warn("就绪检查存在异常组件",
+			zap.String("status", report.Status),
+			zap.Any("components", report.Components))
+	}
+
+	s.cached = report
+	return report
+}
+
+// DatabaseHealthCheck 检查数据库连接池是否可用
+func DatabaseHealthCheck(db *gorm.DB) HealthCheck {
+	return HealthCheck{
+		Name: "database",
+		Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+	}
+}
+
+// RedisHealthCheck 检查 Redis 连接是否可用
+func RedisHealthCheck(redisClient *cache.RedisClient) HealthCheck {
+	return HealthCheck{
+		Name: "redis",
+		Check: func(ctx context.Context) error {
+			return redisClient.Ping(ctx)
+		},
+	}
+}
+
+// CasbinHealthCheck 检查 Casbin 策略能否读取
+// 策略表为空是合法状态（例如刚初始化的系统），只有读取出错才视为失败
+func CasbinHealthCheck(enforcer *casbin.Enforcer) HealthCheck {
+	return HealthCheck{
+		Name: "casbin",
+		Check: func(ctx context.Context) error {
+			_, err := enforcer.GetPolicy()
+			return err
+		},
+	}
+}
+
+// SMTPHealthCheck 检查 SMTP 服务器能否连接并完成认证
+// SMTP 只影响邮件发送，失败时标记为降级
+func SMTPHealthCheck(cfg config.EmailConfig) HealthCheck {
+	dialer := NewSMTPDialer(cfg)
+	return HealthCheck{
+		Name:     "smtp",
+		Optional: true,
+		Check: func(ctx context.Context) error {
+			return dialSMTP(ctx, dialer)
+		},
+	}
+}
+
+// dialSMTP 按 gomail 的方式建立连接并完成 TLS 握手和认证
+// gomail 不支持 context，这里用 net/smtp 实现，连接和后续读写都受 ctx 超时控制
+func dialSMTP(ctx context.Context, dialer *gomail.Dialer) error {
+	var netDialer net.Dialer
+	conn, err := netDialer.DialContext(ctx, "tcp", net.JoinHostPort(dialer.Host, strconv.Itoa(dialer.Port)))
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	tlsConfig := dialer.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: dialer.Host}
+	}
+	if dialer.SSL {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, dialer.Host)
+	if err != nil {
+		return fmt.Errorf("连接 SMTP 服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	if !dialer.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("SMTP STARTTLS 失败: %w", err)
+			}
+		}
+	}
+	if dialer.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", dialer.Username, dialer.Password, dialer.Host)); err != nil {
+				return fmt.Errorf("SMTP 认证失败: %w", err)
+			}
+		}
+	}
+	return client.Quit()
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/gomail.v2"
+)
+
+func TestHealthServiceCheck(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	t.Run("all components up", func(t *testing.T) {
+		service := NewHealthService([]HealthCheck{
+			{Name: "database", Check: func(ctx context.Context) error { return nil }},
+			{Name: "redis", Check: func(ctx context.Context) error { return nil }},
+		}, time.Second, 0)
+
+		report := service.Check(context.Background())
+		assert.Equal(t, model.HealthStatusUp, report.Status)
+		assert.Len(t, report.Components, 2)
+		assert.Equal(t, model.HealthStatusUp, report.Components["redis"].Status)
+	})
+
+	t.Run("one component down", func(t *testing.T) {
+		service := NewHealthService([]HealthCheck{
+			{Name: "database", Check: func(ctx context.Context) error { return nil }},
+			{Name: "redis", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		}, time.Second, 0)
+
+		report := service.Check(context.Background())
+		assert.Equal(t, model.HealthStatusDown, report.Status)
+		assert.Equal(t, model.HealthStatusUp, report.Components["database"].Status)
+		assert.Equal(t, model.HealthStatusDown, report.Components["redis"].Status)
+		assert.Equal(t, "connection refused", report.Components["redis"].Error)
+	})
+
+	t.Run("optional component degraded", func(t *testing.T) {
+		service := NewHealthService([]HealthCheck{
+			{Name: "database", Check: func(ctx context.Context) error { return nil }},
+			{Name: "smtp", Optional: true, Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		}, time.Second, 0)
+
+		report := service.Check(context.Background())
+		assert.Equal(t, model.HealthStatusDegraded, report.Status)
+		assert.Equal(t, model.HealthStatusDegraded, report.Components["smtp"].Status)
+		assert.Equal(t, "connection refused", report.Components["smtp"].Error)
+	})
+
+	t.Run("required failure outranks degraded", func(t *testing.T) {
+		service := NewHealthService([]HealthCheck{
+			{Name: "database", Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+			{Name: "smtp", Optional: true, Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		}, time.Second, 0)
+
+		report := service.Check(context.Background())
+		assert.Equal(t, model.HealthStatusDown, report.Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		service := NewHealthService([]HealthCheck{
+			{Name: "redis", Check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+		}, 10*time.Millisecond, 0)
+
+		report := service.Check(context.Background())
+		assert.Equal(t, model.HealthStatusDown, report.Status)
+	})
+
+	t.Run("cached result", func(t *testing.T) {
+		var calls atomic.Int32
+		service := NewHealthService([]HealthCheck{
+			{Name: "database", Check: func(ctx context.Context) error {
+				calls.Add(1)
+				return nil
+			}},
+		}, time.Second, time.Minute)
+
+		service.Check(context.Background())
+		service.Check(context.Background())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestDialSMTPHonoursContext(t *testing.T) {
+	// 服务器接受连接但不发送问候语，检查应在超时后返回而不是一直阻塞
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+	err = dialSMTP(ctx, gomail.NewDialer("127.0.0.1", addr.Port, "", ""))
+	assert.Error(t, err)
+	assert.Less(t, time.Since(startTime), time.Second)
+}
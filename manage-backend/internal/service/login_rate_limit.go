@@ -2,12 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
 	"go.uber.org/zap"
 )
 
@@ -21,63 +29,68 @@ const (
 	
 	// 账户锁定 - login:locked:account:{username}
 	RedisKeyLoginLockedAccount = "login:locked:account:%s"
-)
 
-// 限流配置
-const (
-	// IP级别：每小时最多10次登录尝试
-	MaxLoginAttemptsPerIP = 10
-	IPLimitWindow         = 1 * time.Hour
-	
-	// 账户级别：连续5次失败后锁定
-	MaxLoginFailsPerAccount = 5
-	AccountLockDuration     = 15 * time.Minute
-	FailCountWindow         = 15 * time.Minute
+	// 账户锁定次数（exponential 锁定方式） - login:lockcount:account:{username}
+	RedisKeyLoginLockCountAccount = "login:lockcount:account:%s"
 )
 
 // LoginRateLimitService 登录限流服务
+// 限流次数、窗口和锁定时长从安全策略服务读取，管理员修改后立即生效
 type LoginRateLimitService struct {
-	redisClient *cache.RedisClient
+	redisClient    *cache.RedisClient
+	securityPolicy *SecurityPolicyService
 }
 
 // NewLoginRateLimitService 创建登录限流服务
-func NewLoginRateLimitService(redisClient *cache.RedisClient) *LoginRateLimitService {
+func NewLoginRateLimitService(redisClient *cache.RedisClient, securityPolicy *SecurityPolicyService) *LoginRateLimitService {
 	return &LoginRateLimitService{
-		redisClient: redisClient,
+		redisClient:    redisClient,
+		securityPolicy: securityPolicy,
 	}
 }
 
+// policy 返回当前的登录安全策略
+func (s *LoginRateLimitService) policy() config.LoginSecurityConfig {
+	return s.securityPolicy.Get().Login
+}
+
+// MaxFailsPerAccount 返回账户锁定前允许的连续失败次数
+func (s *LoginRateLimitService) MaxFailsPerAccount() int {
+	return s.policy().MaxFailsPerAccount
+}
+
 // CheckIPRateLimit 检查IP限流
 // 返回：是否允许，剩余次数，错误
 func (s *LoginRateLimitService) CheckIPRateLimit(ctx context.Context, ip string) (bool, int, error) {
 	key := fmt.Sprintf(RedisKeyLoginLimitIP, ip)
+	policy := s.policy()
 	
 	// 递增计数
 	count, err := s.redisClient.Incr(ctx, key)
 	if err != nil {
-		logger.Error("IP登录限流计数失败", zap.String("ip", ip), zap.Error(err))
+		logger.FromContext(ctx).Error("IP登录限流计数失败", zap.String("ip", ip), zap.Error(err))
 		return false, 0, err
 	}
 	
 	// 第一次请求，设置过期时间
 	if count == 1 {
-		if err := s.redisClient.Expire(ctx, key, IPLimitWindow); err != nil {
-			logger.Error("设置IP登录限流过期时间失败", zap.String("ip", ip), zap.Error(err))
+		if err := s.redisClient.Expire(ctx, key, time.Duration(policy.IPWindowMinutes)*time.Minute); err != nil {
+			logger.FromContext(ctx).Error("设置IP登录限流过期时间失败", zap.String("ip", ip), zap.Error(err))
 		}
 	}
 	
-	remaining := int(MaxLoginAttemptsPerIP - count)
+	remaining := policy.MaxAttemptsPerIP - int(count)
 	if remaining < 0 {
 		remaining = 0
 	}
 	
-	allowed := count <= MaxLoginAttemptsPerIP
+	allowed := count <= int64(policy.MaxAttemptsPerIP)
 	
 	if !allowed {
-		logger.Warn("IP登录请求频率超限",
+		logger.FromContext(ctx).Warn("IP登录请求频率超限",
 			zap.String("ip", ip),
 			zap.Int64("count", count),
-			zap.Int("max", MaxLoginAttemptsPerIP))
+			zap.Int("max", policy.MaxAttemptsPerIP))
 	}
 	
 	return allowed, remaining, nil
@@ -90,7 +103,7 @@ func (s *LoginRateLimitService) CheckAccountLocked(ctx context.Context, username
 	
 	exists, err := s.redisClient.Exists(ctx, key)
 	if err != nil {
-		logger.Error("检查账户锁定状态失败", zap.String("username", username), zap.Error(err))
+		logger.FromContext(ctx).Error("检查账户锁定状态失败", zap.String("username", username), zap.Error(err))
 		return false, 0, err
 	}
 	
@@ -98,11 +111,11 @@ func (s *LoginRateLimitService) CheckAccountLocked(ctx context.Context, username
 		// 账户已锁定，获取剩余时间
 		ttl, err := s.redisClient.TTL(ctx, key)
 		if err != nil {
-			logger.Error("获取账户锁定TTL失败", zap.String("username", username), zap.Error(err))
+			logger.FromContext(ctx).Error("获取账户锁定TTL失败", zap.String("username", username), zap.Error(err))
 			return true, 0, err
 		}
 		
-		logger.Warn("账户处于锁定状态",
+		logger.FromContext(ctx).Warn("账户处于锁定状态",
 			zap.String("username", username),
 			zap.Duration("remaining", ttl))
 		
@@ -116,74 +129,119 @@ func (s *LoginRateLimitService) CheckAccountLocked(ctx context.Context, username
 // 返回：当前失败次数，是否应该锁定账户，错误
 func (s *LoginRateLimitService) RecordLoginFailure(ctx context.Context, username string) (int, bool, error) {
 	key := fmt.Sprintf(RedisKeyLoginFailAccount, username)
+	policy := s.policy()
 	
 	// 递增失败计数
 	count, err := s.redisClient.Incr(ctx, key)
 	if err != nil {
-		logger.Error("记录登录失败次数失败", zap.String("username", username), zap.Error(err))
+		logger.FromContext(ctx).Error("记录登录失败次数失败", zap.String("username", username), zap.Error(err))
 		return 0, false, err
 	}
 	
 	// 第一次失败，设置过期时间
 	if count == 1 {
-		if err := s.redisClient.Expire(ctx, key, FailCountWindow); err != nil {
-			logger.Error("设置失败计数过期时间失败", zap.String("username", username), zap.Error(err))
+		if err := s.redisClient.Expire(ctx, key, time.Duration(policy.FailWindowMinutes)*time.Minute); err != nil {
+			logger.FromContext(ctx).Error("设置失败计数过期时间失败", zap.String("username", username), zap.Error(err))
 		}
 	}
 	
-	logger.Info("记录登录失败",
+	logger.FromContext(ctx).Info("记录登录失败",
 		zap.String("username", username),
 		zap.Int64("fail_count", count),
-		zap.Int("max", MaxLoginFailsPerAccount))
+		zap.Int("max", policy.MaxFailsPerAccount))
 	
 	// 检查是否达到锁定阈值
-	shouldLock := count >= int64(MaxLoginFailsPerAccount)
+	shouldLock := count >= int64(policy.MaxFailsPerAccount)
 	
 	if shouldLock {
 		// 锁定账户
-		if err := s.LockAccount(ctx, username); err != nil {
-			logger.Error("锁定账户失败", zap.String("username", username), zap.Error(err))
+		duration, err := s.LockAccount(ctx, username)
+		if err != nil {
+			logger.FromContext(ctx).Error("锁定账户失败", zap.String("username", username), zap.Error(err))
 			return int(count), true, err
 		}
 		
-		logger.Warn("账户因连续失败被锁定",
+		// 解锁后重新计算失败次数
+		if err := s.redisClient.Del(ctx, key); err != nil {
+			logger.FromContext(ctx).Error("重置登录失败次数失败", zap.String("username", username), zap.Error(err))
+		}
+		
+		metrics.AccountLockoutsTotal.Inc()
+		logger.FromContext(ctx).Warn("账户因连续失败被锁定",
 			zap.String("username", username),
 			zap.Int64("fail_count", count),
-			zap.Duration("lock_duration", AccountLockDuration))
+			zap.Duration("lock_duration", duration))
 	}
 	
 	return int(count), shouldLock, nil
 }
 
-// LockAccount 锁定账户
-func (s *LoginRateLimitService) LockAccount(ctx context.Context, username string) error {
+// LockAccount 锁定账户，返回本次锁定时长
+func (s *LoginRateLimitService) LockAccount(ctx context.Context, username string) (time.Duration, error) {
 	key := fmt.Sprintf(RedisKeyLoginLockedAccount, username)
+	policy := s.policy()
 	
-	lockReason := fmt.Sprintf("连续登录失败%d次", MaxLoginFailsPerAccount)
+	// exponential 方式按保留期内的锁定次数翻倍锁定时长
+	lockCount := int64(1)
+	if policy.LockoutMode == config.LockoutModeExponential {
+		countKey := fmt.Sprintf(RedisKeyLoginLockCountAccount, username)
+		count, err := s.redisClient.Incr(ctx, countKey)
+		if err != nil {
+			logger.FromContext(ctx).Error("记录账户锁定次数失败", zap.String("username", username), zap.Error(err))
+		} else {
+			lockCount = count
+			if err := s.redisClient.Expire(ctx, countKey, time.Duration(policy.LockoutResetHours)*time.Hour); err != nil {
+				logger.FromContext(ctx).Error("设置账户锁定次数过期时间失败", zap.String("username", username), zap.Error(err))
+			}
+		}
+	}
+	duration := lockoutDuration(policy, lockCount)
+	
+	lockReason := fmt.Sprintf("连续登录失败%d次", policy.MaxFailsPerAccount)
 	
-	if err := s.redisClient.Set(ctx, key, lockReason, AccountLockDuration); err != nil {
-		logger.Error("锁定账户失败", zap.String("username", username), zap.Error(err))
-		return fmt.Errorf("锁定账户失败: %w", err)
+	if err := s.redisClient.Set(ctx, key, lockReason, duration); err != nil {
+		logger.FromContext(ctx).Error("锁定账户失败", zap.String("username", username), zap.Error(err))
+		return 0, fmt.Errorf("锁定账户失败: %w", err)
 	}
 	
-	logger.Info("账户已锁定",
+	logger.FromContext(ctx).Info("账户已锁定",
 		zap.String("username", username),
 		zap.String("reason", lockReason),
-		zap.Duration("duration", AccountLockDuration))
+		zap.Int64("lock_count", lockCount),
+		zap.Duration("duration", duration))
 	
-	return nil
+	return duration, nil
+}
+
+// lockoutDuration 计算第 lockCount 次锁定的时长
+// fixed 方式固定为 LockDurationMinutes；exponential 方式为 LockDurationMinutes * 2^(lockCount-1)，不超过 MaxLockDurationMinutes
+func lockoutDuration(policy config.LoginSecurityConfig, lockCount int64) time.Duration {
+	duration := time.Duration(policy.LockDurationMinutes) * time.Minute
+	if policy.LockoutMode != config.LockoutModeExponential {
+		return duration
+	}
+	
+	maxDuration := time.Duration(policy.MaxLockDurationMinutes) * time.Minute
+	for i := int64(1); i < lockCount && duration < maxDuration; i++ {
+		duration *= 2
+	}
+	if duration > maxDuration {
+		duration = maxDuration
+	}
+	return duration
 }
 
-// ClearLoginFailures 清除登录失败记录（登录成功时调用）
+// ClearLoginFailures 清除登录失败记录和锁定次数（登录成功时调用）
 func (s *LoginRateLimitService) ClearLoginFailures(ctx context.Context, username string) error {
 	key := fmt.Sprintf(RedisKeyLoginFailAccount, username)
+	countKey := fmt.Sprintf(RedisKeyLoginLockCountAccount, username)
 	
-	if err := s.redisClient.Del(ctx, key); err != nil {
-		logger.Error("清除登录失败记录失败", zap.String("username", username), zap.Error(err))
+	if err := s.redisClient.Del(ctx, key, countKey); err != nil {
+		logger.FromContext(ctx).Error("清除登录失败记录失败", zap.String("username", username), zap.Error(err))
 		return err
 	}
 	
-	logger.Debug("已清除登录失败记录", zap.String("username", username))
+	logger.FromContext(ctx).Debug("已清除登录失败记录", zap.String("username", username))
 	return nil
 }
 
@@ -196,7 +254,7 @@ func (s *LoginRateLimitService) GetFailureCount(ctx context.Context, username st
 		if err == redis.Nil {
 			return 0, nil // 没有失败记录
 		}
-		logger.Error("获取失败次数失败", zap.String("username", username), zap.Error(err))
+		logger.FromContext(ctx).Error("获取失败次数失败", zap.String("username", username), zap.Error(err))
 		return 0, err
 	}
 	
@@ -207,15 +265,225 @@ func (s *LoginRateLimitService) GetFailureCount(ctx context.Context, username st
 
 // GetRemainingAttempts 获取剩余尝试次数
 func (s *LoginRateLimitService) GetRemainingAttempts(ctx context.Context, username string) int {
+	maxFails := s.MaxFailsPerAccount()
 	count, err := s.GetFailureCount(ctx, username)
 	if err != nil {
-		return MaxLoginFailsPerAccount // 出错时返回最大值
+		return maxFails // 出错时返回最大值
 	}
 	
-	remaining := MaxLoginFailsPerAccount - count
+	remaining := maxFails - count
 	if remaining < 0 {
 		remaining = 0
 	}
 	
 	return remaining
 }
+
+// LockedUsernames 批量查询账户是否处于锁定状态
+func (s *LoginRateLimitService) LockedUsernames(ctx context.Context, usernames []string) (map[string]bool, error) {
+	locked := make(map[string]bool, len(usernames))
+	if len(usernames) == 0 {
+		return locked, nil
+	}
+
+	pipe := s.redisClient.GetClient().Pipeline()
+	cmds := make([]*redis.IntCmd, len(usernames))
+	for i, username := range usernames {
+		cmds[i] = pipe.Exists(ctx, fmt.Sprintf(RedisKeyLoginLockedAccount, username))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("查询账户锁定状态失败: %w", err)
+	}
+
+	for i, username := range usernames {
+		if cmds[i].Val() > 0 {
+			locked[username] = true
+		}
+	}
+	return locked, nil
+}
+
+// ListAccountLocks 查询已锁定和有登录失败记录的账户，按用户名排序
+func (s *LoginRateLimitService) ListAccountLocks(ctx context.Context, query *model.AccountLockQuery) ([]model.AccountLockEntry, error) {
+	rdb := s.redisClient.GetClient()
+	pattern := escapeGlob(query.Username) + "*"
+
+	lockedPrefix := fmt.Sprintf(RedisKeyLoginLockedAccount, "")
+	keys, err := scanRedisKeys(ctx, rdb, lockedPrefix+pattern)
+	if err != nil {
+		logger.FromContext(ctx).Error("扫描账户锁定记录失败", zap.Error(err))
+		return nil, apperrors.NewLoginLockQueryFailedError()
+	}
+	usernames := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		usernames[strings.TrimPrefix(key, lockedPrefix)] = struct{}{}
+	}
+
+	if !query.LockedOnly {
+		failPrefix := fmt.Sprintf(RedisKeyLoginFailAccount, "")
+		keys, err := scanRedisKeys(ctx, rdb, failPrefix+pattern)
+		if err != nil {
+			logger.FromContext(ctx).Error("扫描登录失败记录失败", zap.Error(err))
+			return nil, apperrors.NewLoginLockQueryFailedError()
+		}
+		for _, key := range keys {
+			usernames[strings.TrimPrefix(key, failPrefix)] = struct{}{}
+		}
+	}
+
+	type accountCmds struct {
+		username  string
+		reason    *redis.StringCmd
+		ttl       *redis.DurationCmd
+		failCount *redis.StringCmd
+		lockCount *redis.StringCmd
+	}
+	pipe := rdb.Pipeline()
+	cmds := make([]accountCmds, 0, len(usernames))
+	for username := range usernames {
+		lockedKey := fmt.Sprintf(RedisKeyLoginLockedAccount, username)
+		cmds = append(cmds, accountCmds{
+			username:  username,
+			reason:    pipe.Get(ctx, lockedKey),
+			ttl:       pipe.PTTL(ctx, lockedKey),
+			failCount: pipe.Get(ctx, fmt.Sprintf(RedisKeyLoginFailAccount, username)),
+			lockCount: pipe.Get(ctx, fmt.Sprintf(RedisKeyLoginLockCountAccount, username)),
+		})
+	}
+	if len(cmds) > 0 {
+		// 没有锁定或失败记录的键返回 redis.Nil，按 0 处理
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			logger.FromContext(ctx).Error("查询账户锁定状态失败", zap.Error(err))
+			return nil, apperrors.NewLoginLockQueryFailedError()
+		}
+	}
+
+	maxFails := s.MaxFailsPerAccount()
+	entries := make([]model.AccountLockEntry, 0, len(cmds))
+	for _, cmd := range cmds {
+		ttl := cmd.ttl.Val()
+		failCount, _ := strconv.Atoi(cmd.failCount.Val())
+		lockCount, _ := strconv.Atoi(cmd.lockCount.Val())
+		locked := ttl > 0
+		if !locked && (query.LockedOnly || failCount == 0) {
+			// 扫描后已过期
+			continue
+		}
+
+		entry := model.AccountLockEntry{
+			Username:  cmd.username,
+			Locked:    locked,
+			FailCount: failCount,
+			MaxFails:  maxFails,
+			LockCount: lockCount,
+		}
+		if locked {
+			entry.Reason = cmd.reason.Val()
+			entry.LockTTL = int64((ttl + time.Second - 1) / time.Second)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Username < entries[j].Username
+	})
+	return entries, nil
+}
+
+// UnlockAccount 解除账户锁定并清零失败次数，锁定次数保留，用于 exponential 方式继续计算下一次锁定时长
+func (s *LoginRateLimitService) UnlockAccount(ctx context.Context, username string) error {
+	lockedKey := fmt.Sprintf(RedisKeyLoginLockedAccount, username)
+	failKey := fmt.Sprintf(RedisKeyLoginFailAccount, username)
+
+	if err := s.redisClient.Del(ctx, lockedKey, failKey); err != nil {
+		logger.FromContext(ctx).Error("解除账户锁定失败", zap.String("username", username), zap.Error(err))
+		return apperrors.NewLoginLockClearFailedError()
+	}
+
+	logger.FromContext(ctx).Info("账户已解除锁定", zap.String("username", username))
+	return nil
+}
+
+// ResetAccountFailures 清零账户的失败次数和锁定次数，不解除当前的锁定
+func (s *LoginRateLimitService) ResetAccountFailures(ctx context.Context, username string) error {
+	if err := s.ClearLoginFailures(ctx, username); err != nil {
+		return apperrors.NewLoginLockClearFailedError()
+	}
+
+	logger.FromContext(ctx).Info("账户登录失败次数已重置", zap.String("username", username))
+	return nil
+}
+
+// ListIPBlocks 查询当前窗口内有登录记录的 IP，按 IP 排序
+func (s *LoginRateLimitService) ListIPBlocks(ctx context.Context, query *model.IPBlockQuery) ([]model.IPBlockEntry, error) {
+	rdb := s.redisClient.GetClient()
+	prefix := fmt.Sprintf(RedisKeyLoginLimitIP, "")
+	keys, err := scanRedisKeys(ctx, rdb, prefix+escapeGlob(query.IP)+"*")
+	if err != nil {
+		logger.FromContext(ctx).Error("扫描登录 IP 限流记录失败", zap.Error(err))
+		return nil, apperrors.NewLoginLockQueryFailedError()
+	}
+
+	type ipCmds struct {
+		ip       string
+		attempts *redis.StringCmd
+		ttl      *redis.DurationCmd
+	}
+	pipe := rdb.Pipeline()
+	cmds := make([]ipCmds, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, ipCmds{
+			ip:       strings.TrimPrefix(key, prefix),
+			attempts: pipe.Get(ctx, key),
+			ttl:      pipe.PTTL(ctx, key),
+		})
+	}
+	if len(cmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			logger.FromContext(ctx).Error("查询登录 IP 限流状态失败", zap.Error(err))
+			return nil, apperrors.NewLoginLockQueryFailedError()
+		}
+	}
+
+	maxAttempts := s.policy().MaxAttemptsPerIP
+	entries := make([]model.IPBlockEntry, 0, len(cmds))
+	for _, cmd := range cmds {
+		attempts, _ := strconv.Atoi(cmd.attempts.Val())
+		if attempts == 0 {
+			continue
+		}
+		// 计数先递增再比较，达到上限后下一次登录即被拒绝
+		blocked := attempts >= maxAttempts
+		if query.BlockedOnly && !blocked {
+			continue
+		}
+
+		ttl := cmd.ttl.Val()
+		if ttl < 0 {
+			ttl = 0
+		}
+		entries = append(entries, model.IPBlockEntry{
+			IP:          cmd.ip,
+			Attempts:    attempts,
+			MaxAttempts: maxAttempts,
+			Blocked:     blocked,
+			ResetIn:     int64((ttl + time.Second - 1) / time.Second),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].IP < entries[j].IP
+	})
+	return entries, nil
+}
+
+// UnblockIP 清除 IP 的登录次数，解除封禁
+func (s *LoginRateLimitService) UnblockIP(ctx context.Context, ip string) error {
+	if err := s.redisClient.Del(ctx, fmt.Sprintf(RedisKeyLoginLimitIP, ip)); err != nil {
+		logger.FromContext(ctx).Error("解除 IP 登录封禁失败", zap.String("ip", ip), zap.Error(err))
+		return apperrors.NewLoginLockClearFailedError()
+	}
+
+	logger.FromContext(ctx).Info("IP 登录封禁已解除", zap.String("ip", ip))
+	return nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
@@ -25,6 +26,11 @@ type MenuRepositoryInterface interface {
 	GetByPermissionCodes(codes []string) ([]model.Menu, error)
 	GetMenusWithoutPermission() ([]model.Menu, error)
 	HasChildren(id uint) (bool, error)
+	HasAnyChildren(id uint) (bool, error)
+	ListDeleted(query *model.ListQuery) ([]model.Menu, int64, error)
+	GetDeletedByID(id uint) (*model.Menu, error)
+	Restore(id uint) error
+	Purge(id uint) error
 }
 
 // MenuService 菜单业务服务
@@ -276,10 +282,10 @@ func (s *MenuService) GetUserMenuTree(userID uint, roleRepo RoleRepositoryInterf
 	userPermissions := make(map[string]bool)
 	for _, role := range roles {
 		// 从 role_permissions 表获取权限ID
-		permissionIDs, err := roleRepo.GetRolePermissionIDs(role.ID)
+		permissionIDs, err := roleRepo.GetRolePermissionIDs(context.Background(), role.ID)
 		if err == nil {
 			// 根据权限ID获取权限详情
-			permissions, err := s.permissionRepo.GetByIDs(permissionIDs)
+			permissions, err := s.permissionRepo.GetByIDs(context.Background(), permissionIDs)
 			if err == nil {
 				for _, perm := range permissions {
 					userPermissions[perm.Code] = true
@@ -346,6 +352,7 @@ func (s *MenuService) toMenuResponse(menu *model.Menu) *model.MenuResponse {
 		Status:         menu.Status,
 		CreatedAt:      menu.CreatedAt,
 		UpdatedAt:      menu.UpdatedAt,
+		DeletedAt:      model.DeletedTime(menu.DeletedAt),
 	}
 }
 
@@ -372,3 +379,93 @@ func (s *MenuService) UpdateMenuOrder(updates []model.MenuOrderUpdate) error {
 	logger.Info("批量更新菜单顺序成功", zap.Int("count", len(updates)))
 	return nil
 }
+
+// ListDeleted 分页获取回收站中的菜单
+func (s *MenuService) ListDeleted(query *model.ListQuery) ([]model.MenuResponse, int64, error) {
+	menus, total, err := s.menuRepo.ListDeleted(query)
+	if err != nil {
+		logger.Error("获取已删除菜单列表失败", zap.Error(err))
+		return nil, 0, apperrors.NewRecycleBinListFailedError()
+	}
+
+	responses := make([]model.MenuResponse, len(menus))
+	for i, menu := range menus {
+		responses[i] = *s.toMenuResponse(&menu)
+	}
+
+	return responses, total, nil
+}
+
+// Restore 从回收站恢复菜单，父菜单必须存在且未被删除
+func (s *MenuService) Restore(id uint) error {
+	menu, err := s.getDeletedMenu(id)
+	if err != nil {
+		return err
+	}
+
+	// 父菜单仍在回收站中时需要先恢复父菜单
+	if menu.ParentID != nil {
+		if _, err := s.menuRepo.GetByID(*menu.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.NewMenuParentNotFoundError()
+			}
+			logger.Error("获取父菜单失败", zap.Uint("parent_id", *menu.ParentID), zap.Error(err))
+			return apperrors.NewMenuParentGetFailedError()
+		}
+	}
+
+	if err := s.menuRepo.Restore(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewRecycleBinNotFoundError("菜单不存在或未被删除")
+		}
+		logger.Error("恢复菜单失败", zap.Uint("menu_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinRestoreFailedError()
+	}
+
+	logger.Info("恢复菜单成功",
+		zap.Uint("menu_id", id),
+		zap.String("name", menu.Name))
+
+	return nil
+}
+
+// Purge 彻底删除回收站中的菜单，存在子菜单（包括已删除的子菜单）时不允许删除
+func (s *MenuService) Purge(id uint) error {
+	menu, err := s.getDeletedMenu(id)
+	if err != nil {
+		return err
+	}
+
+	hasChildren, err := s.menuRepo.HasAnyChildren(id)
+	if err != nil {
+		logger.Error("检查子菜单失败", zap.Uint("menu_id", id), zap.Error(err))
+		return apperrors.NewMenuCheckChildrenFailedError()
+	}
+	if hasChildren {
+		return apperrors.NewMenuHasChildrenError()
+	}
+
+	if err := s.menuRepo.Purge(id); err != nil {
+		logger.Error("彻底删除菜单失败", zap.Uint("menu_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinPurgeFailedError()
+	}
+
+	logger.Info("彻底删除菜单成功",
+		zap.Uint("menu_id", id),
+		zap.String("name", menu.Name))
+
+	return nil
+}
+
+// getDeletedMenu 获取回收站中的菜单
+func (s *MenuService) getDeletedMenu(id uint) (*model.Menu, error) {
+	menu, err := s.menuRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewRecycleBinNotFoundError("菜单不存在或未被删除")
+		}
+		logger.Error("获取已删除菜单失败", zap.Uint("menu_id", id), zap.Error(err))
+		return nil, apperrors.NewMenuGetFailedError()
+	}
+	return menu, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// minUserInfoLength 用户名或邮箱前缀达到该长度才检查密码是否包含它们，避免误伤过短的用户名
+const minUserInfoLength = 3
+
+// PasswordHistoryRepositoryInterface 密码历史仓库接口
+type PasswordHistoryRepositoryInterface interface {
+	Create(ctx context.Context, history *model.PasswordHistory) error
+	ListRecent(ctx context.Context, userID uint, limit int) ([]model.PasswordHistory, error)
+	Prune(ctx context.Context, userID uint, keep int) error
+}
+
+// PasswordPolicyService 密码策略服务
+// 按安全策略校验密码强度，并记录历史密码以禁止重复使用
+type PasswordPolicyService struct {
+	securityPolicy *SecurityPolicyService
+	historyRepo    PasswordHistoryRepositoryInterface
+}
+
+// NewPasswordPolicyService 创建密码策略服务
+func NewPasswordPolicyService(securityPolicy *SecurityPolicyService, historyRepo PasswordHistoryRepositoryInterface) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		securityPolicy: securityPolicy,
+		historyRepo:    historyRepo,
+	}
+}
+
+// policy 返回当前生效的密码策略
+func (s *PasswordPolicyService) policy() config.PasswordSecurityConfig {
+	return s.securityPolicy.Get().Password
+}
+
+// ValidateStrength 校验密码长度、字符类型、禁用密码列表以及是否包含用户信息
+func (s *PasswordPolicyService) ValidateStrength(password, username, email string) error {
+	return checkPasswordStrength(s.policy(), password, username, email)
+}
+
+// CheckReuse 检查新密码是否与当前密码或最近的历史密码相同
+// history_count 包含当前密码，因此只需再比对 history_count-1 条历史记录
+func (s *PasswordPolicyService) CheckReuse(ctx context.Context, user *model.User, password string) error {
+	if user.Password != "" && utils.CheckPassword(password, user.Password) {
+		return apperrors.NewWeakPasswordError("新密码不能与当前密码相同")
+	}
+
+	count := s.policy().HistoryCount
+	if count <= 1 {
+		return nil
+	}
+
+	histories, err := s.historyRepo.ListRecent(ctx, user.ID, count-1)
+	if err != nil {
+		logger.FromContext(ctx).Error("查询密码历史失败",
+			zap.Uint("user_id", user.ID),
+			zap.Error(err),
+			zap.String("operation", "check_password_reuse"))
+		return apperrors.NewUserQueryFailedError()
+	}
+	for _, history := range histories {
+		if utils.CheckPassword(password, history.PasswordHash) {
+			return apperrors.NewWeakPasswordError(fmt.Sprintf("不能使用最近%d次使用过的密码", count))
+		}
+	}
+	return nil
+}
+
+// Record 密码修改成功后记录被替换的旧密码哈希
+// 始终保留上限条数，管理员调大 history_count 后立即对已有记录生效
+func (s *PasswordPolicyService) Record(ctx context.Context, userID uint, oldPasswordHash string) error {
+	if oldPasswordHash == "" {
+		return nil
+	}
+	if err := s.historyRepo.Create(ctx, &model.PasswordHistory{
+		UserID:       userID,
+		PasswordHash: oldPasswordHash,
+	}); err != nil {
+		return err
+	}
+	return s.historyRepo.Prune(ctx, userID, config.MaxPasswordHistoryCount)
+}
+
+// checkPasswordStrength 按密码策略校验密码，不符合时返回说明原因的 CodeWeakPassword 错误
+func checkPasswordStrength(policy config.PasswordSecurityConfig, password, username, email string) error {
+	if len([]rune(password)) < policy.MinLength {
+		return apperrors.NewWeakPasswordError(fmt.Sprintf("密码长度不能少于%d位", policy.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var missing []string
+	if policy.RequireUppercase && !hasUpper {
+		missing = append(missing, "大写字母")
+	}
+	if policy.RequireLowercase && !hasLower {
+		missing = append(missing, "小写字母")
+	}
+	if policy.RequireDigit && !hasDigit {
+		missing = append(missing, "数字")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return apperrors.NewWeakPasswordError("密码必须包含" + strings.Join(missing, "、"))
+	}
+
+	lower := strings.ToLower(password)
+	for _, banned := range policy.BannedPasswords {
+		if lower == strings.ToLower(strings.TrimSpace(banned)) {
+			return apperrors.NewWeakPasswordError("密码过于常见，请更换")
+		}
+	}
+
+	if policy.DisallowUserInfo {
+		localPart, _, _ := strings.Cut(email, "@")
+		for _, info := range []string{username, localPart} {
+			info = strings.ToLower(strings.TrimSpace(info))
+			if len([]rune(info)) >= minUserInfoLength && strings.Contains(lower, info) {
+				return apperrors.NewWeakPasswordError("密码不能包含用户名或邮箱")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePasswordHistoryRepo 内存中的密码历史仓库，按写入顺序保存
+type fakePasswordHistoryRepo struct {
+	histories []model.PasswordHistory
+}
+
+func (r *fakePasswordHistoryRepo) Create(ctx context.Context, history *model.PasswordHistory) error {
+	history.CreatedAt = time.Now()
+	r.histories = append(r.histories, *history)
+	return nil
+}
+
+func (r *fakePasswordHistoryRepo) ListRecent(ctx context.Context, userID uint, limit int) ([]model.PasswordHistory, error) {
+	var recent []model.PasswordHistory
+	for i := len(r.histories) - 1; i >= 0 && len(recent) < limit; i-- {
+		if r.histories[i].UserID == userID {
+			recent = append(recent, r.histories[i])
+		}
+	}
+	return recent, nil
+}
+
+func (r *fakePasswordHistoryRepo) Prune(ctx context.Context, userID uint, keep int) error {
+	return nil
+}
+
+func TestCheckPasswordStrength(t *testing.T) {
+	policy := defaultSecurityConfig().Password
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"valid", "blue7horse", false},
+		{"too short", "ab1", true},
+		{"missing digit", "bluehorses", true},
+		{"missing lowercase", "BLUE7HORSE", true},
+		{"banned case insensitive", "PassWord1", true},
+		{"contains username", "xalice2024", true},
+		{"contains email local part", "9alice.w9", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPasswordStrength(policy, tt.password, "alice", "alice.w@example.com")
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *apperrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperrors.CodeWeakPassword, appErr.BusinessCode)
+		})
+	}
+
+	t.Run("uppercase and symbol required", func(t *testing.T) {
+		strict := policy
+		strict.RequireUppercase = true
+		strict.RequireSymbol = true
+		assert.Error(t, checkPasswordStrength(strict, "blue7horse", "alice", ""))
+		assert.NoError(t, checkPasswordStrength(strict, "Blue7horse!", "alice", ""))
+	})
+}
+
+func TestPasswordPolicyServiceCheckReuse(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakePasswordHistoryRepo{}
+	service := NewPasswordPolicyService(NewSecurityPolicyService(nil, nil, defaultSecurityConfig()), repo)
+
+	hash := func(password string) string {
+		hashed, err := utils.HashPassword(password)
+		require.NoError(t, err)
+		return hashed
+	}
+	user := &model.User{ID: 1, Password: hash("current1x")}
+	require.NoError(t, service.Record(ctx, 1, hash("previous1x")))
+	require.NoError(t, service.Record(ctx, 2, hash("other1xyz")))
+
+	assert.Error(t, service.CheckReuse(ctx, user, "current1x"))
+	assert.Error(t, service.CheckReuse(ctx, user, "previous1x"))
+	assert.NoError(t, service.CheckReuse(ctx, user, "other1xyz"))
+	assert.NoError(t, service.CheckReuse(ctx, user, "brandnew1x"))
+}
+
+func TestPasswordPolicyServiceCheckReuseCountsCurrentPassword(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakePasswordHistoryRepo{}
+	service := NewPasswordPolicyService(NewSecurityPolicyService(nil, nil, defaultSecurityConfig()), repo)
+
+	hash := func(password string) string {
+		hashed, err := utils.HashPassword(password)
+		require.NoError(t, err)
+		return hashed
+	}
+	// history_count=5：当前密码加最近 4 条历史被禁止，第 5 条历史已可复用
+	for _, password := range []string{"oldest1x", "second1x", "third1xx", "fourth1x", "fifth1xx"} {
+		require.NoError(t, service.Record(ctx, 1, hash(password)))
+	}
+	user := &model.User{ID: 1, Password: hash("current1x")}
+
+	for _, password := range []string{"current1x", "fifth1xx", "fourth1x", "third1xx", "second1x"} {
+		assert.Error(t, service.CheckReuse(ctx, user, password), password)
+	}
+	assert.NoError(t, service.CheckReuse(ctx, user, "oldest1x"))
+}
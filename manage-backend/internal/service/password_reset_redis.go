@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
@@ -24,18 +25,6 @@ const (
 	RedisKeyLimitEmail = "password_reset:limit:email:%s"
 )
 
-// 限流配置
-const (
-	// IP级别：每小时最多5次请求
-	MaxRequestsPerIP = 5
-	
-	// 邮箱级别：每小时最多3次请求
-	MaxRequestsPerEmail = 3
-	
-	// 限流时间窗口
-	RateLimitWindow = 1 * time.Hour
-)
-
 // RedisResetToken Redis中存储的Token数据
 type RedisResetToken struct {
 	UserID    uint      `json:"user_id"`
@@ -44,48 +33,62 @@ type RedisResetToken struct {
 }
 
 // PasswordResetRedisService Redis密码重置服务
+// 限流次数和窗口从安全策略服务读取
 type PasswordResetRedisService struct {
-	redisClient *cache.RedisClient
+	redisClient    *cache.RedisClient
+	securityPolicy *SecurityPolicyService
 }
 
 // NewPasswordResetRedisService 创建Redis密码重置服务
-func NewPasswordResetRedisService(redisClient *cache.RedisClient) *PasswordResetRedisService {
+func NewPasswordResetRedisService(redisClient *cache.RedisClient, securityPolicy *SecurityPolicyService) *PasswordResetRedisService {
 	return &PasswordResetRedisService{
-		redisClient: redisClient,
+		redisClient:    redisClient,
+		securityPolicy: securityPolicy,
 	}
 }
 
+// policy 返回当前的密码重置限流策略
+func (s *PasswordResetRedisService) policy() config.PasswordResetSecurityConfig {
+	return s.securityPolicy.Get().PasswordReset
+}
+
+// RateLimitWindow 返回当前的限流时间窗口
+func (s *PasswordResetRedisService) RateLimitWindow() time.Duration {
+	return time.Duration(s.policy().WindowMinutes) * time.Minute
+}
+
 // CheckIPRateLimit 检查IP限流
 // 返回：是否允许请求，剩余次数，错误
 func (s *PasswordResetRedisService) CheckIPRateLimit(ctx context.Context, ip string) (bool, int, error) {
 	key := fmt.Sprintf(RedisKeyLimitIP, ip)
+	policy := s.policy()
 	
 	// 递增计数
 	count, err := s.redisClient.Incr(ctx, key)
 	if err != nil {
-		logger.Error("IP限流计数失败", zap.String("ip", ip), zap.Error(err))
+		logger.FromContext(ctx).Error("IP限流计数失败", zap.String("ip", ip), zap.Error(err))
 		return false, 0, err
 	}
 	
 	// 第一次请求，设置过期时间
 	if count == 1 {
-		if err := s.redisClient.Expire(ctx, key, RateLimitWindow); err != nil {
-			logger.Error("设置IP限流过期时间失败", zap.String("ip", ip), zap.Error(err))
+		if err := s.redisClient.Expire(ctx, key, time.Duration(policy.WindowMinutes)*time.Minute); err != nil {
+			logger.FromContext(ctx).Error("设置IP限流过期时间失败", zap.String("ip", ip), zap.Error(err))
 		}
 	}
 	
-	remaining := int(MaxRequestsPerIP - count)
+	remaining := policy.MaxRequestsPerIP - int(count)
 	if remaining < 0 {
 		remaining = 0
 	}
 	
-	allowed := count <= MaxRequestsPerIP
+	allowed := count <= int64(policy.MaxRequestsPerIP)
 	
 	if !allowed {
-		logger.Warn("IP请求频率超限",
+		logger.FromContext(ctx).Warn("IP请求频率超限",
 			zap.String("ip", ip),
 			zap.Int64("count", count),
-			zap.Int("max", MaxRequestsPerIP))
+			zap.Int("max", policy.MaxRequestsPerIP))
 	}
 	
 	return allowed, remaining, nil
@@ -95,33 +98,34 @@ func (s *PasswordResetRedisService) CheckIPRateLimit(ctx context.Context, ip str
 // 返回：是否允许请求，剩余次数，错误
 func (s *PasswordResetRedisService) CheckEmailRateLimit(ctx context.Context, email string) (bool, int, error) {
 	key := fmt.Sprintf(RedisKeyLimitEmail, email)
+	policy := s.policy()
 	
 	// 递增计数
 	count, err := s.redisClient.Incr(ctx, key)
 	if err != nil {
-		logger.Error("邮箱限流计数失败", zap.String("email", email), zap.Error(err))
+		logger.FromContext(ctx).Error("邮箱限流计数失败", zap.String("email", email), zap.Error(err))
 		return false, 0, err
 	}
 	
 	// 第一次请求，设置过期时间
 	if count == 1 {
-		if err := s.redisClient.Expire(ctx, key, RateLimitWindow); err != nil {
-			logger.Error("设置邮箱限流过期时间失败", zap.String("email", email), zap.Error(err))
+		if err := s.redisClient.Expire(ctx, key, time.Duration(policy.WindowMinutes)*time.Minute); err != nil {
+			logger.FromContext(ctx).Error("设置邮箱限流过期时间失败", zap.String("email", email), zap.Error(err))
 		}
 	}
 	
-	remaining := int(MaxRequestsPerEmail - count)
+	remaining := policy.MaxRequestsPerEmail - int(count)
 	if remaining < 0 {
 		remaining = 0
 	}
 	
-	allowed := count <= MaxRequestsPerEmail
+	allowed := count <= int64(policy.MaxRequestsPerEmail)
 	
 	if !allowed {
-		logger.Warn("邮箱请求频率超限",
+		logger.FromContext(ctx).Warn("邮箱请求频率超限",
 			zap.String("email", email),
 			zap.Int64("count", count),
-			zap.Int("max", MaxRequestsPerEmail))
+			zap.Int("max", policy.MaxRequestsPerEmail))
 	}
 	
 	return allowed, remaining, nil
@@ -140,20 +144,20 @@ func (s *PasswordResetRedisService) SaveToken(ctx context.Context, token string,
 	// 序列化为JSON
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		logger.Error("序列化Token数据失败", zap.Error(err))
+		logger.FromContext(ctx).Error("序列化Token数据失败", zap.Error(err))
 		return fmt.Errorf("序列化Token数据失败: %w", err)
 	}
 	
 	// 保存到Redis，设置过期时间
 	if err := s.redisClient.Set(ctx, key, jsonData, expiration); err != nil {
-		logger.Error("保存Token到Redis失败",
+		logger.FromContext(ctx).Error("保存Token到Redis失败",
 			zap.String("token", token),
 			zap.Uint("user_id", userID),
 			zap.Error(err))
 		return fmt.Errorf("保存Token到Redis失败: %w", err)
 	}
 	
-	logger.Debug("Token已保存到Redis",
+	logger.FromContext(ctx).Debug("Token已保存到Redis",
 		zap.Uint("user_id", userID),
 		zap.String("email", email),
 		zap.Duration("expiration", expiration))
@@ -169,21 +173,21 @@ func (s *PasswordResetRedisService) GetToken(ctx context.Context, token string)
 	jsonData, err := s.redisClient.Get(ctx, key)
 	if err != nil {
 		if err == redis.Nil {
-			logger.Debug("Token在Redis中不存在", zap.String("token", token))
+			logger.FromContext(ctx).Debug("Token在Redis中不存在", zap.String("token", token))
 			return nil, nil // Token不存在
 		}
-		logger.Error("从Redis获取Token失败", zap.String("token", token), zap.Error(err))
+		logger.FromContext(ctx).Error("从Redis获取Token失败", zap.String("token", token), zap.Error(err))
 		return nil, fmt.Errorf("从Redis获取Token失败: %w", err)
 	}
 	
 	// 反序列化
 	var data RedisResetToken
 	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
-		logger.Error("反序列化Token数据失败", zap.Error(err))
+		logger.FromContext(ctx).Error("反序列化Token数据失败", zap.Error(err))
 		return nil, fmt.Errorf("反序列化Token数据失败: %w", err)
 	}
 	
-	logger.Debug("从Redis获取Token成功",
+	logger.FromContext(ctx).Debug("从Redis获取Token成功",
 		zap.Uint("user_id", data.UserID),
 		zap.String("email", data.Email))
 	
@@ -195,11 +199,11 @@ func (s *PasswordResetRedisService) DeleteToken(ctx context.Context, token strin
 	key := fmt.Sprintf(RedisKeyResetToken, token)
 	
 	if err := s.redisClient.Del(ctx, key); err != nil {
-		logger.Error("删除Redis Token失败", zap.String("token", token), zap.Error(err))
+		logger.FromContext(ctx).Error("删除Redis Token失败", zap.String("token", token), zap.Error(err))
 		return fmt.Errorf("删除Redis Token失败: %w", err)
 	}
 	
-	logger.Debug("已删除Redis Token")
+	logger.FromContext(ctx).Debug("已删除Redis Token")
 	return nil
 }
 
@@ -209,7 +213,7 @@ func (s *PasswordResetRedisService) GetTokenTTL(ctx context.Context, token strin
 	
 	ttl, err := s.redisClient.TTL(ctx, key)
 	if err != nil {
-		logger.Error("获取Token TTL失败", zap.String("token", token), zap.Error(err))
+		logger.FromContext(ctx).Error("获取Token TTL失败", zap.String("token", token), zap.Error(err))
 		return 0, fmt.Errorf("获取Token TTL失败: %w", err)
 	}
 	
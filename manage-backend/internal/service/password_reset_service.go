@@ -13,6 +13,7 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
 	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -31,6 +32,7 @@ type passwordResetService struct {
 	emailService    EmailService
 	auditLogService *AuditLogService
 	redisService    *PasswordResetRedisService
+	passwordPolicy  *PasswordPolicyService
 }
 
 // NewPasswordResetService 创建密码重置服务实例
@@ -41,6 +43,7 @@ func NewPasswordResetService(
 	emailService EmailService,
 	auditLogService *AuditLogService,
 	redisService *PasswordResetRedisService,
+	passwordPolicy *PasswordPolicyService,
 ) PasswordResetService {
 	logger.Info("密码重置服务初始化成功（已启用Redis加速）")
 	return &passwordResetService{
@@ -50,12 +53,13 @@ func NewPasswordResetService(
 		emailService:    emailService,
 		auditLogService: auditLogService,
 		redisService:    redisService,
+		passwordPolicy:  passwordPolicy,
 	}
 }
 
 // RequestPasswordReset 请求密码重置
 func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email, ipAddress, userAgent string) error {
-	logger.Info("开始处理密码重置请求",
+	logger.FromContext(ctx).Info("开始处理密码重置请求",
 		zap.String("email", email),
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "request_password_reset"))
@@ -64,14 +68,14 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 	if s.redisService != nil {
 		allowed, remaining, err := s.redisService.CheckIPRateLimit(ctx, ipAddress)
 		if err != nil {
-			logger.Warn("IP限流检查失败，继续处理", zap.Error(err))
+			logger.FromContext(ctx).Warn("IP限流检查失败，继续处理", zap.Error(err))
 		} else if !allowed {
-			logger.Warn("IP请求频率超限，拒绝请求",
+			logger.FromContext(ctx).Warn("IP请求频率超限，拒绝请求",
 				zap.String("ip", ipAddress),
 				zap.Int("remaining", remaining))
-			return apperrors.NewRateLimitErrorWithCode(fmt.Sprintf("请求过于频繁，请1小时后再试（剩余次数：%d）", remaining))
+			return apperrors.NewRateLimitErrorWithCode(fmt.Sprintf("请求过于频繁，请%d分钟后再试（剩余次数：%d）", int(s.redisService.RateLimitWindow().Minutes()), remaining))
 		} else {
-			logger.Debug("IP限流检查通过",
+			logger.FromContext(ctx).Debug("IP限流检查通过",
 				zap.String("ip", ipAddress),
 				zap.Int("remaining", remaining))
 		}
@@ -81,14 +85,14 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 	if s.redisService != nil {
 		allowed, remaining, err := s.redisService.CheckEmailRateLimit(ctx, email)
 		if err != nil {
-			logger.Warn("邮箱限流检查失败，继续处理", zap.Error(err))
+			logger.FromContext(ctx).Warn("邮箱限流检查失败，继续处理", zap.Error(err))
 		} else if !allowed {
-			logger.Warn("邮箱请求频率超限，拒绝请求",
+			logger.FromContext(ctx).Warn("邮箱请求频率超限，拒绝请求",
 				zap.String("email", email),
 				zap.Int("remaining", remaining))
-			return apperrors.NewRateLimitErrorWithCode(fmt.Sprintf("该邮箱请求过于频繁，请1小时后再试（剩余次数：%d）", remaining))
+			return apperrors.NewRateLimitErrorWithCode(fmt.Sprintf("该邮箱请求过于频繁，请%d分钟后再试（剩余次数：%d）", int(s.redisService.RateLimitWindow().Minutes()), remaining))
 		} else {
-			logger.Debug("邮箱限流检查通过",
+			logger.FromContext(ctx).Debug("邮箱限流检查通过",
 				zap.String("email", email),
 				zap.Int("remaining", remaining))
 		}
@@ -99,13 +103,13 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// 安全实践：即使用户不存在也返回成功，防止邮箱枚举攻击
-			logger.Warn("密码重置请求：用户不存在（返回成功以防止枚举）",
+			logger.FromContext(ctx).Warn("密码重置请求：用户不存在（返回成功以防止枚举）",
 				zap.String("email", email),
 				zap.String("ip_address", ipAddress),
 				zap.String("operation", "request_password_reset"))
 			return nil
 		}
-		logger.Error("查询用户失败",
+		logger.FromContext(ctx).Error("查询用户失败",
 			zap.String("email", email),
 			zap.Error(err),
 			zap.String("operation", "request_password_reset"))
@@ -114,7 +118,7 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 
 	// 2. 检查用户状态
 	if user.Status != "active" {
-		logger.Warn("密码重置请求失败：账户已被禁用",
+		logger.FromContext(ctx).Warn("密码重置请求失败：账户已被禁用",
 			zap.Uint("user_id", user.ID),
 			zap.String("status", user.Status),
 			zap.String("operation", "request_password_reset"))
@@ -123,14 +127,14 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 
 	// 3. 删除该用户之前未使用的Token（防止重复请求）
 	if err := s.resetTokenRepo.DeleteByUserID(ctx, user.ID); err != nil {
-		logger.Error("清理旧Token失败",
+		logger.FromContext(ctx).Error("清理旧Token失败",
 			zap.Uint("user_id", user.ID),
 			zap.Error(err),
 			zap.String("operation", "request_password_reset"))
 		return apperrors.NewPasswordResetTokenCleanFailedError()
 	}
 
-	logger.Debug("已清理用户旧的重置Token",
+	logger.FromContext(ctx).Debug("已清理用户旧的重置Token",
 		zap.Uint("user_id", user.ID),
 		zap.String("email", email))
 
@@ -148,7 +152,7 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 	}
 
 	if err := s.resetTokenRepo.Create(ctx, resetToken); err != nil {
-		logger.Error("创建重置Token失败",
+		logger.FromContext(ctx).Error("创建重置Token失败",
 			zap.Uint("user_id", user.ID),
 			zap.String("email", email),
 			zap.Error(err),
@@ -156,7 +160,7 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 		return apperrors.NewPasswordResetTokenCreateFailedError()
 	}
 
-	logger.Debug("重置Token创建成功",
+	logger.FromContext(ctx).Debug("重置Token创建成功",
 		zap.Uint("user_id", user.ID),
 		zap.String("email", email),
 		zap.Time("expires_at", expiresAt))
@@ -165,24 +169,26 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 	if s.redisService != nil {
 		expiration := time.Duration(s.config.PasswordReset.TokenExpireMinutes) * time.Minute
 		if err := s.redisService.SaveToken(ctx, token, user.ID, email, expiration); err != nil {
-			logger.Warn("保存Token到Redis失败，但不影响主流程", zap.Error(err))
+			logger.FromContext(ctx).Warn("保存Token到Redis失败，但不影响主流程", zap.Error(err))
 		} else {
-			logger.Debug("Token已同步到Redis")
+			logger.FromContext(ctx).Debug("Token已同步到Redis")
 		}
 	}
 
 	// 5. 发送邮件
 	if err := s.emailService.SendPasswordResetEmail(email, token, user.Username); err != nil {
-		logger.Error("发送重置邮件失败",
+		metrics.PasswordResetEmailsTotal.WithLabelValues("failed").Inc()
+		logger.FromContext(ctx).Error("发送重置邮件失败",
 			zap.Uint("user_id", user.ID),
 			zap.String("email", email),
 			zap.Error(err),
 			zap.String("operation", "request_password_reset"))
 		return apperrors.NewPasswordResetEmailSendFailedError()
 	}
+	metrics.PasswordResetEmailsTotal.WithLabelValues("sent").Inc()
 
 	// 6. 记录审计日志
-	s.auditLogService.auditLogRepo.Create(&model.AuditLog{
+	s.auditLogService.Record(&model.AuditLog{
 		UserID:     user.ID,
 		Username:   user.Username,
 		Action:     "请求密码重置",
@@ -192,9 +198,10 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 		IP:         ipAddress,
 		UserAgent:  userAgent,
 		Status:     200,
+		RequestID:  logger.RequestIDFromContext(ctx),
 	})
 
-	logger.Info("密码重置请求处理成功",
+	logger.FromContext(ctx).Info("密码重置请求处理成功",
 		zap.String("email", email),
 		zap.Uint("user_id", user.ID),
 		zap.String("username", user.Username),
@@ -206,7 +213,7 @@ func (s *passwordResetService) RequestPasswordReset(ctx context.Context, email,
 
 // VerifyResetToken 验证重置Token（混合方案：优先Redis，降级PostgreSQL）
 func (s *passwordResetService) VerifyResetToken(ctx context.Context, token string) (*model.User, error) {
-	logger.Debug("开始验证重置Token",
+	logger.FromContext(ctx).Debug("开始验证重置Token",
 		zap.String("operation", "verify_reset_token"))
 
 	var userID uint
@@ -216,10 +223,10 @@ func (s *passwordResetService) VerifyResetToken(ctx context.Context, token strin
 	if s.redisService != nil {
 		redisToken, err := s.redisService.GetToken(ctx, token)
 		if err != nil {
-			logger.Warn("从Redis获取Token失败，降级到数据库", zap.Error(err))
+			logger.FromContext(ctx).Warn("从Redis获取Token失败，降级到数据库", zap.Error(err))
 		} else if redisToken != nil {
 			// Redis命中
-			logger.Debug("Token在Redis中命中（快速验证）",
+			logger.FromContext(ctx).Debug("Token在Redis中命中（快速验证）",
 				zap.Uint("user_id", redisToken.UserID),
 				zap.String("email", redisToken.Email))
 			userID = redisToken.UserID
@@ -229,15 +236,15 @@ func (s *passwordResetService) VerifyResetToken(ctx context.Context, token strin
 
 	// 2. Redis未命中，从PostgreSQL查找（降级路径）
 	if userID == 0 {
-		logger.Debug("Redis未命中，从数据库查询Token")
+		logger.FromContext(ctx).Debug("Redis未命中，从数据库查询Token")
 		resetToken, err := s.resetTokenRepo.FindByToken(ctx, token)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logger.Warn("验证失败：Token不存在",
+				logger.FromContext(ctx).Warn("验证失败：Token不存在",
 					zap.String("operation", "verify_reset_token"))
 				return nil, apperrors.NewInvalidResetTokenError()
 			}
-			logger.Error("查询Token失败",
+			logger.FromContext(ctx).Error("查询Token失败",
 				zap.Error(err),
 				zap.String("operation", "verify_reset_token"))
 			return nil, apperrors.NewPasswordResetTokenQueryFailedError()
@@ -246,14 +253,14 @@ func (s *passwordResetService) VerifyResetToken(ctx context.Context, token strin
 		// 验证Token有效性
 		if !resetToken.IsValid() {
 			if resetToken.IsExpired() {
-				logger.Warn("验证失败：Token已过期",
+				logger.FromContext(ctx).Warn("验证失败：Token已过期",
 					zap.Uint("token_id", resetToken.ID),
 					zap.Time("expires_at", resetToken.ExpiresAt),
 					zap.String("operation", "verify_reset_token"))
 				return nil, apperrors.NewResetTokenExpiredError()
 			}
 			if resetToken.IsUsed() {
-				logger.Warn("验证失败：Token已使用",
+				logger.FromContext(ctx).Warn("验证失败：Token已使用",
 					zap.Uint("token_id", resetToken.ID),
 					zap.Time("used_at", *resetToken.UsedAt),
 					zap.String("operation", "verify_reset_token"))
@@ -269,29 +276,29 @@ func (s *passwordResetService) VerifyResetToken(ctx context.Context, token strin
 			ttl := time.Until(resetToken.ExpiresAt)
 			if ttl > 0 {
 				if err := s.redisService.SaveToken(ctx, token, userID, email, ttl); err != nil {
-					logger.Warn("回写Token到Redis失败", zap.Error(err))
+					logger.FromContext(ctx).Warn("回写Token到Redis失败", zap.Error(err))
 				} else {
-					logger.Debug("Token已回写到Redis")
+					logger.FromContext(ctx).Debug("Token已回写到Redis")
 				}
 			}
 		}
 
-		logger.Debug("Token验证通过（数据库）",
+		logger.FromContext(ctx).Debug("Token验证通过（数据库）",
 			zap.Uint("token_id", resetToken.ID),
 			zap.Uint("user_id", userID))
 	}
 
 	// 3. 查找用户
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		logger.Error("查询用户失败",
+		logger.FromContext(ctx).Error("查询用户失败",
 			zap.Uint("user_id", userID),
 			zap.Error(err),
 			zap.String("operation", "verify_reset_token"))
 		return nil, apperrors.NewPasswordResetUserQueryFailedError()
 	}
 
-	logger.Debug("Token验证成功",
+	logger.FromContext(ctx).Debug("Token验证成功",
 		zap.Uint("user_id", user.ID),
 		zap.String("username", user.Username),
 		zap.String("email", user.Email),
@@ -302,36 +309,44 @@ func (s *passwordResetService) VerifyResetToken(ctx context.Context, token strin
 
 // ResetPassword 重置密码
 func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPassword, ipAddress, userAgent string) error {
-	logger.Info("开始重置密码",
+	logger.FromContext(ctx).Info("开始重置密码",
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "reset_password"))
 
 	// 1. 验证Token并获取用户
 	user, err := s.VerifyResetToken(ctx, token)
 	if err != nil {
-		logger.Warn("密码重置失败：Token验证失败",
+		logger.FromContext(ctx).Warn("密码重置失败：Token验证失败",
 			zap.Error(err),
 			zap.String("ip_address", ipAddress),
 			zap.String("operation", "reset_password"))
 		return err
 	}
 
-	// 2. 加密新密码
+	// 2. 校验密码策略（强度和历史密码）
+	if err := s.passwordPolicy.ValidateStrength(newPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.CheckReuse(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// 2.5. 加密新密码
 	hashedPassword, err := utils.HashPassword(newPassword)
 	if err != nil {
-		logger.Error("密码加密失败",
+		logger.FromContext(ctx).Error("密码加密失败",
 			zap.Uint("user_id", user.ID),
 			zap.Error(err),
 			zap.String("operation", "reset_password"))
 		return apperrors.NewPasswordResetHashFailedError()
 	}
 
-	logger.Debug("新密码加密成功",
+	logger.FromContext(ctx).Debug("新密码加密成功",
 		zap.Uint("user_id", user.ID))
 
 	// 3. 更新密码
 	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
-		logger.Error("更新密码失败",
+		logger.FromContext(ctx).Error("更新密码失败",
 			zap.Uint("user_id", user.ID),
 			zap.String("username", user.Username),
 			zap.Error(err),
@@ -339,16 +354,23 @@ func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPass
 		return apperrors.NewPasswordResetUpdateFailedError()
 	}
 
-	logger.Debug("密码更新成功",
+	logger.FromContext(ctx).Debug("密码更新成功",
 		zap.Uint("user_id", user.ID),
 		zap.String("username", user.Username))
 
+	if err := s.passwordPolicy.Record(ctx, user.ID, user.Password); err != nil {
+		logger.FromContext(ctx).Error("记录密码历史失败",
+			zap.Uint("user_id", user.ID),
+			zap.Error(err),
+			zap.String("operation", "reset_password"))
+	}
+
 	// 3.5. 删除Redis中的Token（立即失效）
 	if s.redisService != nil {
 		if err := s.redisService.DeleteToken(ctx, token); err != nil {
-			logger.Warn("删除Redis Token失败", zap.Error(err))
+			logger.FromContext(ctx).Warn("删除Redis Token失败", zap.Error(err))
 		} else {
-			logger.Debug("已删除Redis中的Token")
+			logger.FromContext(ctx).Debug("已删除Redis中的Token")
 		}
 	}
 
@@ -356,19 +378,19 @@ func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPass
 	resetToken, _ := s.resetTokenRepo.FindByToken(ctx, token)
 	if resetToken != nil {
 		if err := s.resetTokenRepo.MarkAsUsed(ctx, resetToken.ID); err != nil {
-			logger.Error("标记Token为已使用失败",
+			logger.FromContext(ctx).Error("标记Token为已使用失败",
 				zap.Uint("token_id", resetToken.ID),
 				zap.Error(err),
 				zap.String("operation", "reset_password"))
 			// 不返回错误，因为密码已经更新成功
 		} else {
-			logger.Debug("Token已标记为已使用",
+			logger.FromContext(ctx).Debug("Token已标记为已使用",
 				zap.Uint("token_id", resetToken.ID))
 		}
 	}
 
 	// 5. 记录审计日志
-	s.auditLogService.auditLogRepo.Create(&model.AuditLog{
+	s.auditLogService.Record(&model.AuditLog{
 		UserID:     user.ID,
 		Username:   user.Username,
 		Action:     "密码重置成功",
@@ -378,9 +400,10 @@ func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPass
 		IP:         ipAddress,
 		UserAgent:  userAgent,
 		Status:     200,
+		RequestID:  logger.RequestIDFromContext(ctx),
 	})
 
-	logger.Info("密码重置成功",
+	logger.FromContext(ctx).Info("密码重置成功",
 		zap.Uint("user_id", user.ID),
 		zap.String("username", user.Username),
 		zap.String("email", user.Email),
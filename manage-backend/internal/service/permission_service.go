@@ -13,12 +13,14 @@ import (
 // PermissionService 权限业务服务
 type PermissionService struct {
 	permissionRepo PermissionRepositoryInterface
+	casbinService  CasbinServiceInterface
 }
 
 // NewPermissionService 创建 PermissionService 实例
-func NewPermissionService(permissionRepo PermissionRepositoryInterface) *PermissionService {
+func NewPermissionService(permissionRepo PermissionRepositoryInterface, casbinService CasbinServiceInterface) *PermissionService {
 	return &PermissionService{
 		permissionRepo: permissionRepo,
+		casbinService:  casbinService,
 	}
 }
 
@@ -145,9 +147,8 @@ func (s *PermissionService) Delete(id uint) error {
 }
 
 // List 分页获取权限列表
-func (s *PermissionService) List(page, pageSize int) ([]model.PermissionResponse, int64, error) {
-	offset := (page - 1) * pageSize
-	permissions, total, err := s.permissionRepo.List(offset, pageSize)
+func (s *PermissionService) List(req *model.PermissionListRequest) ([]model.PermissionResponse, int64, error) {
+	permissions, total, err := s.permissionRepo.List(req)
 	if err != nil {
 		logger.Error("获取权限列表失败", zap.Error(err))
 		return nil, 0, apperrors.NewPermissionListFailedError()
@@ -261,6 +262,95 @@ func (s *PermissionService) GetUserPermissions(userID uint, roleRepo RoleReposit
 	return codes, nil
 }
 
+// ListDeleted 分页获取回收站中的权限
+func (s *PermissionService) ListDeleted(query *model.ListQuery) ([]model.PermissionResponse, int64, error) {
+	permissions, total, err := s.permissionRepo.ListDeleted(query)
+	if err != nil {
+		logger.Error("获取已删除权限列表失败", zap.Error(err))
+		return nil, 0, apperrors.NewRecycleBinListFailedError()
+	}
+
+	responses := make([]model.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		responses[i] = *s.toPermissionResponse(&permission)
+	}
+
+	return responses, total, nil
+}
+
+// Restore 从回收站恢复权限
+func (s *PermissionService) Restore(id uint) error {
+	permission, err := s.getDeletedPermission(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.permissionRepo.Restore(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewRecycleBinNotFoundError("权限不存在或未被删除")
+		}
+		logger.Error("恢复权限失败", zap.Uint("permission_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinRestoreFailedError()
+	}
+
+	logger.Info("恢复权限成功",
+		zap.Uint("permission_id", id),
+		zap.String("code", permission.Code))
+
+	return nil
+}
+
+// Purge 彻底删除回收站中的权限，同时删除角色-权限关联和各角色对应的 Casbin 策略
+// 彻底删除后权限代码可以被重新使用
+func (s *PermissionService) Purge(id uint) error {
+	permission, err := s.getDeletedPermission(id)
+	if err != nil {
+		return err
+	}
+
+	// 只有 API 类型的权限存在 Casbin 策略
+	if permission.Type == "api" && permission.Path != "" && permission.Method != "" {
+		roleCodes, err := s.permissionRepo.GetRoleCodesByPermissionID(id)
+		if err != nil {
+			logger.Error("获取权限关联角色失败", zap.Uint("permission_id", id), zap.Error(err))
+			return apperrors.NewRolePermissionGetFailedError()
+		}
+		for _, code := range roleCodes {
+			if err := s.casbinService.RemovePermissionForRole(code, permission.Path, permission.Method); err != nil {
+				logger.Error("删除角色 Casbin 权限失败",
+					zap.String("role_code", code),
+					zap.Uint("permission_id", id),
+					zap.Error(err))
+				return apperrors.NewRolePermissionDeleteFailedError()
+			}
+		}
+	}
+
+	if err := s.permissionRepo.Purge(id); err != nil {
+		logger.Error("彻底删除权限失败", zap.Uint("permission_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinPurgeFailedError()
+	}
+
+	logger.Info("彻底删除权限成功",
+		zap.Uint("permission_id", id),
+		zap.String("code", permission.Code))
+
+	return nil
+}
+
+// getDeletedPermission 获取回收站中的权限
+func (s *PermissionService) getDeletedPermission(id uint) (*model.Permission, error) {
+	permission, err := s.permissionRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewRecycleBinNotFoundError("权限不存在或未被删除")
+		}
+		logger.Error("获取已删除权限失败", zap.Uint("permission_id", id), zap.Error(err))
+		return nil, apperrors.NewPermissionGetFailedError()
+	}
+	return permission, nil
+}
+
 // toPermissionResponse 转换为响应结构
 func (s *PermissionService) toPermissionResponse(permission *model.Permission) *model.PermissionResponse {
 	return &model.PermissionResponse{
@@ -276,5 +366,6 @@ func (s *PermissionService) toPermissionResponse(permission *model.Permission) *
 		Status:      permission.Status,
 		CreatedAt:   permission.CreatedAt,
 		UpdatedAt:   permission.UpdatedAt,
+		DeletedAt:   model.DeletedTime(permission.DeletedAt),
 	}
 }
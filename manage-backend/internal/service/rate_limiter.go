@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 限流计数 - ratelimit:{group}:{subject}，有序集合，成员为每次请求，分数为请求时间（毫秒）
+const RedisKeyRateLimit = "ratelimit:%s:%s"
+
+const rateLimitKeyPrefix = "ratelimit:"
+
+// slidingWindowScript 滑动窗口限流脚本，清理过期记录、判断并记录本次请求在一次调用中原子完成
+// 使用 Redis 服务器时间，多实例部署时不受各节点时钟偏差影响
+//
+// KEYS[1] 限流键
+// ARGV[1] 窗口长度（毫秒）
+// ARGV[2] 窗口内允许的最大请求数
+// ARGV[3] 本次请求的唯一标识
+// 返回 {是否放行, 窗口内请求数, 距离最早一次请求过期的毫秒数}
+var slidingWindowScript = redis.NewScript(`
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+// RateLimiter 基于 Redis 的通用接口限流器（滑动窗口）
+type RateLimiter struct {
+	rdb     *redis.Client
+	rules   map[string]config.RateLimitRule
+	apiKeys map[string]struct{} // 已登记 API Key 的 SHA-256 摘要
+}
+
+// NewRateLimiter 创建接口限流器，无效的规则会被忽略
+func NewRateLimiter(rdb *redis.Client, cfg config.RateLimitConfig) *RateLimiter {
+	rules := make(map[string]config.RateLimitRule)
+	if cfg.Enabled {
+		for group, rule := range cfg.Rules {
+			if rule.Limit < 1 || rule.WindowSeconds < 1 || strings.Contains(group, ":") {
+				logger.Warn("忽略无效的限流规则",
+					zap.String("group", group),
+					zap.Int("limit", rule.Limit),
+					zap.Int("window_seconds", rule.WindowSeconds))
+				continue
+			}
+			if rule.KeyBy == "" {
+				rule.KeyBy = model.RateLimitKeyByIP
+			}
+			rules[group] = rule
+		}
+	}
+
+	apiKeys := make(map[string]struct{}, len(cfg.APIKeys))
+	for _, digest := range cfg.APIKeys {
+		digest = strings.ToLower(strings.TrimSpace(digest))
+		if len(digest) != sha256.Size*2 {
+			logger.Warn("忽略无效的 API Key 摘要", zap.Int("length", len(digest)))
+			continue
+		}
+		apiKeys[digest] = struct{}{}
+	}
+
+	return &RateLimiter{
+		rdb:     rdb,
+		rules:   rules,
+		apiKeys: apiKeys,
+	}
+}
+
+// Rule 获取路由分组的限流规则，未启用限流或分组未配置规则时返回 false
+func (l *RateLimiter) Rule(group string) (config.RateLimitRule, bool) {
+	rule, ok := l.rules[group]
+	return rule, ok
+}
+
+// APIKeyID 校验 API Key 是否已登记，返回用作限流对象的摘要前缀
+// 未登记的 Key 返回 false，避免随意构造的 Key 各自拥有独立的限流计数
+func (l *RateLimiter) APIKeyID(apiKey string) (string, bool) {
+	sum := sha256.Sum256([]byte(apiKey))
+	digest := hex.EncodeToString(sum[:])
+	if _, ok := l.apiKeys[digest]; !ok {
+		return "", false
+	}
+	// 只保存摘要前缀，避免 API Key 明文出现在 Redis 和管理接口中
+	return digest[:16], true
+}
+
+// Allow 判断并记录一次请求
+func (l *RateLimiter) Allow(ctx context.Context, group, subject string) (*model.RateLimitResult, error) {
+	rule, ok := l.rules[group]
+	if !ok {
+		return &model.RateLimitResult{Allowed: true}, nil
+	}
+
+	key := fmt.Sprintf(RedisKeyRateLimit, group, subject)
+	window := time.Duration(rule.WindowSeconds) * time.Second
+	values, err := slidingWindowScript.Run(ctx, l.rdb, []string{key},
+		window.Milliseconds(), rule.Limit, uuid.NewString()).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("执行限流脚本失败: %w", err)
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("限流脚本返回值异常: %v", values)
+	}
+
+	remaining := rule.Limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &model.RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     rule.Limit,
+		Remaining: remaining,
+		ResetIn:   time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// List 查询当前的限流计数，按分组、对象排序
+func (l *RateLimiter) List(ctx context.Context, query *model.RateLimitQuery) ([]model.RateLimitEntry, error) {
+	keys, err := l.scanKeys(ctx, query.Group, query.Subject)
+	if err != nil {
+		logger.FromContext(ctx).Error("扫描限流计数失败", zap.String("group", query.Group), zap.Error(err))
+		return nil, apperrors.NewRateLimitQueryFailedError()
+	}
+
+	now := time.Now()
+	entries := make([]model.RateLimitEntry, 0, len(keys))
+	for _, key := range keys {
+		group, subject, ok := parseRateLimitKey(key)
+		if !ok || !strings.HasPrefix(subject, query.Subject) {
+			continue
+		}
+		rule, ok := l.rules[group]
+		if !ok {
+			// 规则已从配置中移除，计数会在过期后自动清理
+			continue
+		}
+
+		windowStart := now.Add(-time.Duration(rule.WindowSeconds) * time.Second).UnixMilli()
+		pipe := l.rdb.Pipeline()
+		countCmd := pipe.ZCount(ctx, key, fmt.Sprintf("(%d", windowStart), "+inf")
+		ttlCmd := pipe.PTTL(ctx, key)
+		if _, err := pipe.Exec(ctx); err != nil {
+			logger.FromContext(ctx).Error("查询限流计数失败", zap.String("key", key), zap.Error(err))
+			return nil, apperrors.NewRateLimitQueryFailedError()
+		}
+
+		count := int(countCmd.Val())
+		if count == 0 {
+			continue
+		}
+		remaining := rule.Limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		entries = append(entries, model.RateLimitEntry{
+			Group:     group,
+			Subject:   subject,
+			Count:     count,
+			Limit:     rule.Limit,
+			Remaining: remaining,
+			ResetIn:   int64((ttlCmd.Val() + time.Second - 1) / time.Second),
+			Limited:   remaining == 0,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Group != entries[j].Group {
+			return entries[i].Group < entries[j].Group
+		}
+		return entries[i].Subject < entries[j].Subject
+	})
+
+	return entries, nil
+}
+
+// Clear 清除限流计数，subject 为空时清除整个分组
+func (l *RateLimiter) Clear(ctx context.Context, group, subject string) (int64, error) {
+	var keys []string
+	if subject != "" {
+		keys = []string{fmt.Sprintf(RedisKeyRateLimit, group, subject)}
+	} else {
+		var err error
+		keys, err = l.scanKeys(ctx, group, "")
+		if err != nil {
+			logger.FromContext(ctx).Error("扫描限流计数失败", zap.String("group", group), zap.Error(err))
+			return 0, apperrors.NewRateLimitClearFailedError()
+		}
+		if len(keys) == 0 {
+			return 0, nil
+		}
+	}
+
+	cleared, err := l.rdb.Del(ctx, keys...).Result()
+	if err != nil {
+		logger.FromContext(ctx).Error("清除限流计数失败",
+			zap.String("group", group),
+			zap.String("subject", subject),
+			zap.Error(err))
+		return 0, apperrors.NewRateLimitClearFailedError()
+	}
+
+	logger.FromContext(ctx).Info("已清除限流计数",
+		zap.String("group", group),
+		zap.String("subject", subject),
+		zap.Int64("cleared", cleared))
+
+	return cleared, nil
+}
+
+// scanKeys 使用 SCAN 遍历限流键（避免 KEYS 阻塞 Redis），subject 按前缀匹配
+func (l *RateLimiter) scanKeys(ctx context.Context, group, subject string) ([]string, error) {
+	pattern := rateLimitKeyPrefix + "*"
+	if group != "" {
+		pattern = rateLimitKeyPrefix + escapeGlob(group) + ":" + escapeGlob(subject) + "*"
+	} else if subject != "" {
+		pattern = rateLimitKeyPrefix + "*:" + escapeGlob(subject) + "*"
+	}
+
+	return scanRedisKeys(ctx, l.rdb, pattern)
+}
+
+// scanRedisKeys 使用 SCAN 遍历匹配的键
+func scanRedisKeys(ctx context.Context, rdb *redis.Client, pattern string) ([]string, error) {
+	var keys []string
+	iter := rdb.Scan(ctx, 0, pattern, 200).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// parseRateLimitKey 从限流键中解析路由分组和限流对象
+func parseRateLimitKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, rateLimitKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+// escapeGlob 转义 SCAN MATCH 模式中的特殊字符
+func escapeGlob(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
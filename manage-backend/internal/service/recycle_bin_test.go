@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeDeletedUserRepo 内存中的回收站用户仓库
+type fakeDeletedUserRepo struct {
+	UserRepositoryInterface
+	deleted map[uint]*model.User
+	purged  []uint
+}
+
+func (r *fakeDeletedUserRepo) GetDeletedByID(id uint) (*model.User, error) {
+	user, ok := r.deleted[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (r *fakeDeletedUserRepo) Purge(id uint) error {
+	delete(r.deleted, id)
+	r.purged = append(r.purged, id)
+	return nil
+}
+
+// fakeCasbinService 记录被移除 Casbin 角色的用户
+type fakeCasbinService struct {
+	CasbinServiceInterface
+	removedUsers []uint
+}
+
+func (s *fakeCasbinService) RemoveAllRolesForUser(userID uint) error {
+	s.removedUsers = append(s.removedUsers, userID)
+	return nil
+}
+
+// fakeMenuRepo 内存中的菜单仓库，menus 为未删除的菜单，deleted 为回收站中的菜单
+type fakeMenuRepo struct {
+	MenuRepositoryInterface
+	menus    map[uint]*model.Menu
+	deleted  map[uint]*model.Menu
+	children map[uint]bool
+}
+
+func (r *fakeMenuRepo) GetByID(id uint) (*model.Menu, error) {
+	menu, ok := r.menus[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return menu, nil
+}
+
+func (r *fakeMenuRepo) GetDeletedByID(id uint) (*model.Menu, error) {
+	menu, ok := r.deleted[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return menu, nil
+}
+
+func (r *fakeMenuRepo) Restore(id uint) error {
+	r.menus[id] = r.deleted[id]
+	delete(r.deleted, id)
+	return nil
+}
+
+func (r *fakeMenuRepo) HasAnyChildren(id uint) (bool, error) {
+	return r.children[id], nil
+}
+
+func (r *fakeMenuRepo) Purge(id uint) error {
+	delete(r.deleted, id)
+	return nil
+}
+
+func requireBusinessCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var appErr *apperrors.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, code, appErr.BusinessCode)
+}
+
+func TestUserServicePurge(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	ctx := context.Background()
+
+	userRepo := &fakeDeletedUserRepo{deleted: map[uint]*model.User{
+		2: {ID: 2, Username: "alice", AvatarKey: "avatars/2/a.png"},
+		3: {ID: 3, Username: "bob"},
+	}}
+	casbin := &fakeCasbinService{}
+	sessions := &fakeSessionService{}
+	files := &fakeStorage{files: map[string][]byte{"avatars/2/a.png": []byte("avatar")}}
+	service := &UserService{
+		userRepo:       userRepo,
+		casbinService:  casbin,
+		sessionService: sessions,
+		fileStorage:    files,
+	}
+
+	require.NoError(t, service.Purge(ctx, 2))
+	assert.Equal(t, []uint{2}, userRepo.purged)
+	assert.Equal(t, []uint{2}, casbin.removedUsers)
+	assert.Equal(t, []uint{2}, sessions.revoked)
+	assert.Equal(t, []string{"avatars/2/a.png"}, files.deleted)
+	assert.Empty(t, files.files)
+
+	// 没有头像的用户不访问存储
+	require.NoError(t, service.Purge(ctx, 3))
+	assert.Equal(t, []string{"avatars/2/a.png"}, files.deleted)
+
+	// 未删除或不存在的用户不能彻底删除
+	requireBusinessCode(t, service.Purge(ctx, 2), apperrors.CodeRecycleBinNotFound)
+	assert.Equal(t, []uint{2, 3}, userRepo.purged)
+}
+
+func TestMenuServiceRecycleBin(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	parentID := uint(1)
+
+	newService := func() (*MenuService, *fakeMenuRepo) {
+		repo := &fakeMenuRepo{
+			menus: map[uint]*model.Menu{},
+			deleted: map[uint]*model.Menu{
+				1: {ID: 1, Name: "system"},
+				2: {ID: 2, Name: "users", ParentID: &parentID},
+			},
+			children: map[uint]bool{1: true},
+		}
+		return &MenuService{menuRepo: repo}, repo
+	}
+
+	t.Run("restore requires parent to be restored first", func(t *testing.T) {
+		service, repo := newService()
+
+		requireBusinessCode(t, service.Restore(2), apperrors.CodeMenuParentNotFound)
+		assert.Contains(t, repo.deleted, uint(2))
+
+		require.NoError(t, service.Restore(1))
+		require.NoError(t, service.Restore(2))
+		assert.Empty(t, repo.deleted)
+
+		requireBusinessCode(t, service.Restore(2), apperrors.CodeRecycleBinNotFound)
+	})
+
+	t.Run("purge refused while children exist", func(t *testing.T) {
+		service, repo := newService()
+
+		requireBusinessCode(t, service.Purge(1), apperrors.CodeMenuHasChildren)
+		require.NoError(t, service.Purge(2))
+		assert.NotContains(t, repo.deleted, uint(2))
+	})
+}
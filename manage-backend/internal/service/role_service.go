@@ -1,34 +1,42 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 
 		apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // RoleRepositoryInterface 定义角色仓库接口
 type RoleRepositoryInterface interface {
-	Create(role *model.Role) error
-	GetByID(id uint) (*model.Role, error)
+	Create(ctx context.Context, role *model.Role) error
+	GetByID(ctx context.Context, id uint) (*model.Role, error)
 	GetByCode(code string) (*model.Role, error)
-	Update(role *model.Role) error
-	Delete(id uint) error
-	List(offset, limit int) ([]model.Role, int64, error)
+	Update(ctx context.Context, role *model.Role) error
+	Delete(ctx context.Context, id uint) error
+	List(req *model.RoleListRequest) ([]model.Role, int64, error)
 	GetAll() ([]model.Role, error)
-	CheckCodeExists(code string) (bool, error)
+	CheckCodeExists(ctx context.Context, code string) (bool, error)
 	CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error)
 	GetUserRoles(userID uint) ([]model.Role, error)
-	AssignRoleToUser(userID, roleID uint, assignedBy uint) error
+	GetRoleCodesByUserIDs(userIDs []uint) (map[uint][]string, error)
+	AssignRoleToUser(ctx context.Context, userID, roleID uint, assignedBy uint) error
 	RemoveRoleFromUser(userID, roleID uint) error
-	RemoveAllRolesFromUser(userID uint) error
-	GetUsersByRole(roleID uint) ([]uint, error)
-	UpdateRolePermissions(roleID uint, permissionIDs []uint) error
-	GetRolePermissionIDs(roleID uint) ([]uint, error)
+	RemoveAllRolesFromUser(ctx context.Context, userID uint) error
+	GetUsersByRole(ctx context.Context, roleID uint) ([]uint, error)
+	UpdateRolePermissions(ctx context.Context, roleID uint, permissionIDs []uint) error
+	GetRolePermissionIDs(ctx context.Context, roleID uint) ([]uint, error)
+	ListDeleted(query *model.ListQuery) ([]model.Role, int64, error)
+	GetDeletedByID(id uint) (*model.Role, error)
+	Restore(ctx context.Context, id uint) error
+	Purge(ctx context.Context, id uint) error
 }
 
 // PermissionRepositoryInterface 定义权限仓库接口
@@ -38,14 +46,19 @@ type PermissionRepositoryInterface interface {
 	GetByCode(code string) (*model.Permission, error)
 	Update(permission *model.Permission) error
 	Delete(id uint) error
-	List(offset, limit int) ([]model.Permission, int64, error)
+	List(req *model.PermissionListRequest) ([]model.Permission, int64, error)
 	GetAll() ([]model.Permission, error)
 	GetByResource(resource string) ([]model.Permission, error)
 	GetByType(permType string) ([]model.Permission, error)
 	CheckCodeExists(code string) (bool, error)
 	CheckCodeExistsExcludeID(code string, excludeID uint) (bool, error)
-	GetByIDs(ids []uint) ([]model.Permission, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]model.Permission, error)
 	GetByCodes(codes []string) ([]model.Permission, error)
+	ListDeleted(query *model.ListQuery) ([]model.Permission, int64, error)
+	GetDeletedByID(id uint) (*model.Permission, error)
+	Restore(id uint) error
+	GetRoleCodesByPermissionID(permissionID uint) ([]string, error)
+	Purge(id uint) error
 }
 
 // CasbinServiceInterface 定义 Casbin 服务接口
@@ -82,11 +95,14 @@ func NewRoleService(
 }
 
 // Create 创建角色
-func (s *RoleService) Create(req *model.CreateRoleRequest) (*model.RoleResponse, error) {
+func (s *RoleService) Create(ctx context.Context, req *model.CreateRoleRequest) (*model.RoleResponse, error) {
+	ctx, span := tracing.Start(ctx, "RoleService.Create", attribute.String("role.code", req.Code))
+	defer span.End()
+
 	// 检查角色代码是否已存在
-	exists, err := s.roleRepo.CheckCodeExists(req.Code)
+	exists, err := s.roleRepo.CheckCodeExists(ctx, req.Code)
 	if err != nil {
-		logger.Error("检查角色代码失败", zap.String("code", req.Code), zap.Error(err))
+		logger.FromContext(ctx).Error("检查角色代码失败", zap.String("code", req.Code), zap.Error(err))
 		return nil, apperrors.NewRoleCheckFailedError()
 	}
 	if exists {
@@ -105,12 +121,12 @@ func (s *RoleService) Create(req *model.CreateRoleRequest) (*model.RoleResponse,
 		role.Status = "active"
 	}
 
-	if err := s.roleRepo.Create(role); err != nil {
-		logger.Error("创建角色失败", zap.String("code", req.Code), zap.Error(err))
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		logger.FromContext(ctx).Error("创建角色失败", zap.String("code", req.Code), zap.Error(err))
 		return nil, apperrors.NewRoleCreateFailedError()
 	}
 
-	logger.Info("创建角色成功",
+	logger.FromContext(ctx).Info("创建角色成功",
 		zap.Uint("role_id", role.ID),
 		zap.String("code", role.Code),
 		zap.String("name", role.Name))
@@ -120,7 +136,7 @@ func (s *RoleService) Create(req *model.CreateRoleRequest) (*model.RoleResponse,
 
 // GetByID 根据ID获取角色
 func (s *RoleService) GetByID(id uint) (*model.RoleResponse, error) {
-	role, err := s.roleRepo.GetByID(id)
+	role, err := s.roleRepo.GetByID(context.Background(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewRoleNotFoundError()
@@ -133,14 +149,17 @@ func (s *RoleService) GetByID(id uint) (*model.RoleResponse, error) {
 }
 
 // Update 更新角色
-func (s *RoleService) Update(id uint, req *model.UpdateRoleRequest) (*model.RoleResponse, error) {
+func (s *RoleService) Update(ctx context.Context, id uint, req *model.UpdateRoleRequest) (*model.RoleResponse, error) {
+	ctx, span := tracing.Start(ctx, "RoleService.Update", attribute.Int64("role.id", int64(id)))
+	defer span.End()
+
 	// 获取角色
-	role, err := s.roleRepo.GetByID(id)
+	role, err := s.roleRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewRoleNotFoundError()
 		}
-		logger.Error("获取角色失败", zap.Uint("role_id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("获取角色失败", zap.Uint("role_id", id), zap.Error(err))
 		return nil, apperrors.NewRoleGetFailedError()
 	}
 
@@ -160,12 +179,12 @@ func (s *RoleService) Update(id uint, req *model.UpdateRoleRequest) (*model.Role
 		role.Status = req.Status
 	}
 
-	if err := s.roleRepo.Update(role); err != nil {
-		logger.Error("更新角色失败", zap.Uint("role_id", id), zap.Error(err))
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		logger.FromContext(ctx).Error("更新角色失败", zap.Uint("role_id", id), zap.Error(err))
 		return nil, apperrors.NewRoleUpdateFailedError()
 	}
 
-	logger.Info("更新角色成功",
+	logger.FromContext(ctx).Info("更新角色成功",
 		zap.Uint("role_id", role.ID),
 		zap.String("code", role.Code))
 
@@ -173,14 +192,17 @@ func (s *RoleService) Update(id uint, req *model.UpdateRoleRequest) (*model.Role
 }
 
 // Delete 删除角色
-func (s *RoleService) Delete(id uint) error {
+func (s *RoleService) Delete(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "RoleService.Delete", attribute.Int64("role.id", int64(id)))
+	defer span.End()
+
 	// 获取角色
-	role, err := s.roleRepo.GetByID(id)
+	role, err := s.roleRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return apperrors.NewRoleNotFoundError()
 		}
-		logger.Error("获取角色失败", zap.Uint("role_id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("获取角色失败", zap.Uint("role_id", id), zap.Error(err))
 		return apperrors.NewRoleGetFailedError()
 	}
 
@@ -190,9 +212,9 @@ func (s *RoleService) Delete(id uint) error {
 	}
 
 	// 检查是否有用户使用该角色
-	userIDs, err := s.roleRepo.GetUsersByRole(id)
+	userIDs, err := s.roleRepo.GetUsersByRole(ctx, id)
 	if err != nil {
-		logger.Error("检查角色使用情况失败", zap.Uint("role_id", id), zap.Error(err))
+		logger.FromContext(ctx).Error("检查角色使用情况失败", zap.Uint("role_id", id), zap.Error(err))
 		return apperrors.NewRoleCheckUsageFailedError()
 	}
 	if len(userIDs) > 0 {
@@ -201,17 +223,17 @@ func (s *RoleService) Delete(id uint) error {
 
 	// 删除 Casbin 中的角色权限
 	if err := s.casbinService.RemoveAllPermissionsForRole(role.Code); err != nil {
-		logger.Error("删除角色权限失败", zap.String("code", role.Code), zap.Error(err))
+		logger.FromContext(ctx).Error("删除角色权限失败", zap.String("code", role.Code), zap.Error(err))
 		return apperrors.NewRolePermissionDeleteFailedError()
 	}
 
 	// 删除角色
-	if err := s.roleRepo.Delete(id); err != nil {
-		logger.Error("删除角色失败", zap.Uint("role_id", id), zap.Error(err))
+	if err := s.roleRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).Error("删除角色失败", zap.Uint("role_id", id), zap.Error(err))
 		return apperrors.NewRoleDeleteFailedError()
 	}
 
-	logger.Info("删除角色成功",
+	logger.FromContext(ctx).Info("删除角色成功",
 		zap.Uint("role_id", id),
 		zap.String("code", role.Code))
 
@@ -219,9 +241,8 @@ func (s *RoleService) Delete(id uint) error {
 }
 
 // List 分页获取角色列表
-func (s *RoleService) List(page, pageSize int) ([]model.RoleResponse, int64, error) {
-	offset := (page - 1) * pageSize
-	roles, total, err := s.roleRepo.List(offset, pageSize)
+func (s *RoleService) List(req *model.RoleListRequest) ([]model.RoleResponse, int64, error) {
+	roles, total, err := s.roleRepo.List(req)
 	if err != nil {
 		logger.Error("获取角色列表失败", zap.Error(err))
 		return nil, 0, apperrors.NewRoleListFailedError()
@@ -252,30 +273,35 @@ func (s *RoleService) GetAll() ([]model.RoleResponse, error) {
 }
 
 // AssignPermissions 为角色分配权限
-func (s *RoleService) AssignPermissions(roleID uint, req *model.AssignRolePermissionsRequest) error {
+func (s *RoleService) AssignPermissions(ctx context.Context, roleID uint, req *model.AssignRolePermissionsRequest) error {
+	ctx, span := tracing.Start(ctx, "RoleService.AssignPermissions",
+		attribute.Int64("role.id", int64(roleID)),
+		attribute.Int("permission.count", len(req.PermissionIDs)))
+	defer span.End()
+
 	// 获取角色
-	role, err := s.roleRepo.GetByID(roleID)
+	role, err := s.roleRepo.GetByID(ctx, roleID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return apperrors.NewRoleNotFoundError()
 		}
-		logger.Error("获取角色失败", zap.Uint("role_id", roleID), zap.Error(err))
+		logger.FromContext(ctx).Error("获取角色失败", zap.Uint("role_id", roleID), zap.Error(err))
 		return apperrors.NewRoleGetFailedError()
 	}
 
 	// 获取权限列表
-	permissions, err := s.permissionRepo.GetByIDs(req.PermissionIDs)
+	permissions, err := s.permissionRepo.GetByIDs(ctx, req.PermissionIDs)
 	if err != nil {
-		logger.Error("获取权限列表失败", zap.Error(err))
+		logger.FromContext(ctx).Error("获取权限列表失败", zap.Error(err))
 		return apperrors.NewPermissionListFailedError()
 	}
 
-	logger.Info("获取到的权限数量",
+	logger.FromContext(ctx).Info("获取到的权限数量",
 		zap.Int("requested", len(req.PermissionIDs)),
 		zap.Int("found", len(permissions)))
 
 	if len(permissions) != len(req.PermissionIDs) {
-		logger.Warn("部分权限不存在",
+		logger.FromContext(ctx).Warn("部分权限不存在",
 			zap.Uints("requested_ids", req.PermissionIDs),
 			zap.Int("found_count", len(permissions)))
 		return apperrors.NewPermissionNotFoundError("部分权限不存在")
@@ -288,17 +314,17 @@ func (s *RoleService) AssignPermissions(roleID uint, req *model.AssignRolePermis
 		// 只处理 API 类型的权限
 		if perm.Type == "api" && perm.Path != "" && perm.Method != "" {
 			casbinPolicies = append(casbinPolicies, []string{perm.Path, perm.Method})
-			logger.Debug("添加API权限策略",
+			logger.FromContext(ctx).Debug("添加API权限策略",
 				zap.String("code", perm.Code),
 				zap.String("path", perm.Path),
 				zap.String("method", perm.Method))
 		} else if perm.Type == "menu" {
 			// 菜单类型权限不需要添加到 Casbin，只记录日志
-			logger.Debug("跳过菜单类型权限",
+			logger.FromContext(ctx).Debug("跳过菜单类型权限",
 				zap.String("code", perm.Code),
 				zap.String("type", perm.Type))
 		} else {
-			logger.Warn("跳过无效权限",
+			logger.FromContext(ctx).Warn("跳过无效权限",
 				zap.String("code", perm.Code),
 				zap.String("type", perm.Type),
 				zap.String("path", perm.Path),
@@ -308,7 +334,7 @@ func (s *RoleService) AssignPermissions(roleID uint, req *model.AssignRolePermis
 
 	// 更新 Casbin 策略（只更新 API 类型的权限）
 	if err := s.casbinService.UpdateRolePermissions(role.Code, casbinPolicies); err != nil {
-		logger.Error("更新 Casbin 角色权限失败",
+		logger.FromContext(ctx).Error("更新 Casbin 角色权限失败",
 			zap.Uint("role_id", roleID),
 			zap.String("code", role.Code),
 			zap.Error(err))
@@ -316,14 +342,14 @@ func (s *RoleService) AssignPermissions(roleID uint, req *model.AssignRolePermis
 	}
 
 	// 更新数据库中的角色-权限关联（包括所有类型的权限）
-	if err := s.roleRepo.UpdateRolePermissions(roleID, req.PermissionIDs); err != nil {
-		logger.Error("更新数据库角色权限失败",
+	if err := s.roleRepo.UpdateRolePermissions(ctx, roleID, req.PermissionIDs); err != nil {
+		logger.FromContext(ctx).Error("更新数据库角色权限失败",
 			zap.Uint("role_id", roleID),
 			zap.Error(err))
 		return apperrors.NewRolePermissionUpdateFailedError()
 	}
 
-	logger.Info("分配角色权限成功",
+	logger.FromContext(ctx).Info("分配角色权限成功",
 		zap.Uint("role_id", roleID),
 		zap.String("code", role.Code),
 		zap.Int("permission_count", len(permissions)),
@@ -335,7 +361,7 @@ func (s *RoleService) AssignPermissions(roleID uint, req *model.AssignRolePermis
 // GetRolePermissions 获取角色的权限列表
 func (s *RoleService) GetRolePermissions(roleID uint) (*model.RoleWithPermissions, error) {
 	// 获取角色
-	role, err := s.roleRepo.GetByID(roleID)
+	role, err := s.roleRepo.GetByID(context.Background(), roleID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, apperrors.NewRoleNotFoundError()
@@ -377,7 +403,7 @@ func (s *RoleService) GetRolePermissions(roleID uint) (*model.RoleWithPermission
 	}
 
 	// 从数据库获取角色的权限ID列表
-	permissionIDs, err := s.roleRepo.GetRolePermissionIDs(roleID)
+	permissionIDs, err := s.roleRepo.GetRolePermissionIDs(context.Background(), roleID)
 	if err != nil {
 		logger.Error("获取角色权限ID失败", zap.Uint("role_id", roleID), zap.Error(err))
 		return nil, apperrors.NewRolePermissionGetFailedError()
@@ -416,10 +442,15 @@ func (s *RoleService) GetRolePermissions(roleID uint) (*model.RoleWithPermission
 }
 
 // AssignRolesToUser 为用户分配角色
-func (s *RoleService) AssignRolesToUser(userID uint, req *model.AssignUserRolesRequest, assignedBy uint) error {
+func (s *RoleService) AssignRolesToUser(ctx context.Context, userID uint, req *model.AssignUserRolesRequest, assignedBy uint) error {
+	ctx, span := tracing.Start(ctx, "RoleService.AssignRolesToUser",
+		attribute.Int64("user.id", int64(userID)),
+		attribute.Int("role.count", len(req.RoleIDs)))
+	defer span.End()
+
 	// 验证角色是否存在
 	for _, roleID := range req.RoleIDs {
-		_, err := s.roleRepo.GetByID(roleID)
+		_, err := s.roleRepo.GetByID(ctx, roleID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return apperrors.NewRoleNotFoundError()
@@ -429,24 +460,24 @@ func (s *RoleService) AssignRolesToUser(userID uint, req *model.AssignUserRolesR
 	}
 
 	// 移除用户的所有现有角色
-	if err := s.roleRepo.RemoveAllRolesFromUser(userID); err != nil {
-		logger.Error("移除用户角色失败", zap.Uint("user_id", userID), zap.Error(err))
+	if err := s.roleRepo.RemoveAllRolesFromUser(ctx, userID); err != nil {
+		logger.FromContext(ctx).Error("移除用户角色失败", zap.Uint("user_id", userID), zap.Error(err))
 		return apperrors.NewUserRoleRemoveFailedError()
 	}
 
 	// 移除 Casbin 中的用户角色关系
 	if err := s.casbinService.RemoveAllRolesForUser(userID); err != nil {
-		logger.Error("移除用户Casbin角色失败", zap.Uint("user_id", userID), zap.Error(err))
+		logger.FromContext(ctx).Error("移除用户Casbin角色失败", zap.Uint("user_id", userID), zap.Error(err))
 		return apperrors.NewUserCasbinRoleRemoveFailedError()
 	}
 
 	// 分配新角色
 	for _, roleID := range req.RoleIDs {
-		role, _ := s.roleRepo.GetByID(roleID)
+		role, _ := s.roleRepo.GetByID(ctx, roleID)
 
 		// 添加到数据库
-		if err := s.roleRepo.AssignRoleToUser(userID, roleID, assignedBy); err != nil {
-			logger.Error("分配用户角色失败",
+		if err := s.roleRepo.AssignRoleToUser(ctx, userID, roleID, assignedBy); err != nil {
+			logger.FromContext(ctx).Error("分配用户角色失败",
 				zap.Uint("user_id", userID),
 				zap.Uint("role_id", roleID),
 				zap.Error(err))
@@ -455,7 +486,7 @@ func (s *RoleService) AssignRolesToUser(userID uint, req *model.AssignUserRolesR
 
 		// 添加到 Casbin
 		if err := s.casbinService.AddRoleForUser(userID, role.Code); err != nil {
-			logger.Error("添加用户Casbin角色失败",
+			logger.FromContext(ctx).Error("添加用户Casbin角色失败",
 				zap.Uint("user_id", userID),
 				zap.String("role_code", role.Code),
 				zap.Error(err))
@@ -463,7 +494,7 @@ func (s *RoleService) AssignRolesToUser(userID uint, req *model.AssignUserRolesR
 		}
 	}
 
-	logger.Info("分配用户角色成功",
+	logger.FromContext(ctx).Info("分配用户角色成功",
 		zap.Uint("user_id", userID),
 		zap.Int("role_count", len(req.RoleIDs)))
 
@@ -486,6 +517,118 @@ func (s *RoleService) GetUserRoles(userID uint) ([]model.RoleResponse, error) {
 	return responses, nil
 }
 
+// ListDeleted 分页获取回收站中的角色
+func (s *RoleService) ListDeleted(query *model.ListQuery) ([]model.RoleResponse, int64, error) {
+	roles, total, err := s.roleRepo.ListDeleted(query)
+	if err != nil {
+		logger.Error("获取已删除角色列表失败", zap.Error(err))
+		return nil, 0, apperrors.NewRecycleBinListFailedError()
+	}
+
+	responses := make([]model.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = *s.toRoleResponse(&role)
+	}
+
+	return responses, total, nil
+}
+
+// Restore 从回收站恢复角色，并按数据库中保留的角色-权限关联重新同步 Casbin 策略
+func (s *RoleService) Restore(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "RoleService.Restore", attribute.Int64("role.id", int64(id)))
+	defer span.End()
+
+	role, err := s.getDeletedRole(id)
+	if err != nil {
+		return err
+	}
+
+	// 删除角色时只移除了 Casbin 策略，角色-权限关联仍保留在数据库中
+	permissionIDs, err := s.roleRepo.GetRolePermissionIDs(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).Error("获取角色权限ID失败", zap.Uint("role_id", id), zap.Error(err))
+		return apperrors.NewRolePermissionGetFailedError()
+	}
+	var policies [][]string
+	if len(permissionIDs) > 0 {
+		permissions, err := s.permissionRepo.GetByIDs(ctx, permissionIDs)
+		if err != nil {
+			logger.FromContext(ctx).Error("获取权限列表失败", zap.Uint("role_id", id), zap.Error(err))
+			return apperrors.NewPermissionListFailedError()
+		}
+		for _, perm := range permissions {
+			// 只有 API 类型的权限需要同步到 Casbin
+			if perm.Type == "api" && perm.Path != "" && perm.Method != "" {
+				policies = append(policies, []string{perm.Path, perm.Method})
+			}
+		}
+	}
+
+	if err := s.roleRepo.Restore(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewRecycleBinNotFoundError("角色不存在或未被删除")
+		}
+		logger.FromContext(ctx).Error("恢复角色失败", zap.Uint("role_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinRestoreFailedError()
+	}
+
+	if err := s.casbinService.UpdateRolePermissions(role.Code, policies); err != nil {
+		logger.FromContext(ctx).Error("恢复角色 Casbin 权限失败",
+			zap.Uint("role_id", id),
+			zap.String("code", role.Code),
+			zap.Error(err))
+		return apperrors.NewCasbinUpdateFailedError()
+	}
+
+	logger.FromContext(ctx).Info("恢复角色成功",
+		zap.Uint("role_id", id),
+		zap.String("code", role.Code),
+		zap.Int("casbin_policy_count", len(policies)))
+
+	return nil
+}
+
+// Purge 彻底删除回收站中的角色，同时删除角色-权限关联、用户-角色关联和 Casbin 策略
+// 彻底删除后角色代码可以被重新使用
+func (s *RoleService) Purge(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "RoleService.Purge", attribute.Int64("role.id", int64(id)))
+	defer span.End()
+
+	role, err := s.getDeletedRole(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.casbinService.RemoveAllPermissionsForRole(role.Code); err != nil {
+		logger.FromContext(ctx).Error("删除角色权限失败", zap.String("code", role.Code), zap.Error(err))
+		return apperrors.NewRolePermissionDeleteFailedError()
+	}
+
+	if err := s.roleRepo.Purge(ctx, id); err != nil {
+		logger.FromContext(ctx).Error("彻底删除角色失败", zap.Uint("role_id", id), zap.Error(err))
+		return apperrors.NewRecycleBinPurgeFailedError()
+	}
+
+	logger.FromContext(ctx).Info("彻底删除角色成功",
+		zap.Uint("role_id", id),
+		zap.String("code", role.Code))
+
+	return nil
+}
+
+// getDeletedRole 获取回收站中的角色
+func (s *RoleService) getDeletedRole(id uint) (*model.Role, error) {
+	role, err := s.roleRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewRecycleBinNotFoundError("角色不存在或未被删除")
+		}
+		logger.Error("获取已删除角色失败", zap.Uint("role_id", id), zap.Error(err))
+		return nil, apperrors.NewRoleGetFailedError()
+	}
+	return role, nil
+}
+
 // toRoleResponse 转换为响应结构
 func (s *RoleService) toRoleResponse(role *model.Role) *model.RoleResponse {
 	return &model.RoleResponse{
@@ -497,5 +640,6 @@ func (s *RoleService) toRoleResponse(role *model.Role) *model.RoleResponse {
 		IsSystem:    role.IsSystem,
 		CreatedAt:   role.CreatedAt,
 		UpdatedAt:   role.UpdatedAt,
+		DeletedAt:   model.DeletedTime(role.DeletedAt),
 	}
 }
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SecurityPolicyChannel 安全策略变更通知频道
+// 消息只作为通知，各实例收到后从数据库重新加载，避免消息乱序导致策略不一致
+const SecurityPolicyChannel = "security:policy:changed"
+
+// SystemSettingRepositoryInterface 系统设置仓库接口
+type SystemSettingRepositoryInterface interface {
+	Get(ctx context.Context, key string) (*model.SystemSetting, error)
+	Save(ctx context.Context, setting *model.SystemSetting) error
+}
+
+// SecurityPolicyService 登录锁定与限流策略服务
+// 策略保存在内存中供每次请求读取，修改后持久化到数据库并通过 Redis 通知其他实例重新加载
+type SecurityPolicyService struct {
+	settingRepo SystemSettingRepositoryInterface
+	redisClient *cache.RedisClient
+	defaults    config.SecurityConfig
+	current     atomic.Pointer[model.SecurityPolicyResponse]
+	pubsub      *redis.PubSub
+	done        chan struct{}
+}
+
+// NewSecurityPolicyService 创建安全策略服务，初始使用配置文件中的策略
+// redisClient 为空时修改只在当前实例生效
+func NewSecurityPolicyService(settingRepo SystemSettingRepositoryInterface, redisClient *cache.RedisClient, defaults config.SecurityConfig) *SecurityPolicyService {
+	s := &SecurityPolicyService{
+		settingRepo: settingRepo,
+		redisClient: redisClient,
+		defaults:    defaults,
+	}
+	s.current.Store(&model.SecurityPolicyResponse{
+		Policy: defaults,
+		Source: model.SecurityPolicySourceConfig,
+	})
+	return s
+}
+
+// Get 返回当前生效的安全策略
+func (s *SecurityPolicyService) Get() config.SecurityConfig {
+	return s.current.Load().Policy
+}
+
+// GetPolicy 返回当前生效的安全策略及其来源
+func (s *SecurityPolicyService) GetPolicy() *model.SecurityPolicyResponse {
+	return s.current.Load()
+}
+
+// Reload 从数据库加载管理员修改过的策略，没有修改记录时使用配置文件中的策略
+// 持久化的策略无效时保留当前策略并返回错误
+func (s *SecurityPolicyService) Reload(ctx context.Context) error {
+	setting, err := s.settingRepo.Get(ctx, model.SettingKeySecurityPolicy)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.current.Store(&model.SecurityPolicyResponse{
+				Policy: s.defaults,
+				Source: model.SecurityPolicySourceConfig,
+			})
+			return nil
+		}
+		return fmt.Errorf("查询安全策略失败: %w", err)
+	}
+
+	// 以配置文件为基础解析，新增的策略项在管理员修改前沿用配置文件中的值
+	policy := s.defaults
+	if err := json.Unmarshal([]byte(setting.Value), &policy); err != nil {
+		return fmt.Errorf("解析安全策略失败: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("持久化的安全策略无效: %w", err)
+	}
+
+	updatedAt := setting.UpdatedAt
+	s.current.Store(&model.SecurityPolicyResponse{
+		Policy:    policy,
+		Source:    model.SecurityPolicySourceOverride,
+		UpdatedBy: setting.UpdatedBy,
+		UpdatedAt: &updatedAt,
+	})
+	return nil
+}
+
+// Update 校验并保存新的安全策略，立即在当前实例生效并通知其他实例
+func (s *SecurityPolicyService) Update(ctx context.Context, policy config.SecurityConfig, updatedBy uint) (*model.SecurityPolicyResponse, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	value, err := json.Marshal(policy)
+	if err != nil {
+		logger.FromContext(ctx).Error("序列化安全策略失败", zap.Error(err))
+		return nil, apperrors.NewSecurityPolicyUpdateFailedError()
+	}
+
+	setting := &model.SystemSetting{
+		Key:       model.SettingKeySecurityPolicy,
+		Value:     string(value),
+		UpdatedBy: updatedBy,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.settingRepo.Save(ctx, setting); err != nil {
+		logger.FromContext(ctx).Error("保存安全策略失败", zap.Uint("updated_by", updatedBy), zap.Error(err))
+		return nil, apperrors.NewSecurityPolicyUpdateFailedError()
+	}
+
+	response := &model.SecurityPolicyResponse{
+		Policy:    policy,
+		Source:    model.SecurityPolicySourceOverride,
+		UpdatedBy: updatedBy,
+		UpdatedAt: &setting.UpdatedAt,
+	}
+	s.current.Store(response)
+
+	logger.FromContext(ctx).Info("安全策略已更新",
+		zap.Uint("updated_by", updatedBy),
+		zap.String("policy", setting.Value))
+
+	if s.redisClient != nil {
+		if err := s.redisClient.GetClient().Publish(ctx, SecurityPolicyChannel, setting.Key).Err(); err != nil {
+			// 已持久化，其他实例会在重启或下一次变更时加载
+			logger.FromContext(ctx).Warn("通知其他实例安全策略变更失败", zap.Error(err))
+		}
+	}
+
+	return response, nil
+}
+
+// Start 订阅安全策略变更通知
+func (s *SecurityPolicyService) Start() {
+	if s.redisClient == nil {
+		return
+	}
+
+	s.pubsub = s.redisClient.GetClient().Subscribe(context.Background(), SecurityPolicyChannel)
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		for range s.pubsub.Channel() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := s.Reload(ctx)
+			cancel()
+			if err != nil {
+				logger.Error("重新加载安全策略失败，保留当前策略", zap.Error(err))
+				continue
+			}
+			logger.Info("已重新加载安全策略", zap.String("source", s.GetPolicy().Source))
+		}
+	}()
+}
+
+// Stop 取消订阅，需在关闭 Redis 连接之前调用
+func (s *SecurityPolicyService) Stop(ctx context.Context) error {
+	if s.pubsub == nil {
+		return nil
+	}
+	if err := s.pubsub.Close(); err != nil {
+		return err
+	}
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeSystemSettingRepo 内存中的系统设置仓库
+type fakeSystemSettingRepo struct {
+	settings map[string]model.SystemSetting
+}
+
+func (r *fakeSystemSettingRepo) Get(ctx context.Context, key string) (*model.SystemSetting, error) {
+	setting, ok := r.settings[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &setting, nil
+}
+
+func (r *fakeSystemSettingRepo) Save(ctx context.Context, setting *model.SystemSetting) error {
+	r.settings[setting.Key] = *setting
+	return nil
+}
+
+func defaultSecurityConfig() config.SecurityConfig {
+	return config.SecurityConfig{
+		Login: config.LoginSecurityConfig{
+			MaxAttemptsPerIP:       10,
+			IPWindowMinutes:        60,
+			MaxFailsPerAccount:     5,
+			FailWindowMinutes:      15,
+			LockoutMode:            config.LockoutModeFixed,
+			LockDurationMinutes:    15,
+			MaxLockDurationMinutes: 1440,
+			LockoutResetHours:      24,
+		},
+		PasswordReset: config.PasswordResetSecurityConfig{
+			MaxRequestsPerIP:    5,
+			MaxRequestsPerEmail: 3,
+			WindowMinutes:       60,
+		},
+		Password: config.PasswordSecurityConfig{
+			MinLength:        8,
+			RequireLowercase: true,
+			RequireDigit:     true,
+			BannedPasswords:  []string{"password1"},
+			DisallowUserInfo: true,
+			HistoryCount:     5,
+		},
+	}
+}
+
+func TestSecurityPolicyServiceReload(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	t.Run("no override uses config", func(t *testing.T) {
+		service := NewSecurityPolicyService(&fakeSystemSettingRepo{settings: map[string]model.SystemSetting{}}, nil, defaultSecurityConfig())
+
+		require.NoError(t, service.Reload(context.Background()))
+		assert.Equal(t, model.SecurityPolicySourceConfig, service.GetPolicy().Source)
+		assert.Equal(t, defaultSecurityConfig(), service.Get())
+	})
+
+	t.Run("override merged over config", func(t *testing.T) {
+		repo := &fakeSystemSettingRepo{settings: map[string]model.SystemSetting{
+			model.SettingKeySecurityPolicy: {
+				Key:       model.SettingKeySecurityPolicy,
+				Value:     `{"login":{"max_fails_per_account":3,"lockout_mode":"exponential"}}`,
+				UpdatedBy: 1,
+				UpdatedAt: time.Now(),
+			},
+		}}
+		service := NewSecurityPolicyService(repo, nil, defaultSecurityConfig())
+
+		require.NoError(t, service.Reload(context.Background()))
+		policy := service.GetPolicy()
+		assert.Equal(t, model.SecurityPolicySourceOverride, policy.Source)
+		assert.Equal(t, uint(1), policy.UpdatedBy)
+		assert.Equal(t, 3, policy.Policy.Login.MaxFailsPerAccount)
+		assert.Equal(t, config.LockoutModeExponential, policy.Policy.Login.LockoutMode)
+		assert.Equal(t, 10, policy.Policy.Login.MaxAttemptsPerIP)
+		assert.Equal(t, 60, policy.Policy.PasswordReset.WindowMinutes)
+	})
+
+	t.Run("invalid override keeps current policy", func(t *testing.T) {
+		repo := &fakeSystemSettingRepo{settings: map[string]model.SystemSetting{
+			model.SettingKeySecurityPolicy: {
+				Key:   model.SettingKeySecurityPolicy,
+				Value: `{"login":{"max_fails_per_account":0}}`,
+			},
+		}}
+		service := NewSecurityPolicyService(repo, nil, defaultSecurityConfig())
+
+		assert.Error(t, service.Reload(context.Background()))
+		assert.Equal(t, model.SecurityPolicySourceConfig, service.GetPolicy().Source)
+		assert.Equal(t, 5, service.Get().Login.MaxFailsPerAccount)
+	})
+}
+
+func TestSecurityPolicyServiceUpdate(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	t.Run("valid policy takes effect immediately", func(t *testing.T) {
+		repo := &fakeSystemSettingRepo{settings: map[string]model.SystemSetting{}}
+		service := NewSecurityPolicyService(repo, nil, defaultSecurityConfig())
+
+		policy := defaultSecurityConfig()
+		policy.Login.MaxFailsPerAccount = 8
+		response, err := service.Update(context.Background(), policy, 2)
+		require.NoError(t, err)
+		assert.Equal(t, model.SecurityPolicySourceOverride, response.Source)
+		assert.Equal(t, 8, service.Get().Login.MaxFailsPerAccount)
+		assert.Contains(t, repo.settings, model.SettingKeySecurityPolicy)
+
+		// 其他实例从数据库加载到相同的策略
+		other := NewSecurityPolicyService(repo, nil, defaultSecurityConfig())
+		require.NoError(t, other.Reload(context.Background()))
+		assert.Equal(t, policy, other.Get())
+	})
+
+	t.Run("invalid policy rejected", func(t *testing.T) {
+		repo := &fakeSystemSettingRepo{settings: map[string]model.SystemSetting{}}
+		service := NewSecurityPolicyService(repo, nil, defaultSecurityConfig())
+
+		policy := defaultSecurityConfig()
+		policy.Login.LockoutMode = "forever"
+		_, err := service.Update(context.Background(), policy, 2)
+		var appErr *apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.ErrorTypeValidation, appErr.Type)
+		assert.Empty(t, repo.settings)
+		assert.Equal(t, config.LockoutModeFixed, service.Get().Login.LockoutMode)
+	})
+}
+
+func TestLockoutDuration(t *testing.T) {
+	policy := defaultSecurityConfig().Login
+
+	assert.Equal(t, 15*time.Minute, lockoutDuration(policy, 3))
+
+	policy.LockoutMode = config.LockoutModeExponential
+	policy.MaxLockDurationMinutes = 100
+	assert.Equal(t, 15*time.Minute, lockoutDuration(policy, 1))
+	assert.Equal(t, 30*time.Minute, lockoutDuration(policy, 2))
+	assert.Equal(t, 60*time.Minute, lockoutDuration(policy, 3))
+	assert.Equal(t, 100*time.Minute, lockoutDuration(policy, 4))
+	assert.Equal(t, 100*time.Minute, lockoutDuration(policy, 64))
+}
@@ -3,33 +3,139 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
 )
 
 // SessionInfo 表示用户会话信息
+// - SessionID: 会话ID（每次登录生成，写入令牌的 sid 声明）
 // - UserID: 用户ID
 // - Username: 用户名
+// - Role: 用户角色
 // - RefreshToken: 刷新令牌（用于续期）
+// - AccessJTI: 当前访问令牌的 JTI（吊销会话时加入黑名单）
+// - AccessExpiresAt: 当前访问令牌的过期时间
 // - DeviceInfo: 设备信息
 // - IPAddress: 登录时的IP地址
 // - UserAgent: 浏览器/客户端标识
 // - LoginTime: 登录时间
 // - LastActivity: 最后活跃时间
 type SessionInfo struct {
-	UserID       uint      `json:"user_id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	DeviceInfo   string    `json:"device_info"`
-	IPAddress    string    `json:"ip_address"`
-	UserAgent    string    `json:"user_agent"`
-	LoginTime    time.Time `json:"login_time"`
-	LastActivity time.Time `json:"last_activity"`
+	SessionID       string    `json:"session_id"`
+	UserID          uint      `json:"user_id"`
+	Username        string    `json:"username"`
+	Role            string    `json:"role"`
+	RefreshToken    string    `json:"refresh_token"`
+	AccessJTI       string    `json:"access_jti"`
+	AccessExpiresAt time.Time `json:"access_expires_at"`
+	DeviceInfo      string    `json:"device_info"`
+	IPAddress       string    `json:"ip_address"`
+	UserAgent       string    `json:"user_agent"`
+	LoginTime       time.Time `json:"login_time"`
+	LastActivity    time.Time `json:"last_activity"`
 }
 
+// 会话相关 Redis Key 与有效期
+const (
+	// 单个会话 - user:session:{userID}:{sessionID}
+	RedisKeyUserSession = "user:session:%d:%s"
+
+	// 会话最后活跃时间 - user:session_activity:{userID}:{sessionID}，值为毫秒时间戳
+	// 与会话数据分开存放，每次请求只写该键，不会覆盖并发轮换的刷新令牌
+	RedisKeyUserSessionActivity = "user:session_activity:%d:%s"
+
+	// 用户会话索引 - user:sessions:{userID}
+	RedisKeyUserSessionIndex = "user:sessions:%d"
+
+	// 已轮换的刷新令牌 - token:rotated:{jti}，值为所属会话ID
+	RedisKeyRotatedRefreshToken = "token:rotated:%s"
+
+	// 必须修改密码标记 - user:password_change_required:{userID}，登录时按用户记录同步
+	RedisKeyPasswordChangeRequired = "user:password_change_required:%d"
+
+	// 会话有效期，与刷新令牌一致
+	SessionTTL = 30 * 24 * time.Hour
+)
+
+// RefreshTokenReuseError 表示检测到已轮换的刷新令牌被再次使用
+// 出现该错误时，令牌所属的整个会话（令牌族）已被吊销
+type RefreshTokenReuseError struct {
+	UserID    uint
+	Username  string
+	SessionID string
+}
+
+func (e *RefreshTokenReuseError) Error() string {
+	return fmt.Sprintf("检测到刷新令牌重复使用: user_id=%d, session_id=%s", e.UserID, e.SessionID)
+}
+
+// ErrRefreshTokenConflict 表示会话的刷新令牌在校验之后已被其他请求轮换
+var ErrRefreshTokenConflict = errors.New("刷新令牌已被并发轮换")
+
+// touchSessionScript 更新会话最后活跃时间并续期，会话不存在时不做任何写入，
+// 避免已吊销的会话被重新创建
+//
+// KEYS[1] 会话键
+// KEYS[2] 最后活跃时间键
+// ARGV[1] 最后活跃时间（毫秒时间戳）
+// ARGV[2] 会话有效期（毫秒）
+// 返回 1 表示已更新，0 表示会话不存在
+var touchSessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// rotateRefreshTokenScript 以比较并交换的方式轮换刷新令牌：
+// 只有会话中保存的仍是旧刷新令牌时才写入新会话，并同时记录旧令牌为已轮换、加入黑名单，
+// 被替换的访问令牌也加入黑名单，会话中始终只有一个可用的访问令牌
+//
+// KEYS[1] 会话键
+// KEYS[2] 最后活跃时间键
+// KEYS[3] 旧刷新令牌的已轮换标记键
+// KEYS[4] 旧刷新令牌的黑名单键
+// KEYS[5] 被替换的访问令牌的黑名单键
+// ARGV[1] 旧刷新令牌
+// ARGV[2] 新的会话数据
+// ARGV[3] 会话有效期（毫秒）
+// ARGV[4] 会话ID
+// ARGV[5] 旧刷新令牌剩余有效期（毫秒），为 0 时不记录
+// ARGV[6] 最后活跃时间（毫秒时间戳）
+// ARGV[7] 被替换的访问令牌剩余有效期（毫秒），为 0 时不记录
+// 返回 1 表示轮换成功，0 表示会话不存在，-1 表示刷新令牌已被轮换
+var rotateRefreshTokenScript = redis.NewScript(`
+local data = redis.call('GET', KEYS[1])
+if not data then
+	return 0
+end
+if cjson.decode(data)['refresh_token'] ~= ARGV[1] then
+	return -1
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+redis.call('SET', KEYS[2], ARGV[6], 'PX', ARGV[3])
+local ttl = tonumber(ARGV[5])
+if ttl > 0 then
+	redis.call('SET', KEYS[3], ARGV[4], 'PX', ttl)
+	redis.call('SET', KEYS[4], 'blacklisted', 'PX', ttl)
+end
+local accessTTL = tonumber(ARGV[7])
+if accessTTL > 0 then
+	redis.call('SET', KEYS[5], 'blacklisted', 'PX', accessTTL)
+end
+return 1
+`)
+
 // SessionService 会话服务
 // 负责管理用户会话、令牌黑名单、用户活跃状态以及权限缓存
 type SessionService struct {
@@ -45,32 +151,134 @@ func NewSessionService(redisClient *cache.RedisClient, jwtManager *auth.JWTManag
 	}
 }
 
+// NewSessionID 生成新的会话ID
+func NewSessionID() string {
+	return uuid.New().String()
+}
+
 // CreateSession 创建一个新的用户会话并存储到 Redis
+// 每次登录对应一个独立会话，并加入用户的会话索引
 // 会话有效期为 30 天（与刷新令牌一致）
-func (s *SessionService) CreateSession(ctx context.Context, userID uint, username, refreshToken, deviceInfo, ipAddress, userAgent string) error {
+func (s *SessionService) CreateSession(ctx context.Context, sessionID string, userID uint, username, role string, tokenPair *auth.TokenPair, deviceInfo, ipAddress, userAgent string) error {
+	now := time.Now()
 	sessionInfo := &SessionInfo{
-		UserID:       userID,
-		Username:     username,
-		RefreshToken: refreshToken,
-		DeviceInfo:   deviceInfo,
-		IPAddress:    ipAddress,
-		UserAgent:    userAgent,
-		LoginTime:    time.Now(),
-		LastActivity: time.Now(),
+		SessionID:       sessionID,
+		UserID:          userID,
+		Username:        username,
+		Role:            role,
+		RefreshToken:    tokenPair.RefreshToken,
+		AccessJTI:       tokenPair.AccessJTI,
+		AccessExpiresAt: now.Add(time.Duration(tokenPair.ExpiresIn) * time.Second),
+		DeviceInfo:      deviceInfo,
+		IPAddress:       ipAddress,
+		UserAgent:       userAgent,
+		LoginTime:       now,
+		LastActivity:    now,
+	}
+
+	if err := s.saveSession(ctx, sessionInfo); err != nil {
+		return err
+	}
+
+	indexKey := fmt.Sprintf(RedisKeyUserSessionIndex, userID)
+	if err := s.redisClient.SAdd(ctx, indexKey, sessionID); err != nil {
+		return fmt.Errorf("写入会话索引失败: %w", err)
+	}
+	return s.redisClient.Expire(ctx, indexKey, SessionTTL)
+}
+
+// RotateRefreshToken 轮换会话的刷新令牌
+// 在一个 Lua 脚本中校验会话当前的刷新令牌仍是 sessionInfo 中的旧令牌，
+// 再写入新令牌，并把旧令牌加入黑名单、记录为已轮换，以便旧令牌再次出现时识别为重复使用；
+// 被替换的访问令牌同时加入黑名单。
+// 会话已被吊销时返回 redis.Nil，旧令牌已被并发请求轮换时返回 ErrRefreshTokenConflict
+func (s *SessionService) RotateRefreshToken(ctx context.Context, sessionInfo *SessionInfo, tokenPair *auth.TokenPair) error {
+	oldRefreshToken := sessionInfo.RefreshToken
+	now := time.Now()
+
+	rotated := *sessionInfo
+	rotated.RefreshToken = tokenPair.RefreshToken
+	rotated.AccessJTI = tokenPair.AccessJTI
+	rotated.AccessExpiresAt = now.Add(time.Duration(tokenPair.ExpiresIn) * time.Second)
+	rotated.LastActivity = now
+	sessionData, err := json.Marshal(&rotated)
+	if err != nil {
+		return fmt.Errorf("序列化会话信息失败: %w", err)
+	}
+
+	// 旧令牌已无法解析（例如已过期）时无需记录为已轮换
+	oldJTI := ""
+	var oldTTL time.Duration
+	if claims, err := s.jwtManager.ValidateRefreshToken(oldRefreshToken); err == nil {
+		oldJTI = claims.JTI
+		oldTTL = max(s.jwtManager.GetTokenExpiration(claims), 0)
+	}
+
+	keys := []string{
+		fmt.Sprintf(RedisKeyUserSession, sessionInfo.UserID, sessionInfo.SessionID),
+		fmt.Sprintf(RedisKeyUserSessionActivity, sessionInfo.UserID, sessionInfo.SessionID),
+		fmt.Sprintf(RedisKeyRotatedRefreshToken, oldJTI),
+		fmt.Sprintf("token:blacklist:%s", oldJTI),
+		fmt.Sprintf("token:blacklist:%s", sessionInfo.AccessJTI),
+	}
+	// 会话中的访问令牌与旧刷新令牌属于同一次签发，脚本校验刷新令牌未变时它也未变
+	var oldAccessTTL time.Duration
+	if sessionInfo.AccessJTI != "" {
+		oldAccessTTL = max(sessionInfo.AccessExpiresAt.Sub(now), 0)
+	}
+	result, err := rotateRefreshTokenScript.Run(ctx, s.redisClient.GetClient(), keys,
+		oldRefreshToken, sessionData, SessionTTL.Milliseconds(), sessionInfo.SessionID, oldTTL.Milliseconds(), now.UnixMilli(),
+		oldAccessTTL.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("轮换刷新令牌失败: %w", err)
+	}
+
+	switch result {
+	case 0:
+		return fmt.Errorf("未找到会话: %w", redis.Nil)
+	case -1:
+		return ErrRefreshTokenConflict
 	}
 
+	*sessionInfo = rotated
+	return nil
+}
+
+// isRefreshTokenRotated 检查指定 JTI 的刷新令牌是否已被轮换
+func (s *SessionService) isRefreshTokenRotated(ctx context.Context, jti string) bool {
+	exists, err := s.redisClient.Exists(ctx, fmt.Sprintf(RedisKeyRotatedRefreshToken, jti))
+	if err != nil {
+		return false
+	}
+	return exists > 0
+}
+
+// revokeTokenFamily 吊销刷新令牌所属的整个会话，并返回重复使用错误
+func (s *SessionService) revokeTokenFamily(ctx context.Context, claims *auth.Claims) error {
+	if err := s.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("吊销令牌族失败: %w", err)
+	}
+	return &RefreshTokenReuseError{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		SessionID: claims.SessionID,
+	}
+}
+
+// saveSession 序列化并保存会话
+func (s *SessionService) saveSession(ctx context.Context, sessionInfo *SessionInfo) error {
 	sessionData, err := json.Marshal(sessionInfo)
 	if err != nil {
 		return fmt.Errorf("序列化会话信息失败: %w", err)
 	}
 
-	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Set(ctx, sessionKey, sessionData, 30*24*time.Hour)
+	sessionKey := fmt.Sprintf(RedisKeyUserSession, sessionInfo.UserID, sessionInfo.SessionID)
+	return s.redisClient.Set(ctx, sessionKey, sessionData, SessionTTL)
 }
 
-// GetSession 从 Redis 获取用户会话信息
-func (s *SessionService) GetSession(ctx context.Context, userID uint) (*SessionInfo, error) {
-	sessionKey := fmt.Sprintf("user:session:%d", userID)
+// GetSession 从 Redis 获取指定会话信息
+func (s *SessionService) GetSession(ctx context.Context, userID uint, sessionID string) (*SessionInfo, error) {
+	sessionKey := fmt.Sprintf(RedisKeyUserSession, userID, sessionID)
 	sessionData, err := s.redisClient.Get(ctx, sessionKey)
 	if err != nil {
 		return nil, fmt.Errorf("未找到会话: %w", err)
@@ -81,56 +289,155 @@ func (s *SessionService) GetSession(ctx context.Context, userID uint) (*SessionI
 		return nil, fmt.Errorf("反序列化会话信息失败: %w", err)
 	}
 
+	// 最后活跃时间单独存放，读取失败时沿用会话数据中的值
+	activityKey := fmt.Sprintf(RedisKeyUserSessionActivity, userID, sessionID)
+	if activity, err := s.redisClient.Get(ctx, activityKey); err == nil {
+		if millis, err := strconv.ParseInt(activity, 10, 64); err == nil {
+			sessionInfo.LastActivity = time.UnixMilli(millis)
+		}
+	}
+
 	return &sessionInfo, nil
 }
 
-// UpdateLastActivity 更新用户会话的最后活跃时间
-// 每次用户有请求时调用，用于刷新 TTL
-func (s *SessionService) UpdateLastActivity(ctx context.Context, userID uint) error {
-	sessionInfo, err := s.GetSession(ctx, userID)
+// ListSessions 获取用户的所有有效会话，按最后活跃时间倒序
+// 已过期的会话会从索引中清理
+func (s *SessionService) ListSessions(ctx context.Context, userID uint) ([]*SessionInfo, error) {
+	indexKey := fmt.Sprintf(RedisKeyUserSessionIndex, userID)
+	sessionIDs, err := s.redisClient.SMembers(ctx, indexKey)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("读取会话索引失败: %w", err)
 	}
 
-	sessionInfo.LastActivity = time.Now()
+	sessions := make([]*SessionInfo, 0, len(sessionIDs))
+	for _, sessionID := range sessionIDs {
+		sessionInfo, err := s.GetSession(ctx, userID, sessionID)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				s.redisClient.SRem(ctx, indexKey, sessionID)
+				continue
+			}
+			return nil, err
+		}
+		sessions = append(sessions, sessionInfo)
+	}
 
-	sessionData, err := json.Marshal(sessionInfo)
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActivity.After(sessions[j].LastActivity)
+	})
+
+	return sessions, nil
+}
+
+// UpdateLastActivity 更新会话的最后活跃时间
+// 每次用户有请求时调用，用于刷新 TTL；未绑定会话的旧令牌直接跳过。
+// 只写入单独的活跃时间键，不读写会话数据，因此不会覆盖并发轮换的刷新令牌，
+// 也不会重新创建已吊销的会话
+func (s *SessionService) UpdateLastActivity(ctx context.Context, userID uint, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+
+	keys := []string{
+		fmt.Sprintf(RedisKeyUserSession, userID, sessionID),
+		fmt.Sprintf(RedisKeyUserSessionActivity, userID, sessionID),
+	}
+	updated, err := touchSessionScript.Run(ctx, s.redisClient.GetClient(), keys,
+		time.Now().UnixMilli(), SessionTTL.Milliseconds()).Int()
 	if err != nil {
-		return fmt.Errorf("序列化会话信息失败: %w", err)
+		return fmt.Errorf("更新会话活跃时间失败: %w", err)
 	}
+	if updated == 0 {
+		return fmt.Errorf("未找到会话: %w", redis.Nil)
+	}
+	return nil
+}
 
-	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Set(ctx, sessionKey, sessionData, 30*24*time.Hour)
+// DeleteSession 删除 Redis 中的指定会话
+func (s *SessionService) DeleteSession(ctx context.Context, userID uint, sessionID string) error {
+	sessionKey := fmt.Sprintf(RedisKeyUserSession, userID, sessionID)
+	activityKey := fmt.Sprintf(RedisKeyUserSessionActivity, userID, sessionID)
+	if err := s.redisClient.Del(ctx, sessionKey, activityKey); err != nil {
+		return err
+	}
+	return s.redisClient.SRem(ctx, fmt.Sprintf(RedisKeyUserSessionIndex, userID), sessionID)
 }
 
-// DeleteSession 删除 Redis 中的用户会话
-func (s *SessionService) DeleteSession(ctx context.Context, userID uint) error {
-	sessionKey := fmt.Sprintf("user:session:%d", userID)
-	return s.redisClient.Del(ctx, sessionKey)
+// RevokeSession 吊销指定会话
+// 将会话当前的访问令牌和刷新令牌加入黑名单，并删除会话，使其立即失效
+func (s *SessionService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	sessionInfo, err := s.GetSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if ttl := time.Until(sessionInfo.AccessExpiresAt); sessionInfo.AccessJTI != "" && ttl > 0 {
+		if err := s.AddTokenToBlacklist(ctx, sessionInfo.AccessJTI, ttl); err != nil {
+			return fmt.Errorf("吊销访问令牌失败: %w", err)
+		}
+	}
+
+	if claims, err := s.jwtManager.ValidateRefreshToken(sessionInfo.RefreshToken); err == nil {
+		if ttl := s.jwtManager.GetTokenExpiration(claims); ttl > 0 {
+			if err := s.AddTokenToBlacklist(ctx, claims.JTI, ttl); err != nil {
+				return fmt.Errorf("吊销刷新令牌失败: %w", err)
+			}
+		}
+	}
+
+	return s.DeleteSession(ctx, userID, sessionID)
+}
+
+// RevokeAllSessions 吊销用户的所有会话，exceptSessionID 不为空时保留该会话
+// 返回被吊销的会话数量
+func (s *SessionService) RevokeAllSessions(ctx context.Context, userID uint, exceptSessionID string) (int, error) {
+	sessions, err := s.ListSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, sessionInfo := range sessions {
+		if sessionInfo.SessionID == exceptSessionID {
+			continue
+		}
+		if err := s.RevokeSession(ctx, userID, sessionInfo.SessionID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+
+	return revoked, nil
 }
 
 // ValidateRefreshToken 校验刷新令牌并验证 Redis 中的会话
 // 步骤：
 // 1. 校验刷新令牌的有效性（JWT 格式）
-// 2. 检查是否在黑名单
-// 3. 从 Redis 获取会话并校验是否匹配
+// 2. 检查是否为已轮换的旧令牌（重复使用则吊销整个令牌族）
+// 3. 检查是否在黑名单
+// 4. 从 Redis 获取令牌所属会话并校验是否匹配
 func (s *SessionService) ValidateRefreshToken(ctx context.Context, refreshToken string) (*SessionInfo, error) {
 	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("刷新令牌无效: %w", err)
 	}
 
+	if s.isRefreshTokenRotated(ctx, claims.JTI) {
+		return nil, s.revokeTokenFamily(ctx, claims)
+	}
+
 	if s.IsTokenBlacklisted(ctx, claims.JTI) {
 		return nil, fmt.Errorf("刷新令牌已被加入黑名单")
 	}
 
-	sessionInfo, err := s.GetSession(ctx, claims.UserID)
+	sessionInfo, err := s.GetSession(ctx, claims.UserID, claims.SessionID)
 	if err != nil {
 		return nil, fmt.Errorf("未找到会话: %w", err)
 	}
 
+	// 令牌属于该会话但已不是当前令牌，同样视为重复使用
 	if sessionInfo.RefreshToken != refreshToken {
-		return nil, fmt.Errorf("刷新令牌不匹配")
+		return nil, s.revokeTokenFamily(ctx, claims)
 	}
 
 	return sessionInfo, nil
@@ -152,6 +459,26 @@ func (s *SessionService) IsTokenBlacklisted(ctx context.Context, jti string) boo
 	return exists > 0
 }
 
+// SetPasswordChangeRequired 设置或清除用户必须修改密码的标记
+// 标记有效期与会话一致，每次登录时按用户记录重新同步
+func (s *SessionService) SetPasswordChangeRequired(ctx context.Context, userID uint, required bool) error {
+	key := fmt.Sprintf(RedisKeyPasswordChangeRequired, userID)
+	if !required {
+		return s.redisClient.Del(ctx, key)
+	}
+	return s.redisClient.Set(ctx, key, time.Now().Unix(), SessionTTL)
+}
+
+// IsPasswordChangeRequired 判断用户是否必须先修改密码
+// Redis 不可用时返回错误，由调用方拒绝请求，不能当作无需修改密码放行
+func (s *SessionService) IsPasswordChangeRequired(ctx context.Context, userID uint) (bool, error) {
+	exists, err := s.redisClient.Exists(ctx, fmt.Sprintf(RedisKeyPasswordChangeRequired, userID))
+	if err != nil {
+		return false, fmt.Errorf("查询必须修改密码标记失败: %w", err)
+	}
+	return exists > 0, nil
+}
+
 // SetUserActive 设置用户为活跃状态（TTL 30 分钟）
 // 一般在用户请求时调用，用于标记在线状态
 func (s *SessionService) SetUserActive(ctx context.Context, userID uint) error {
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/cache"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 两步验证相关 Redis Key
+const (
+	// 登录挑战 - 2fa:challenge:{token}
+	RedisKeyTwoFactorChallenge = "2fa:challenge:%s"
+
+	// 挑战验证失败次数 - 2fa:challenge:attempts:{token}
+	RedisKeyTwoFactorAttempts = "2fa:challenge:attempts:%s"
+
+	// 用户未完成的登录挑战 - 2fa:challenges:{userID}，账户锁定时据此作废全部挑战
+	RedisKeyTwoFactorUserChallenges = "2fa:challenges:%d"
+
+	// 已使用的 TOTP 时间步（防止验证码重放） - 2fa:used:{userID}:{step}
+	RedisKeyTwoFactorUsedStep = "2fa:used:%d:%d"
+)
+
+// 两步验证参数
+const (
+	// 单个登录挑战允许的最大验证失败次数
+	MaxTwoFactorAttempts = 5
+
+	// 每次生成的恢复码数量
+	RecoveryCodeCount = 10
+)
+
+// TwoFactorRepositoryInterface 定义两步验证仓库接口
+type TwoFactorRepositoryInterface interface {
+	GetByUserID(ctx context.Context, userID uint) (*model.UserTwoFactor, error)
+	Save(ctx context.Context, twoFactor *model.UserTwoFactor) error
+	UpdateRecoveryCodes(ctx context.Context, id uint, oldCodes, newCodes string) (bool, error)
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
+
+// TwoFactorChallenge 密码验证通过后暂存在 Redis 中的登录上下文
+type TwoFactorChallenge struct {
+	UserID     uint      `json:"user_id"`
+	Username   string    `json:"username"`
+	DeviceInfo string    `json:"device_info"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TwoFactorService 两步验证服务
+// 负责 TOTP 绑定、恢复码管理以及登录挑战
+type TwoFactorService struct {
+	twoFactorRepo TwoFactorRepositoryInterface
+	redisClient   *cache.RedisClient
+	issuer        string
+	challengeTTL  time.Duration
+}
+
+// NewTwoFactorService 创建两步验证服务实例
+func NewTwoFactorService(twoFactorRepo TwoFactorRepositoryInterface, redisClient *cache.RedisClient, cfg config.TwoFactorConfig) *TwoFactorService {
+	issuer := cfg.Issuer
+	if issuer == "" {
+		issuer = "Go Manage Starter"
+	}
+	challengeExpire := cfg.ChallengeExpireMinutes
+	if challengeExpire <= 0 {
+		challengeExpire = 5
+	}
+
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		redisClient:   redisClient,
+		issuer:        issuer,
+		challengeTTL:  time.Duration(challengeExpire) * time.Minute,
+	}
+}
+
+// GetStatus 获取用户的两步验证状态
+func (s *TwoFactorService) GetStatus(ctx context.Context, userID uint) (*model.TwoFactorStatusResponse, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.TwoFactorStatusResponse{Enabled: false}, nil
+		}
+		logger.FromContext(ctx).Error("查询两步验证信息失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "get_two_factor_status"))
+		return nil, apperrors.NewTwoFactorQueryFailedError()
+	}
+
+	if !twoFactor.Enabled {
+		return &model.TwoFactorStatusResponse{Enabled: false}, nil
+	}
+
+	return &model.TwoFactorStatusResponse{
+		Enabled:                true,
+		ConfirmedAt:            twoFactor.ConfirmedAt,
+		RecoveryCodesRemaining: len(decodeRecoveryCodes(twoFactor.RecoveryCodes)),
+	}, nil
+}
+
+// IsEnabled 检查用户是否已启用两步验证
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID uint) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return twoFactor.Enabled, nil
+}
+
+// Enroll 开始绑定两步验证
+// 生成新的密钥并返回绑定地址；在 Confirm 之前不会生效，重复调用会替换待确认的密钥
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uint, accountName string) (*model.TwoFactorEnrollResponse, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(ctx).Error("查询两步验证信息失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "enroll_two_factor"))
+		return nil, apperrors.NewTwoFactorQueryFailedError()
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return nil, apperrors.NewTwoFactorAlreadyEnabledError()
+	}
+	if twoFactor == nil {
+		twoFactor = &model.UserTwoFactor{UserID: userID}
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		logger.FromContext(ctx).Error("生成两步验证密钥失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "enroll_two_factor"))
+		return nil, apperrors.NewTwoFactorSetupFailedError()
+	}
+
+	twoFactor.Secret = secret
+	twoFactor.Enabled = false
+	twoFactor.RecoveryCodes = ""
+	twoFactor.ConfirmedAt = nil
+	if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		logger.FromContext(ctx).Error("保存两步验证密钥失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "enroll_two_factor"))
+		return nil, apperrors.NewTwoFactorSetupFailedError()
+	}
+
+	logger.FromContext(ctx).Info("两步验证密钥已生成，等待确认",
+		zap.Uint("user_id", userID),
+		zap.String("operation", "enroll_two_factor"))
+
+	return &model.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(s.issuer, accountName, secret),
+	}, nil
+}
+
+// Confirm 使用首个验证码确认绑定，启用两步验证并生成恢复码
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uint, code string) (*model.TwoFactorRecoveryCodesResponse, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewTwoFactorNotEnrolledError()
+		}
+		logger.FromContext(ctx).Error("查询两步验证信息失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "confirm_two_factor"))
+		return nil, apperrors.NewTwoFactorQueryFailedError()
+	}
+	if twoFactor.Enabled {
+		return nil, apperrors.NewTwoFactorAlreadyEnabledError()
+	}
+
+	if !s.verifyTOTP(ctx, userID, twoFactor.Secret, code) {
+		logger.FromContext(ctx).Warn("确认两步验证失败：验证码错误",
+			zap.Uint("user_id", userID),
+			zap.String("operation", "confirm_two_factor"))
+		return nil, apperrors.NewTwoFactorInvalidCodeError()
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.FromContext(ctx).Error("生成恢复码失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "confirm_two_factor"))
+		return nil, apperrors.NewTwoFactorSetupFailedError()
+	}
+
+	now := time.Now()
+	twoFactor.Enabled = true
+	twoFactor.ConfirmedAt = &now
+	twoFactor.RecoveryCodes = encodeRecoveryCodes(hashes)
+	if err := s.twoFactorRepo.Save(ctx, twoFactor); err != nil {
+		logger.FromContext(ctx).Error("启用两步验证失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "confirm_two_factor"))
+		return nil, apperrors.NewTwoFactorSetupFailedError()
+	}
+
+	logger.FromContext(ctx).Info("两步验证已启用",
+		zap.Uint("user_id", userID),
+		zap.String("operation", "confirm_two_factor"))
+
+	return &model.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset 重置用户的两步验证（管理员操作），用户下次登录仅需密码
+func (s *TwoFactorService) Reset(ctx context.Context, userID uint) error {
+	if err := s.twoFactorRepo.DeleteByUserID(ctx, userID); err != nil {
+		logger.FromContext(ctx).Error("重置两步验证失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "reset_two_factor"))
+		return apperrors.NewTwoFactorResetFailedError()
+	}
+
+	logger.FromContext(ctx).Info("两步验证已重置",
+		zap.Uint("user_id", userID),
+		zap.String("operation", "reset_two_factor"))
+	return nil
+}
+
+// VerifyCode 校验用户提交的验证码
+// 先按 TOTP 校验，失败时再尝试一次性恢复码（使用后立即失效）
+func (s *TwoFactorService) VerifyCode(ctx context.Context, userID uint, code string) (bool, error) {
+	twoFactor, err := s.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !twoFactor.Enabled {
+		return false, nil
+	}
+
+	if s.verifyTOTP(ctx, userID, twoFactor.Secret, code) {
+		return true, nil
+	}
+
+	return s.consumeRecoveryCode(ctx, twoFactor, code)
+}
+
+// CreateChallenge 创建登录挑战，返回给客户端的挑战令牌
+func (s *TwoFactorService) CreateChallenge(ctx context.Context, challenge *TwoFactorChallenge) (*model.TwoFactorChallengeResponse, error) {
+	token := uuid.New().String()
+	challenge.CreatedAt = time.Now()
+
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return nil, fmt.Errorf("序列化两步验证挑战失败: %w", err)
+	}
+
+	if err := s.redisClient.Set(ctx, fmt.Sprintf(RedisKeyTwoFactorChallenge, token), data, s.challengeTTL); err != nil {
+		return nil, fmt.Errorf("保存两步验证挑战失败: %w", err)
+	}
+
+	indexKey := fmt.Sprintf(RedisKeyTwoFactorUserChallenges, challenge.UserID)
+	if err := s.redisClient.SAdd(ctx, indexKey, token); err != nil {
+		return nil, fmt.Errorf("保存两步验证挑战索引失败: %w", err)
+	}
+	if err := s.redisClient.Expire(ctx, indexKey, s.challengeTTL); err != nil {
+		return nil, fmt.Errorf("保存两步验证挑战索引失败: %w", err)
+	}
+
+	return &model.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresIn:         int64(s.challengeTTL.Seconds()),
+	}, nil
+}
+
+// GetChallenge 获取登录挑战
+func (s *TwoFactorService) GetChallenge(ctx context.Context, token string) (*TwoFactorChallenge, error) {
+	data, err := s.redisClient.Get(ctx, fmt.Sprintf(RedisKeyTwoFactorChallenge, token))
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge TwoFactorChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil {
+		return nil, fmt.Errorf("反序列化两步验证挑战失败: %w", err)
+	}
+	return &challenge, nil
+}
+
+// RecordChallengeFailure 记录一次验证失败，超过最大次数时作废该挑战
+// 返回: bool - 挑战是否已作废
+func (s *TwoFactorService) RecordChallengeFailure(ctx context.Context, token string) (bool, error) {
+	attemptsKey := fmt.Sprintf(RedisKeyTwoFactorAttempts, token)
+	attempts, err := s.redisClient.Incr(ctx, attemptsKey)
+	if err != nil {
+		return false, err
+	}
+	if attempts == 1 {
+		s.redisClient.Expire(ctx, attemptsKey, s.challengeTTL)
+	}
+
+	if attempts >= MaxTwoFactorAttempts {
+		return true, s.DeleteChallenge(ctx, token)
+	}
+	return false, nil
+}
+
+// DeleteChallenge 删除登录挑战（验证成功或作废时调用）
+func (s *TwoFactorService) DeleteChallenge(ctx context.Context, token string) error {
+	return s.redisClient.Del(ctx,
+		fmt.Sprintf(RedisKeyTwoFactorChallenge, token),
+		fmt.Sprintf(RedisKeyTwoFactorAttempts, token))
+}
+
+// DeleteUserChallenges 作废用户所有未完成的登录挑战（账户锁定时调用）
+func (s *TwoFactorService) DeleteUserChallenges(ctx context.Context, userID uint) error {
+	indexKey := fmt.Sprintf(RedisKeyTwoFactorUserChallenges, userID)
+	tokens, err := s.redisClient.SMembers(ctx, indexKey)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tokens)*2+1)
+	for _, token := range tokens {
+		keys = append(keys,
+			fmt.Sprintf(RedisKeyTwoFactorChallenge, token),
+			fmt.Sprintf(RedisKeyTwoFactorAttempts, token))
+	}
+	keys = append(keys, indexKey)
+	return s.redisClient.Del(ctx, keys...)
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, userID uint, secret, code string) bool {
+	step, ok := auth.ValidateTOTPCode(secret, code, time.Now())
+	if !ok {
+		return false
+	}
+
+	// 覆盖允许的时间偏差窗口即可
+	ttl := time.Duration(auth.TOTPPeriod*(2*auth.TOTPSkew+1)) * time.Second
+	fresh, err := s.redisClient.SetNX(ctx, fmt.Sprintf(RedisKeyTwoFactorUsedStep, userID, step), 1, ttl)
+	if err != nil {
+		logger.FromContext(ctx).Warn("记录两步验证码使用状态失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err))
+		return false
+	}
+	return fresh
+}
+
+// consumeRecoveryCode 校验并消耗一个恢复码
+func (s *TwoFactorService) consumeRecoveryCode(ctx context.Context, twoFactor *model.UserTwoFactor, code string) (bool, error) {
+	hashes := decodeRecoveryCodes(twoFactor.RecoveryCodes)
+	target := hashRecoveryCode(code)
+
+	for i, hash := range hashes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(target)) != 1 {
+			continue
+		}
+
+		remaining := append(append([]string{}, hashes[:i]...), hashes[i+1:]...)
+		updated, err := s.twoFactorRepo.UpdateRecoveryCodes(ctx, twoFactor.ID, twoFactor.RecoveryCodes, encodeRecoveryCodes(remaining))
+		if err != nil {
+			return false, err
+		}
+		if updated {
+			logger.FromContext(ctx).Info("已使用恢复码完成两步验证",
+				zap.Uint("user_id", twoFactor.UserID),
+				zap.Int("remaining", len(remaining)))
+		}
+		return updated, nil
+	}
+	return false, nil
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文和对应的哈希
+func generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashes := make([]string, 0, RecoveryCodeCount)
+
+	for i := 0; i < RecoveryCodeCount; i++ {
+		bytes := make([]byte, 6)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(bytes))
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 计算恢复码哈希（忽略大小写、空格和连字符）
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.TrimSpace(code))
+	normalized = strings.ReplaceAll(normalized, "-", "")
+	normalized = strings.ReplaceAll(normalized, " ", "")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeRecoveryCodes(hashes []string) string {
+	data, _ := json.Marshal(hashes)
+	return string(data)
+}
+
+func decodeRecoveryCodes(data string) []string {
+	var hashes []string
+	if data == "" {
+		return hashes
+	}
+	if err := json.Unmarshal([]byte(data), &hashes); err != nil {
+		return nil
+	}
+	return hashes
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/auth"
 	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/metrics"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/storage"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/tracing"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -17,39 +22,65 @@ import (
 // UserRepositoryInterface 定义用户仓库接口
 type UserRepositoryInterface interface {
 	Create(user *model.User) error
-	GetByID(id uint) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
+	GetByID(ctx context.Context, id uint) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
 	Update(user *model.User) error
+	UpdateColumns(ctx context.Context, user *model.User, columns ...string) error
 	Delete(id uint) error
 	List(offset, limit int) ([]model.User, int64, error)
-	ListByVisibility(currentUserID uint, currentUserRole string, offset, limit int) ([]model.User, int64, error)
+	ListByVisibility(currentUserID uint, currentUserRole string, req *model.UserListRequest) ([]model.User, int64, error)
+	ListAllByVisibility(currentUserID uint, currentUserRole string, req *model.UserListRequest, limit int) ([]model.User, error)
+	ListByUsernamesOrEmails(usernames, emails []string) ([]model.User, error)
+	CreateBatch(users []model.User, roleIDs []uint, assignedBy uint) error
 	CheckUsernameExists(username string) (bool, error)
 	CheckEmailExists(email string) (bool, error)
 	CheckUsernameExistsExcludeID(username string, excludeID uint) (bool, error)
 	CheckEmailExistsExcludeID(email string, excludeID uint) (bool, error)
+	UpdatePassword(userID uint, hashedPassword string) error
+	ListDeleted(query *model.ListQuery) ([]model.User, int64, error)
+	GetDeletedByID(id uint) (*model.User, error)
+	Restore(id uint) error
+	Purge(id uint) error
 }
 
 // JWTManagerInterface 定义 JWT 管理器接口
 type JWTManagerInterface interface {
 	GenerateToken(userID uint, username, role string) (string, error)
 	GenerateTokenPair(userID uint, username, role string) (*auth.TokenPair, error)
+	GenerateTokenPairWithSession(userID uint, username, role, sessionID string) (*auth.TokenPair, error)
 	ValidateToken(tokenString string) (*auth.Claims, error)
 	ValidateRefreshToken(tokenString string) (*auth.Claims, error)
 	GetTokenExpiration(claims *auth.Claims) time.Duration
 }
 
+// TwoFactorServiceInterface 定义两步验证服务接口
+type TwoFactorServiceInterface interface {
+	IsEnabled(ctx context.Context, userID uint) (bool, error)
+	VerifyCode(ctx context.Context, userID uint, code string) (bool, error)
+	CreateChallenge(ctx context.Context, challenge *TwoFactorChallenge) (*model.TwoFactorChallengeResponse, error)
+	GetChallenge(ctx context.Context, token string) (*TwoFactorChallenge, error)
+	RecordChallengeFailure(ctx context.Context, token string) (bool, error)
+	DeleteChallenge(ctx context.Context, token string) error
+	DeleteUserChallenges(ctx context.Context, userID uint) error
+}
+
 // SessionServiceInterface 定义会话服务接口
 type SessionServiceInterface interface {
-	CreateSession(ctx context.Context, userID uint, username, refreshToken, deviceInfo, ipAddress, userAgent string) error
-	GetSession(ctx context.Context, userID uint) (*SessionInfo, error)
-	UpdateLastActivity(ctx context.Context, userID uint) error
-	DeleteSession(ctx context.Context, userID uint) error
+	CreateSession(ctx context.Context, sessionID string, userID uint, username, role string, tokenPair *auth.TokenPair, deviceInfo, ipAddress, userAgent string) error
+	RotateRefreshToken(ctx context.Context, sessionInfo *SessionInfo, tokenPair *auth.TokenPair) error
+	GetSession(ctx context.Context, userID uint, sessionID string) (*SessionInfo, error)
+	ListSessions(ctx context.Context, userID uint) ([]*SessionInfo, error)
+	UpdateLastActivity(ctx context.Context, userID uint, sessionID string) error
+	DeleteSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID uint, exceptSessionID string) (int, error)
 	ValidateRefreshToken(ctx context.Context, refreshToken string) (*SessionInfo, error)
 	AddTokenToBlacklist(ctx context.Context, jti string, expiration time.Duration) error
 	IsTokenBlacklisted(ctx context.Context, jti string) bool
 	SetUserActive(ctx context.Context, userID uint) error
 	CacheUserPermissions(ctx context.Context, userID uint, role string, permissions []string) error
+	SetPasswordChangeRequired(ctx context.Context, userID uint, required bool) error
 }
 
 type UserService struct {
@@ -60,6 +91,11 @@ type UserService struct {
 	roleRepo             RoleRepositoryInterface
 	permissionService    *PermissionService
 	loginRateLimitService *LoginRateLimitService
+	casbinService        CasbinServiceInterface
+	auditLogService      *AuditLogService
+	twoFactorService     TwoFactorServiceInterface
+	passwordPolicy       *PasswordPolicyService
+	fileStorage          storage.Storage
 }
 
 func NewUserService(
@@ -70,6 +106,11 @@ func NewUserService(
 	roleRepo RoleRepositoryInterface,
 	permissionService *PermissionService,
 	loginRateLimitService *LoginRateLimitService,
+	casbinService CasbinServiceInterface,
+	auditLogService *AuditLogService,
+	twoFactorService TwoFactorServiceInterface,
+	passwordPolicy *PasswordPolicyService,
+	fileStorage storage.Storage,
 ) *UserService {
 	return &UserService{
 		userRepo:             userRepo,
@@ -79,6 +120,11 @@ func NewUserService(
 		roleRepo:             roleRepo,
 		permissionService:    permissionService,
 		loginRateLimitService: loginRateLimitService,
+		casbinService:        casbinService,
+		auditLogService:      auditLogService,
+		twoFactorService:     twoFactorService,
+		passwordPolicy:       passwordPolicy,
+		fileStorage:          fileStorage,
 	}
 }
 
@@ -94,9 +140,16 @@ func (s *UserService) RegisterWithCreator(req *model.CreateUserRequest, creatorI
 		zap.String("role", req.Role),
 		zap.Any("creator_id", creatorID))
 
-	// 检查用户名是否已存在
-	_, err := s.userRepo.GetByUsername(req.Username)
-	if err == nil {
+	// 检查用户名是否已存在（已删除的用户仍占用用户名，需彻底删除后才能重新使用）
+	exists, err := s.userRepo.CheckUsernameExists(req.Username)
+	if err != nil {
+		logger.Error("用户注册失败：检查用户名失败",
+			zap.String("username", req.Username),
+			zap.Error(err),
+			zap.String("operation", "register"))
+		return nil, apperrors.NewUsernameCheckFailedError()
+	}
+	if exists {
 		logger.Warn("用户注册失败：用户名已存在", 
 			zap.String("username", req.Username),
 			zap.String("operation", "register"))
@@ -104,8 +157,15 @@ func (s *UserService) RegisterWithCreator(req *model.CreateUserRequest, creatorI
 	}
 
 	// 检查邮箱是否已存在
-	_, err = s.userRepo.GetByEmail(req.Email)
-	if err == nil {
+	exists, err = s.userRepo.CheckEmailExists(req.Email)
+	if err != nil {
+		logger.Error("用户注册失败：检查邮箱失败",
+			zap.String("email", req.Email),
+			zap.Error(err),
+			zap.String("operation", "register"))
+		return nil, apperrors.NewEmailCheckFailedError()
+	}
+	if exists {
 		logger.Warn("用户注册失败：邮箱已存在", 
 			zap.String("username", req.Username),
 			zap.String("email", req.Email),
@@ -113,6 +173,15 @@ func (s *UserService) RegisterWithCreator(req *model.CreateUserRequest, creatorI
 		return nil, apperrors.NewEmailExistsError()
 	}
 
+	// 校验密码强度
+	if err := s.passwordPolicy.ValidateStrength(req.Password, req.Username, req.Email); err != nil {
+		logger.Warn("用户注册失败：密码不符合密码策略",
+			zap.String("username", req.Username),
+			zap.Error(err),
+			zap.String("operation", "register"))
+		return nil, err
+	}
+
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -167,13 +236,17 @@ func (s *UserService) RegisterWithCreator(req *model.CreateUserRequest, creatorI
 	return user, nil
 }
 
-func (s *UserService) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
+func (s *UserService) Login(req *model.LoginRequest) (*model.LoginResponse, *model.TwoFactorChallengeResponse, error) {
 	return s.LoginWithContext(context.Background(), req, "", "", "")
 }
 
 // LoginWithContext 带会话上下文信息的登录
-func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequest, deviceInfo, ipAddress, userAgent string) (*model.LoginResponse, error) {
-	logger.Info("开始用户登录流程", 
+// 用户启用两步验证时不签发令牌，而是返回登录挑战，需调用 VerifyTwoFactorLogin 完成登录
+func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequest, deviceInfo, ipAddress, userAgent string) (*model.LoginResponse, *model.TwoFactorChallengeResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Login", attribute.String("user.name", req.Username))
+	defer span.End()
+
+	logger.FromContext(ctx).Info("开始用户登录流程", 
 		zap.String("username", req.Username),
 		zap.String("ip_address", ipAddress),
 		zap.String("user_agent", userAgent),
@@ -181,158 +254,361 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 
 	// 1. IP限流检查
 	if s.loginRateLimitService != nil {
-		allowed, remaining, err := s.loginRateLimitService.CheckIPRateLimit(ctx, ipAddress)
+		stepCtx, step := tracing.Start(ctx, "login.check_ip_rate_limit")
+		allowed, remaining, err := s.loginRateLimitService.CheckIPRateLimit(stepCtx, ipAddress)
+		step.Fail(err)
+		step.End()
 		if err != nil {
-			logger.Warn("IP限流检查失败，继续处理", zap.Error(err))
+			logger.FromContext(ctx).Warn("IP限流检查失败，继续处理", zap.Error(err))
 		} else if !allowed {
-			logger.Warn("IP登录请求频率超限",
+			logger.FromContext(ctx).Warn("IP登录请求频率超限",
 				zap.String("ip", ipAddress),
 				zap.Int("remaining", remaining))
-			return nil, apperrors.NewRateLimitErrorWithCode("")
+			metrics.RecordLoginFailure(metrics.LoginReasonRateLimited)
+			return nil, nil, apperrors.NewRateLimitErrorWithCode("")
 		}
 	}
 
 	// 2. 检查账户是否被锁定
 	if s.loginRateLimitService != nil {
-		locked, ttl, err := s.loginRateLimitService.CheckAccountLocked(ctx, req.Username)
+		stepCtx, step := tracing.Start(ctx, "login.check_account_locked")
+		locked, ttl, err := s.loginRateLimitService.CheckAccountLocked(stepCtx, req.Username)
+		step.Fail(err)
+		step.End()
 		if err != nil {
-			logger.Warn("账户锁定检查失败，继续处理", zap.Error(err))
+			logger.FromContext(ctx).Warn("账户锁定检查失败，继续处理", zap.Error(err))
 		} else if locked {
 			minutes := int(ttl.Minutes())
 			if minutes < 1 {
 				minutes = 1
 			}
-			logger.Warn("账户处于锁定状态",
+			logger.FromContext(ctx).Warn("账户处于锁定状态",
 				zap.String("username", req.Username),
 				zap.Duration("remaining", ttl))
-			return nil, apperrors.NewAccountLockedErrorWithCode("")
+			metrics.RecordLoginFailure(metrics.LoginReasonAccountLocked)
+			return nil, nil, apperrors.NewAccountLockedErrorWithCode("")
 		}
 	}
 
 	// 3. 验证验证码
 	if s.captchaService != nil {
-		if !s.captchaService.VerifyCaptcha(req.CaptchaID, req.CaptchaCode) {
-			logger.Warn("登录失败：验证码错误", 
+		_, step := tracing.Start(ctx, "login.verify_captcha")
+		captchaValid := s.captchaService.VerifyCaptcha(req.CaptchaID, req.CaptchaCode)
+		step.End()
+		if !captchaValid {
+			logger.FromContext(ctx).Warn("登录失败：验证码错误", 
 				zap.String("username", req.Username),
 				zap.String("captcha_id", req.CaptchaID),
 				zap.String("ip_address", ipAddress),
 				zap.String("operation", "login"))
-			return nil, apperrors.NewInvalidCaptchaErrorWithCode("")
+			metrics.RecordLoginFailure(metrics.LoginReasonInvalidCaptcha)
+			return nil, nil, apperrors.NewInvalidCaptchaErrorWithCode("")
 		}
-		logger.Debug("验证码验证通过", 
+		logger.FromContext(ctx).Debug("验证码验证通过", 
 			zap.String("username", req.Username),
 			zap.String("captcha_id", req.CaptchaID))
 	}
 
-	user, err := s.userRepo.GetByUsername(req.Username)
+	user, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("登录失败：用户不存在", 
+			logger.FromContext(ctx).Warn("登录失败：用户不存在", 
 				zap.String("username", req.Username),
 				zap.String("ip_address", ipAddress),
 				zap.String("operation", "login"))
-			return nil, apperrors.NewInvalidCredentialsErrorWithCode("")
+			metrics.RecordLoginFailure(metrics.LoginReasonUserNotFound)
+			return nil, nil, apperrors.NewInvalidCredentialsErrorWithCode("")
 		}
-		logger.Error("登录失败：查询用户时发生错误", 
+		logger.FromContext(ctx).Error("登录失败：查询用户时发生错误", 
 			zap.String("username", req.Username),
 			zap.Error(err),
 			zap.String("operation", "login"))
-		return nil, apperrors.NewUserQueryFailedError()
+		metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+		return nil, nil, apperrors.NewUserQueryFailedError()
 	}
 
-	if !utils.CheckPassword(req.Password, user.Password) {
-		logger.Warn("登录失败：密码错误", 
+	_, step := tracing.Start(ctx, "login.verify_password")
+	passwordValid := utils.CheckPassword(req.Password, user.Password)
+	step.End()
+	if !passwordValid {
+		logger.FromContext(ctx).Warn("登录失败：密码错误", 
 			zap.String("username", req.Username),
 			zap.Uint("user_id", user.ID),
 			zap.String("ip_address", ipAddress),
 			zap.String("operation", "login"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInvalidPassword)
 		
 		// 记录登录失败
 		if s.loginRateLimitService != nil {
 			failCount, shouldLock, err := s.loginRateLimitService.RecordLoginFailure(ctx, req.Username)
 			if err != nil {
-				logger.Error("记录登录失败次数失败", zap.Error(err))
+				logger.FromContext(ctx).Error("记录登录失败次数失败", zap.Error(err))
 			} else if shouldLock {
 				// 账户已被锁定
-				return nil, apperrors.NewAccountLockedErrorWithCode("")
+				return nil, nil, apperrors.NewAccountLockedErrorWithCode("")
 			} else {
 				// 返回剩余尝试次数
-				remaining := MaxLoginFailsPerAccount - failCount
-				logger.Warn("登录失败：密码错误", 
+				remaining := s.loginRateLimitService.MaxFailsPerAccount() - failCount
+				logger.FromContext(ctx).Warn("登录失败：密码错误", 
 					zap.String("username", req.Username),
 					zap.Int("fail_count", failCount),
 					zap.Int("remaining", remaining))
-				return nil, apperrors.NewInvalidCredentialsErrorWithCode("")
+				return nil, nil, apperrors.NewInvalidCredentialsErrorWithCode("")
 			}
 		}
 		
-		return nil, apperrors.NewInvalidCredentialsErrorWithCode("")
+		return nil, nil, apperrors.NewInvalidCredentialsErrorWithCode("")
+	}
+
+	// 密码正确后再检查账户状态，避免通过错误信息探测账户状态
+	if err := checkUserStatus(user, time.Now()); err != nil {
+		logger.FromContext(ctx).Warn("登录失败：账户状态不允许登录",
+			zap.String("username", user.Username),
+			zap.Uint("user_id", user.ID),
+			zap.String("status", user.Status),
+			zap.Any("expires_at", user.ExpiresAt),
+			zap.String("ip_address", ipAddress),
+			zap.String("operation", "login"))
+		metrics.RecordLoginFailure(metrics.LoginReasonAccountDisabled)
+		return nil, nil, err
 	}
 
-	logger.Debug("用户认证成功", 
+	logger.FromContext(ctx).Debug("用户认证成功", 
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 		zap.String("role", user.Role))
 
+	// 启用两步验证时返回登录挑战，令牌在验证通过后签发
+	if s.twoFactorService != nil {
+		stepCtx, step := tracing.Start(ctx, "login.check_two_factor")
+		enabled, err := s.twoFactorService.IsEnabled(stepCtx, user.ID)
+		step.Fail(err)
+		step.End()
+		if err != nil {
+			logger.FromContext(ctx).Error("登录失败：查询两步验证状态失败",
+				zap.String("username", user.Username),
+				zap.Uint("user_id", user.ID),
+				zap.Error(err),
+				zap.String("operation", "login"))
+			metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+			return nil, nil, apperrors.NewTwoFactorQueryFailedError()
+		}
+
+		if enabled {
+			challenge, err := s.twoFactorService.CreateChallenge(ctx, &TwoFactorChallenge{
+				UserID:     user.ID,
+				Username:   user.Username,
+				DeviceInfo: deviceInfo,
+				IPAddress:  ipAddress,
+				UserAgent:  userAgent,
+			})
+			if err != nil {
+				logger.FromContext(ctx).Error("登录失败：创建两步验证挑战失败",
+					zap.String("username", user.Username),
+					zap.Uint("user_id", user.ID),
+					zap.Error(err),
+					zap.String("operation", "login"))
+				metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+				return nil, nil, apperrors.NewSessionCreateFailedError()
+			}
+
+			logger.FromContext(ctx).Info("密码验证通过，等待两步验证",
+				zap.String("username", user.Username),
+				zap.Uint("user_id", user.ID),
+				zap.String("ip_address", ipAddress),
+				zap.String("operation", "login"))
+			metrics.RecordLoginTwoFactorRequired()
+			return nil, challenge, nil
+		}
+	}
+
+	response, err := s.completeLogin(ctx, user, deviceInfo, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, nil, nil
+}
+
+// VerifyTwoFactorLogin 校验两步验证码并完成登录
+// 验证码错误计入账户登录失败次数；单个挑战失败次数过多时作废，需重新输入密码
+func (s *UserService) VerifyTwoFactorLogin(ctx context.Context, req *model.TwoFactorVerifyRequest) (*model.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.VerifyTwoFactorLogin")
+	defer span.End()
+
+	if s.twoFactorService == nil {
+		return nil, apperrors.NewTwoFactorChallengeInvalidError()
+	}
+
+	challenge, err := s.twoFactorService.GetChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		logger.FromContext(ctx).Warn("两步验证失败：挑战无效或已过期",
+			zap.Error(err),
+			zap.String("operation", "verify_two_factor"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInvalidTwoFactor)
+		return nil, apperrors.NewTwoFactorChallengeInvalidError()
+	}
+
+	// 账户锁定后不再校验验证码，避免在多个挑战之间继续暴力尝试
+	if s.loginRateLimitService != nil {
+		locked, ttl, err := s.loginRateLimitService.CheckAccountLocked(ctx, challenge.Username)
+		if err != nil {
+			logger.FromContext(ctx).Warn("账户锁定检查失败，继续处理", zap.Error(err))
+		} else if locked {
+			logger.FromContext(ctx).Warn("两步验证失败：账户处于锁定状态",
+				zap.String("username", challenge.Username),
+				zap.Duration("remaining", ttl),
+				zap.String("operation", "verify_two_factor"))
+			metrics.RecordLoginFailure(metrics.LoginReasonAccountLocked)
+			s.invalidateTwoFactorChallenges(ctx, challenge.UserID)
+			return nil, apperrors.NewAccountLockedErrorWithCode("")
+		}
+	}
+
+	ok, err := s.twoFactorService.VerifyCode(ctx, challenge.UserID, req.Code)
+	if err != nil {
+		logger.FromContext(ctx).Error("两步验证失败：校验验证码时发生错误",
+			zap.Uint("user_id", challenge.UserID),
+			zap.Error(err),
+			zap.String("operation", "verify_two_factor"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+		return nil, apperrors.NewTwoFactorQueryFailedError()
+	}
+
+	if !ok {
+		logger.FromContext(ctx).Warn("两步验证失败：验证码错误",
+			zap.String("username", challenge.Username),
+			zap.Uint("user_id", challenge.UserID),
+			zap.String("ip_address", challenge.IPAddress),
+			zap.String("operation", "verify_two_factor"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInvalidTwoFactor)
+
+		if s.loginRateLimitService != nil {
+			if _, shouldLock, err := s.loginRateLimitService.RecordLoginFailure(ctx, challenge.Username); err != nil {
+				logger.FromContext(ctx).Error("记录登录失败次数失败", zap.Error(err))
+			} else if shouldLock {
+				s.invalidateTwoFactorChallenges(ctx, challenge.UserID)
+				return nil, apperrors.NewAccountLockedErrorWithCode("")
+			}
+		}
+
+		exhausted, err := s.twoFactorService.RecordChallengeFailure(ctx, req.ChallengeToken)
+		if err != nil {
+			logger.FromContext(ctx).Warn("记录两步验证失败次数失败", zap.Error(err))
+		} else if exhausted {
+			return nil, apperrors.NewTwoFactorChallengeInvalidError()
+		}
+		return nil, apperrors.NewTwoFactorInvalidCodeError()
+	}
+
+	// 挑战只能使用一次
+	if err := s.twoFactorService.DeleteChallenge(ctx, req.ChallengeToken); err != nil {
+		logger.FromContext(ctx).Warn("删除两步验证挑战失败", zap.Error(err))
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		logger.FromContext(ctx).Error("两步验证失败：查询用户时发生错误",
+			zap.Uint("user_id", challenge.UserID),
+			zap.Error(err),
+			zap.String("operation", "verify_two_factor"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+		return nil, apperrors.NewUserQueryFailedError()
+	}
+
+	// 挑战创建后账户可能已被禁用
+	if err := checkUserStatus(user, time.Now()); err != nil {
+		logger.FromContext(ctx).Warn("两步验证失败：账户状态不允许登录",
+			zap.Uint("user_id", user.ID),
+			zap.String("status", user.Status),
+			zap.String("operation", "verify_two_factor"))
+		metrics.RecordLoginFailure(metrics.LoginReasonAccountDisabled)
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, challenge.DeviceInfo, challenge.IPAddress, challenge.UserAgent)
+}
+
+// invalidateTwoFactorChallenges 作废用户所有未完成的两步验证挑战
+func (s *UserService) invalidateTwoFactorChallenges(ctx context.Context, userID uint) {
+	if err := s.twoFactorService.DeleteUserChallenges(ctx, userID); err != nil {
+		logger.FromContext(ctx).Warn("作废两步验证挑战失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "verify_two_factor"))
+	}
+}
+
+// completeLogin 清除登录失败记录，签发令牌并创建会话
+func (s *UserService) completeLogin(ctx context.Context, user *model.User, deviceInfo, ipAddress, userAgent string) (*model.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.completeLogin", attribute.Int64("user.id", int64(user.ID)))
+	defer span.End()
+
 	// 清除登录失败记录（登录成功）
 	if s.loginRateLimitService != nil {
-		if err := s.loginRateLimitService.ClearLoginFailures(ctx, req.Username); err != nil {
-			logger.Warn("清除登录失败记录失败", zap.Error(err))
+		if err := s.loginRateLimitService.ClearLoginFailures(ctx, user.Username); err != nil {
+			logger.FromContext(ctx).Warn("清除登录失败记录失败", zap.Error(err))
 		}
 	}
 
-	// 生成令牌对
-	tokenPair, err := s.jwtManager.GenerateTokenPair(user.ID, user.Username, user.Role)
+	// 生成绑定到新会话的令牌对
+	sessionID := NewSessionID()
+	tokenPair, err := s.jwtManager.GenerateTokenPairWithSession(user.ID, user.Username, user.Role, sessionID)
 	if err != nil {
-		logger.Error("生成令牌失败", 
+		logger.FromContext(ctx).Error("生成令牌失败", 
 			zap.String("username", user.Username),
 			zap.Uint("user_id", user.ID),
 			zap.Error(err),
 			zap.String("operation", "login"))
+		metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
 		return nil, apperrors.NewTokenGenerateFailedError()
 	}
 
 	// 在 Redis 中创建会话
 	if s.sessionService != nil {
-		err = s.sessionService.CreateSession(ctx, user.ID, user.Username, tokenPair.RefreshToken, deviceInfo, ipAddress, userAgent)
+		err = s.sessionService.CreateSession(ctx, sessionID, user.ID, user.Username, user.Role, tokenPair, deviceInfo, ipAddress, userAgent)
 		if err != nil {
-			logger.Error("创建会话失败", 
+			logger.FromContext(ctx).Error("创建会话失败", 
 				zap.String("username", user.Username),
 				zap.Uint("user_id", user.ID),
 				zap.Error(err),
 				zap.String("operation", "login"))
+			metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
 			return nil, apperrors.NewSessionCreateFailedError()
 		}
 
 		// 设置用户为活跃状态
 		s.sessionService.SetUserActive(ctx, user.ID)
 
+		// 同步必须修改密码的标记，标记存在时只能访问修改密码等少数接口
+		if err := s.sessionService.SetPasswordChangeRequired(ctx, user.ID, user.MustChangePassword); err != nil {
+			logger.FromContext(ctx).Error("同步修改密码标记失败",
+				zap.Uint("user_id", user.ID),
+				zap.Error(err),
+				zap.String("operation", "login"))
+			metrics.RecordLoginFailure(metrics.LoginReasonInternalError)
+			return nil, apperrors.NewSessionCreateFailedError()
+		}
+
 		// 缓存用户权限
 		permissions := []string{} // 可根据权限系统扩展
 		s.sessionService.CacheUserPermissions(ctx, user.ID, user.Role, permissions)
 		
-		logger.Debug("会话创建成功", 
+		logger.FromContext(ctx).Debug("会话创建成功", 
 			zap.String("username", user.Username),
-			zap.Uint("user_id", user.ID))
+			zap.Uint("user_id", user.ID),
+			zap.String("session_id", sessionID))
 	}
 
-	// 创建安全的用户响应（不包含密码）
-	safeUser := model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      user.Role,
-		Status:    user.Status,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}
+	// 创建安全的用户响应（不包含密码），前端根据 must_change_password 引导用户修改密码
+	safeUser := user.ToResponse()
 
-	logger.Info("用户登录成功", 
+	logger.FromContext(ctx).Info("用户登录成功", 
 		zap.String("username", user.Username),
 		zap.Uint("user_id", user.ID),
 		zap.String("role", user.Role),
 		zap.String("ip_address", ipAddress),
 		zap.String("operation", "login"))
+	metrics.RecordLoginSuccess()
 
 	return &model.LoginResponse{
 		AccessToken:      tokenPair.AccessToken,
@@ -346,10 +622,20 @@ func (s *UserService) LoginWithContext(ctx context.Context, req *model.LoginRequ
 
 // RefreshToken 使用刷新令牌更新访问令牌
 func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.RefreshTokenResponse, error) {
-	logger.Debug("开始刷新令牌流程")
+	return s.RefreshTokenWithClientInfo(ctx, req, "", "")
+}
+
+// RefreshTokenWithClientInfo 使用刷新令牌更新令牌对（带客户端信息）
+// 每次刷新都会轮换刷新令牌，旧令牌立即失效；
+// 已轮换的旧令牌再次出现时吊销整个会话并记录审计日志
+func (s *UserService) RefreshTokenWithClientInfo(ctx context.Context, req *model.RefreshTokenRequest, ipAddress, userAgent string) (*model.RefreshTokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RefreshToken")
+	defer span.End()
+
+	logger.FromContext(ctx).Debug("开始刷新令牌流程")
 
 	if s.sessionService == nil {
-		logger.Error("刷新令牌失败：会话服务不可用", 
+		logger.FromContext(ctx).Error("刷新令牌失败：会话服务不可用", 
 			zap.String("operation", "refresh_token"))
 		return nil, apperrors.NewSessionServiceUnavailableError()
 	}
@@ -357,20 +643,61 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 	// 验证刷新令牌并获取会话
 	sessionInfo, err := s.sessionService.ValidateRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
-		logger.Warn("刷新令牌失败：无效的刷新令牌", 
+		var reuseErr *RefreshTokenReuseError
+		if errors.As(err, &reuseErr) {
+			logger.FromContext(ctx).Warn("检测到刷新令牌重复使用，已吊销整个会话",
+				zap.Uint("user_id", reuseErr.UserID),
+				zap.String("username", reuseErr.Username),
+				zap.String("session_id", reuseErr.SessionID),
+				zap.String("ip_address", ipAddress),
+				zap.String("operation", "refresh_token"))
+			s.recordRefreshTokenReuse(ctx, reuseErr, ipAddress, userAgent)
+			return nil, apperrors.NewRefreshTokenReusedError()
+		}
+
+		logger.FromContext(ctx).Warn("刷新令牌失败：无效的刷新令牌", 
 			zap.Error(err),
 			zap.String("operation", "refresh_token"))
 		return nil, apperrors.NewUnauthorizedErrorWithCode("")
 	}
 
-	logger.Debug("刷新令牌验证成功", 
+	logger.FromContext(ctx).Debug("刷新令牌验证成功", 
 		zap.String("username", sessionInfo.Username),
 		zap.Uint("user_id", sessionInfo.UserID))
 
-	// 生成新的令牌对
-	tokenPair, err := s.jwtManager.GenerateTokenPair(sessionInfo.UserID, sessionInfo.Username, "user") // 角色可从会话中获取
+	// 账户被删除、禁用或过期后不再续期，并吊销该会话
+	user, err := s.userRepo.GetByID(ctx, sessionInfo.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(ctx).Error("刷新令牌失败：查询用户时发生错误",
+			zap.Uint("user_id", sessionInfo.UserID),
+			zap.Error(err),
+			zap.String("operation", "refresh_token"))
+		return nil, apperrors.NewUserQueryFailedError()
+	}
+	var statusErr error
+	if err != nil {
+		statusErr = apperrors.NewUnauthorizedErrorWithCode("用户不存在")
+	} else {
+		statusErr = checkUserStatus(user, time.Now())
+	}
+	if statusErr != nil {
+		logger.FromContext(ctx).Warn("刷新令牌失败：账户不存在或状态不允许登录，吊销会话",
+			zap.Uint("user_id", sessionInfo.UserID),
+			zap.String("session_id", sessionInfo.SessionID),
+			zap.String("operation", "refresh_token"))
+		if err := s.sessionService.RevokeSession(ctx, sessionInfo.UserID, sessionInfo.SessionID); err != nil {
+			logger.FromContext(ctx).Error("吊销会话失败",
+				zap.Uint("user_id", sessionInfo.UserID),
+				zap.Error(err),
+				zap.String("operation", "refresh_token"))
+		}
+		return nil, statusErr
+	}
+
+	// 生成新的令牌对（沿用原会话）
+	tokenPair, err := s.jwtManager.GenerateTokenPairWithSession(sessionInfo.UserID, sessionInfo.Username, sessionInfo.Role, sessionInfo.SessionID)
 	if err != nil {
-		logger.Error("生成新令牌失败", 
+		logger.FromContext(ctx).Error("生成新令牌失败", 
 			zap.String("username", sessionInfo.Username),
 			zap.Uint("user_id", sessionInfo.UserID),
 			zap.Error(err),
@@ -378,10 +705,19 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 		return nil, apperrors.NewTokenGenerateFailedError()
 	}
 
-	// 用新的刷新令牌更新会话
-	err = s.sessionService.CreateSession(ctx, sessionInfo.UserID, sessionInfo.Username, tokenPair.RefreshToken, sessionInfo.DeviceInfo, sessionInfo.IPAddress, sessionInfo.UserAgent)
+	// 轮换刷新令牌，旧令牌加入黑名单
+	// 校验之后会话被吊销或令牌已被并发请求轮换时，本次刷新失败，需要重新登录或使用新令牌
+	err = s.sessionService.RotateRefreshToken(ctx, sessionInfo, tokenPair)
+	if errors.Is(err, ErrRefreshTokenConflict) || errors.Is(err, redis.Nil) {
+		logger.FromContext(ctx).Warn("刷新令牌失败：会话已被吊销或令牌已被轮换",
+			zap.Uint("user_id", sessionInfo.UserID),
+			zap.String("session_id", sessionInfo.SessionID),
+			zap.Error(err),
+			zap.String("operation", "refresh_token"))
+		return nil, apperrors.NewUnauthorizedErrorWithCode("")
+	}
 	if err != nil {
-		logger.Error("更新会话失败", 
+		logger.FromContext(ctx).Error("更新会话失败", 
 			zap.String("username", sessionInfo.Username),
 			zap.Uint("user_id", sessionInfo.UserID),
 			zap.Error(err),
@@ -389,29 +725,52 @@ func (s *UserService) RefreshToken(ctx context.Context, req *model.RefreshTokenR
 		return nil, apperrors.NewSessionUpdateFailedError()
 	}
 
-	// 更新最后活跃时间
-	s.sessionService.UpdateLastActivity(ctx, sessionInfo.UserID)
-
-	logger.Info("令牌刷新成功", 
+	logger.FromContext(ctx).Info("令牌刷新成功", 
 		zap.String("username", sessionInfo.Username),
 		zap.Uint("user_id", sessionInfo.UserID),
 		zap.String("operation", "refresh_token"))
 
 	return &model.RefreshTokenResponse{
-		AccessToken: tokenPair.AccessToken,
-		ExpiresIn:   tokenPair.ExpiresIn,
-		TokenType:   "Bearer",
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+		TokenType:    "Bearer",
 	}, nil
 }
 
+// recordRefreshTokenReuse 记录刷新令牌重复使用的审计日志
+func (s *UserService) recordRefreshTokenReuse(ctx context.Context, reuseErr *RefreshTokenReuseError, ipAddress, userAgent string) {
+	if s.auditLogService == nil {
+		return
+	}
+
+	s.auditLogService.Record(&model.AuditLog{
+		UserID:     reuseErr.UserID,
+		Username:   reuseErr.Username,
+		Action:     "刷新令牌重复使用",
+		Resource:   "session",
+		ResourceID: reuseErr.SessionID,
+		Method:     "POST",
+		Path:       "/auth/refresh",
+		IP:         ipAddress,
+		UserAgent:  userAgent,
+		Status:     401,
+		ErrorMsg:   "检测到已轮换的刷新令牌被再次使用，已吊销该会话",
+		RequestID:  logger.RequestIDFromContext(ctx),
+	})
+}
+
 // Logout 用户登出
 func (s *UserService) Logout(ctx context.Context, userID uint, accessToken string, req *model.LogoutRequest) error {
-	logger.Info("开始用户登出流程", 
+	ctx, span := tracing.Start(ctx, "UserService.Logout", attribute.Int64("user.id", int64(userID)))
+	defer span.End()
+
+	logger.FromContext(ctx).Info("开始用户登出流程", 
 		zap.Uint("user_id", userID),
 		zap.String("operation", "logout"))
 
 	if s.sessionService == nil {
-		logger.Error("登出失败：会话服务不可用", 
+		logger.FromContext(ctx).Error("登出失败：会话服务不可用", 
 			zap.Uint("user_id", userID),
 			zap.String("operation", "logout"))
 		return apperrors.NewSessionServiceUnavailableError()
@@ -420,14 +779,14 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 	// 验证并获取访问令牌声明
 	claims, err := s.jwtManager.ValidateToken(accessToken)
 	if err != nil {
-		logger.Warn("登出失败：无效的访问令牌", 
+		logger.FromContext(ctx).Warn("登出失败：无效的访问令牌", 
 			zap.Uint("user_id", userID),
 			zap.Error(err),
 			zap.String("operation", "logout"))
 		return apperrors.NewUnauthorizedErrorWithCode("")
 	}
 
-	logger.Debug("访问令牌验证成功", 
+	logger.FromContext(ctx).Debug("访问令牌验证成功", 
 		zap.Uint("user_id", userID),
 		zap.String("jti", claims.JTI))
 
@@ -436,14 +795,14 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 	if expiration > 0 {
 		err = s.sessionService.AddTokenToBlacklist(ctx, claims.JTI, expiration)
 		if err != nil {
-			logger.Error("添加令牌到黑名单失败", 
+			logger.FromContext(ctx).Error("添加令牌到黑名单失败", 
 				zap.Uint("user_id", userID),
 				zap.String("jti", claims.JTI),
 				zap.Error(err),
 				zap.String("operation", "logout"))
 			return apperrors.NewTokenBlacklistFailedError()
 		}
-		logger.Debug("访问令牌已加入黑名单", 
+		logger.FromContext(ctx).Debug("访问令牌已加入黑名单", 
 			zap.Uint("user_id", userID),
 			zap.String("jti", claims.JTI))
 	}
@@ -455,40 +814,127 @@ func (s *UserService) Logout(ctx context.Context, userID uint, accessToken strin
 			refreshExpiration := s.jwtManager.GetTokenExpiration(refreshClaims)
 			if refreshExpiration > 0 {
 				s.sessionService.AddTokenToBlacklist(ctx, refreshClaims.JTI, refreshExpiration)
-				logger.Debug("刷新令牌已加入黑名单", 
+				logger.FromContext(ctx).Debug("刷新令牌已加入黑名单", 
 					zap.Uint("user_id", userID),
 					zap.String("refresh_jti", refreshClaims.JTI))
 			}
 		} else {
-			logger.Warn("刷新令牌验证失败", 
+			logger.FromContext(ctx).Warn("刷新令牌验证失败", 
 				zap.Uint("user_id", userID),
 				zap.Error(err))
 		}
 	}
 
-	// 删除会话
-	err = s.sessionService.DeleteSession(ctx, userID)
+	// 删除当前会话
+	err = s.sessionService.DeleteSession(ctx, userID, claims.SessionID)
 	if err != nil {
-		logger.Error("删除会话失败", 
+		logger.FromContext(ctx).Error("删除会话失败", 
 			zap.Uint("user_id", userID),
 			zap.Error(err),
 			zap.String("operation", "logout"))
 		return apperrors.NewSessionDeleteFailedError()
 	}
 
-	logger.Info("用户登出成功", 
+	logger.FromContext(ctx).Info("用户登出成功", 
 		zap.Uint("user_id", userID),
 		zap.String("operation", "logout"))
 
 	return nil
 }
 
+// ListSessions 获取用户的登录会话列表
+// currentSessionID 为当前请求所属会话，用于标记 current
+func (s *UserService) ListSessions(ctx context.Context, userID uint, currentSessionID string) ([]model.SessionResponse, error) {
+	if s.sessionService == nil {
+		return nil, apperrors.NewSessionServiceUnavailableError()
+	}
+
+	sessions, err := s.sessionService.ListSessions(ctx, userID)
+	if err != nil {
+		logger.FromContext(ctx).Error("获取会话列表失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "list_sessions"))
+		return nil, apperrors.NewSessionListFailedError()
+	}
+
+	responses := make([]model.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = model.SessionResponse{
+			SessionID:    session.SessionID,
+			DeviceInfo:   session.DeviceInfo,
+			IPAddress:    session.IPAddress,
+			UserAgent:    session.UserAgent,
+			LoginTime:    session.LoginTime,
+			LastActivity: session.LastActivity,
+			Current:      session.SessionID == currentSessionID,
+		}
+	}
+
+	return responses, nil
+}
+
+// RevokeSession 吊销用户的指定会话（远程登出）
+func (s *UserService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if s.sessionService == nil {
+		return apperrors.NewSessionServiceUnavailableError()
+	}
+
+	if _, err := s.sessionService.GetSession(ctx, userID, sessionID); err != nil {
+		logger.FromContext(ctx).Warn("吊销会话失败：会话不存在",
+			zap.Uint("user_id", userID),
+			zap.String("session_id", sessionID),
+			zap.String("operation", "revoke_session"))
+		return apperrors.NewSessionNotFoundError()
+	}
+
+	if err := s.sessionService.RevokeSession(ctx, userID, sessionID); err != nil {
+		logger.FromContext(ctx).Error("吊销会话失败",
+			zap.Uint("user_id", userID),
+			zap.String("session_id", sessionID),
+			zap.Error(err),
+			zap.String("operation", "revoke_session"))
+		return apperrors.NewSessionRevokeFailedError()
+	}
+
+	logger.FromContext(ctx).Info("会话已吊销",
+		zap.Uint("user_id", userID),
+		zap.String("session_id", sessionID),
+		zap.String("operation", "revoke_session"))
+	return nil
+}
+
+// RevokeAllSessions 吊销用户的所有会话，exceptSessionID 不为空时保留该会话
+// 返回被吊销的会话数量
+func (s *UserService) RevokeAllSessions(ctx context.Context, userID uint, exceptSessionID string) (int, error) {
+	if s.sessionService == nil {
+		return 0, apperrors.NewSessionServiceUnavailableError()
+	}
+
+	revoked, err := s.sessionService.RevokeAllSessions(ctx, userID, exceptSessionID)
+	if err != nil {
+		logger.FromContext(ctx).Error("批量吊销会话失败",
+			zap.Uint("user_id", userID),
+			zap.Int("revoked", revoked),
+			zap.Error(err),
+			zap.String("operation", "revoke_all_sessions"))
+		return revoked, apperrors.NewSessionRevokeFailedError()
+	}
+
+	logger.FromContext(ctx).Info("批量吊销会话成功",
+		zap.Uint("user_id", userID),
+		zap.String("kept_session_id", exceptSessionID),
+		zap.Int("revoked", revoked),
+		zap.String("operation", "revoke_all_sessions"))
+	return revoked, nil
+}
+
 func (s *UserService) GetByID(id uint) (*model.User, error) {
 	logger.Debug("查询用户信息", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "get_user"))
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(context.Background(), id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Warn("用户不存在", 
@@ -511,20 +957,20 @@ func (s *UserService) GetByID(id uint) (*model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User, error) {
-	logger.Info("开始更新用户信息", 
+func (s *UserService) Update(ctx context.Context, id uint, req *model.UpdateUserRequest) (*model.User, error) {
+	logger.FromContext(ctx).Info("开始更新用户信息", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "update_user"))
 
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("更新失败：用户不存在", 
+			logger.FromContext(ctx).Warn("更新失败：用户不存在", 
 				zap.Uint("user_id", id),
 				zap.String("operation", "update_user"))
 			return nil, apperrors.NewNotFoundError("用户不存在")
 		}
-		logger.Error("查询用户失败", 
+		logger.FromContext(ctx).Error("查询用户失败", 
 			zap.Uint("user_id", id),
 			zap.Error(err),
 			zap.String("operation", "update_user"))
@@ -534,7 +980,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	// 记录更新的字段
 	updatedFields := []string{}
 	if req.Username != "" {
-		logger.Debug("更新用户名", 
+		logger.FromContext(ctx).Debug("更新用户名", 
 			zap.Uint("user_id", id),
 			zap.String("old_username", user.Username),
 			zap.String("new_username", req.Username))
@@ -542,7 +988,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "username")
 	}
 	if req.Email != "" {
-		logger.Debug("更新邮箱", 
+		logger.FromContext(ctx).Debug("更新邮箱", 
 			zap.Uint("user_id", id),
 			zap.String("old_email", user.Email),
 			zap.String("new_email", req.Email))
@@ -551,7 +997,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	}
 	roleChanged := false
 	if req.Role != "" && req.Role != user.Role {
-		logger.Debug("更新角色", 
+		logger.FromContext(ctx).Debug("更新角色", 
 			zap.Uint("user_id", id),
 			zap.String("old_role", user.Role),
 			zap.String("new_role", req.Role))
@@ -559,18 +1005,20 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		updatedFields = append(updatedFields, "role")
 		roleChanged = true
 	}
-	if req.Status != "" {
-		logger.Debug("更新状态", 
+	statusChanged := false
+	if req.Status != "" && req.Status != user.Status {
+		logger.FromContext(ctx).Debug("更新状态", 
 			zap.Uint("user_id", id),
 			zap.String("old_status", user.Status),
 			zap.String("new_status", req.Status))
 		user.Status = req.Status
 		updatedFields = append(updatedFields, "status")
+		statusChanged = true
 	}
 
 	err = s.userRepo.Update(user)
 	if err != nil {
-		logger.Error("用户更新失败", 
+		logger.FromContext(ctx).Error("用户更新失败", 
 			zap.Uint("user_id", id),
 			zap.Strings("updated_fields", updatedFields),
 			zap.Error(err),
@@ -581,7 +1029,7 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	// 如果角色发生变化，同步到 user_roles 表
 	if roleChanged {
 		if err := s.syncUserRole(user.ID, user.Role); err != nil {
-			logger.Error("同步用户角色失败", 
+			logger.FromContext(ctx).Error("同步用户角色失败", 
 				zap.Uint("user_id", user.ID),
 				zap.String("role", user.Role),
 				zap.Error(err))
@@ -589,7 +1037,14 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 		}
 	}
 
-	logger.Info("用户更新成功", 
+	// 账户被禁用后立即吊销所有会话
+	if statusChanged && checkUserStatus(user, time.Now()) != nil {
+		if err := s.revokeUserSessions(ctx, user.ID, "update_user"); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.FromContext(ctx).Info("用户更新成功", 
 		zap.Uint("user_id", id),
 		zap.String("username", user.Username),
 		zap.Strings("updated_fields", updatedFields),
@@ -598,21 +1053,190 @@ func (s *UserService) Update(id uint, req *model.UpdateUserRequest) (*model.User
 	return user, nil
 }
 
-func (s *UserService) Delete(id uint) error {
-	logger.Info("开始删除用户", 
+// UpdateStatus 更新账户状态、过期时间和下次登录必须修改密码标记
+// 状态不再允许登录时立即吊销该用户的所有会话
+func (s *UserService) UpdateStatus(ctx context.Context, id uint, req *model.UpdateUserStatusRequest) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.FromContext(ctx).Warn("更新状态失败：用户不存在",
+				zap.Uint("user_id", id),
+				zap.String("operation", "update_user_status"))
+			return nil, apperrors.NewNotFoundError("用户不存在")
+		}
+		logger.FromContext(ctx).Error("查询用户失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "update_user_status"))
+		return nil, apperrors.NewUserQueryFailedError()
+	}
+
+	oldStatus := user.Status
+	user.Status = req.Status
+	user.ExpiresAt = req.ExpiresAt
+	user.MustChangePassword = req.MustChangePassword
+
+	if err := s.userRepo.Update(user); err != nil {
+		logger.FromContext(ctx).Error("更新用户状态失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "update_user_status"))
+		return nil, apperrors.NewUserUpdateFailedError()
+	}
+
+	if checkUserStatus(user, time.Now()) != nil {
+		if err := s.revokeUserSessions(ctx, user.ID, "update_user_status"); err != nil {
+			return nil, err
+		}
+	} else if err := s.sessionService.SetPasswordChangeRequired(ctx, user.ID, user.MustChangePassword); err != nil {
+		// 标记只在登录时同步，这里失败时用户下次登录仍会被要求修改密码
+		logger.FromContext(ctx).Error("同步修改密码标记失败",
+			zap.Uint("user_id", user.ID),
+			zap.Error(err),
+			zap.String("operation", "update_user_status"))
+	}
+
+	logger.FromContext(ctx).Info("用户状态更新成功",
+		zap.Uint("user_id", id),
+		zap.String("old_status", oldStatus),
+		zap.String("new_status", user.Status),
+		zap.Any("expires_at", user.ExpiresAt),
+		zap.Bool("must_change_password", user.MustChangePassword),
+		zap.String("operation", "update_user_status"))
+
+	return user, nil
+}
+
+// ChangePassword 当前用户修改密码
+// 新密码需符合密码策略且不能重复使用最近的旧密码，成功后清除必须修改密码标记并吊销当前会话以外的所有会话
+func (s *UserService) ChangePassword(ctx context.Context, userID uint, currentSessionID string, req *model.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("用户不存在")
+		}
+		logger.FromContext(ctx).Error("查询用户失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+		return apperrors.NewUserQueryFailedError()
+	}
+
+	if !utils.CheckPassword(req.OldPassword, user.Password) {
+		logger.FromContext(ctx).Warn("修改密码失败：原密码错误",
+			zap.Uint("user_id", userID),
+			zap.String("operation", "change_password"))
+		return apperrors.NewValidationError("原密码错误")
+	}
+
+	if err := s.passwordPolicy.ValidateStrength(req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+	if err := s.passwordPolicy.CheckReuse(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		logger.FromContext(ctx).Error("密码加密失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+		return apperrors.NewPasswordHashFailedError()
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, hashedPassword); err != nil {
+		logger.FromContext(ctx).Error("更新密码失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+		return apperrors.NewUserUpdateFailedError()
+	}
+
+	// 以下步骤失败不影响密码已修改成功，只记录日志
+	if err := s.passwordPolicy.Record(ctx, userID, user.Password); err != nil {
+		logger.FromContext(ctx).Error("记录密码历史失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+	}
+
+	if err := s.sessionService.SetPasswordChangeRequired(ctx, userID, false); err != nil {
+		logger.FromContext(ctx).Error("清除修改密码标记失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+	}
+
+	revoked, err := s.sessionService.RevokeAllSessions(ctx, userID, currentSessionID)
+	if err != nil {
+		logger.FromContext(ctx).Error("吊销其他会话失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "change_password"))
+	}
+
+	logger.FromContext(ctx).Info("用户修改密码成功",
+		zap.Uint("user_id", userID),
+		zap.Int("revoked_sessions", revoked),
+		zap.String("operation", "change_password"))
+
+	return nil
+}
+
+// revokeUserSessions 吊销用户的所有会话
+// 账户的修改此时已保存，失败时返回错误让调用方报告，避免停用的账户仍保留有效会话却显示操作成功
+func (s *UserService) revokeUserSessions(ctx context.Context, userID uint, operation string) error {
+	revoked, err := s.sessionService.RevokeAllSessions(ctx, userID, "")
+	if err != nil {
+		logger.FromContext(ctx).Error("吊销用户会话失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", operation))
+		return apperrors.NewUserSessionsNotRevokedError()
+	}
+	logger.FromContext(ctx).Info("已吊销用户的所有会话",
+		zap.Uint("user_id", userID),
+		zap.Int("revoked", revoked),
+		zap.String("operation", operation))
+	return nil
+}
+
+// checkUserStatus 检查账户状态是否允许登录和续期
+func checkUserStatus(user *model.User, now time.Time) error {
+	switch user.Status {
+	case model.UserStatusActive:
+	case model.UserStatusSuspended:
+		return apperrors.NewUserSuspendedError()
+	case model.UserStatusPending:
+		return apperrors.NewUserPendingError()
+	default:
+		return apperrors.NewUserDisabledError()
+	}
+	if user.IsExpired(now) {
+		return apperrors.NewUserExpiredError()
+	}
+	return nil
+}
+
+// Delete 删除用户（移入回收站）并吊销其所有会话
+// 用户角色关联和 Casbin 用户-角色关系保留，恢复用户时无需重新分配角色；
+// 删除期间用户无法登录、会话已吊销，这些关系不会授予任何访问，彻底删除时才一并移除
+func (s *UserService) Delete(ctx context.Context, id uint) error {
+	logger.FromContext(ctx).Info("开始删除用户", 
 		zap.Uint("user_id", id),
 		zap.String("operation", "delete_user"))
 
 	// 先查询用户信息用于日志记录
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			logger.Warn("删除失败：用户不存在", 
+			logger.FromContext(ctx).Warn("删除失败：用户不存在", 
 				zap.Uint("user_id", id),
 				zap.String("operation", "delete_user"))
 			return apperrors.NewNotFoundError("用户不存在")
 		}
-		logger.Error("查询用户失败", 
+		logger.FromContext(ctx).Error("查询用户失败", 
 			zap.Uint("user_id", id),
 			zap.Error(err),
 			zap.String("operation", "delete_user"))
@@ -621,7 +1245,7 @@ func (s *UserService) Delete(id uint) error {
 
 	err = s.userRepo.Delete(id)
 	if err != nil {
-		logger.Error("用户删除失败", 
+		logger.FromContext(ctx).Error("用户删除失败", 
 			zap.Uint("user_id", id),
 			zap.String("username", user.Username),
 			zap.Error(err),
@@ -629,7 +1253,11 @@ func (s *UserService) Delete(id uint) error {
 		return apperrors.NewUserDeleteFailedError()
 	}
 
-	logger.Info("用户删除成功", 
+	if err := s.revokeUserSessions(ctx, id, "delete_user"); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("用户删除成功", 
 		zap.Uint("user_id", id),
 		zap.String("username", user.Username),
 		zap.String("operation", "delete_user"))
@@ -664,38 +1292,61 @@ func (s *UserService) List(page, pageSize int) ([]model.User, int64, error) {
 	return users, total, nil
 }
 
-// ListWithVisibility 根据用户角色和可见性规则获取用户列表
-func (s *UserService) ListWithVisibility(currentUserID uint, currentUserRole string, page, pageSize int) ([]model.User, int64, error) {
-	logger.Debug("查询用户列表（带可见性控制）", 
+// ListWithVisibility 根据用户角色和可见性规则按条件查询用户列表，并标记因连续登录失败被锁定的账户
+func (s *UserService) ListWithVisibility(ctx context.Context, currentUserID uint, currentUserRole string, req *model.UserListRequest) ([]model.UserResponse, int64, error) {
+	logger.FromContext(ctx).Debug("查询用户列表（带可见性控制）", 
 		zap.Uint("current_user_id", currentUserID),
 		zap.String("current_user_role", currentUserRole),
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
+		zap.String("keyword", req.Keyword),
+		zap.String("status", req.Status),
+		zap.String("role", req.Role),
+		zap.String("sort_by", req.SortBy),
 		zap.String("operation", "list_users_with_visibility"))
 
-	offset := (page - 1) * pageSize
-	users, total, err := s.userRepo.ListByVisibility(currentUserID, currentUserRole, offset, pageSize)
+	users, total, err := s.userRepo.ListByVisibility(currentUserID, currentUserRole, req)
 	if err != nil {
-		logger.Error("查询用户列表失败", 
+		logger.FromContext(ctx).Error("查询用户列表失败", 
 			zap.Uint("current_user_id", currentUserID),
 			zap.String("current_user_role", currentUserRole),
-			zap.Int("page", page),
-			zap.Int("page_size", pageSize),
+			zap.Int("page", req.Page),
+			zap.Int("page_size", req.PageSize),
 			zap.Error(err),
 			zap.String("operation", "list_users_with_visibility"))
 		return nil, 0, apperrors.NewInternalError("查询用户列表失败")
 	}
 
-	logger.Debug("用户列表查询成功", 
+	logger.FromContext(ctx).Debug("用户列表查询成功", 
 		zap.Uint("current_user_id", currentUserID),
 		zap.String("current_user_role", currentUserRole),
-		zap.Int("page", page),
-		zap.Int("page_size", pageSize),
+		zap.Int("page", req.Page),
+		zap.Int("page_size", req.PageSize),
 		zap.Int64("total", total),
 		zap.Int("returned_count", len(users)),
 		zap.String("operation", "list_users_with_visibility"))
 
-	return users, total, nil
+	responses := make([]model.UserResponse, len(users))
+	usernames := make([]string, len(users))
+	for i := range users {
+		responses[i] = users[i].ToResponse()
+		usernames[i] = users[i].Username
+	}
+
+	if s.loginRateLimitService != nil {
+		locked, err := s.loginRateLimitService.LockedUsernames(ctx, usernames)
+		if err != nil {
+			// 锁定状态只用于展示，查询失败不影响列表
+			logger.FromContext(ctx).Warn("查询用户锁定状态失败",
+				zap.Error(err),
+				zap.String("operation", "list_users_with_visibility"))
+		}
+		for i := range responses {
+			responses[i].Locked = locked[responses[i].Username]
+		}
+	}
+
+	return responses, total, nil
 }
 
 // CheckUsernameAvailable 检查用户名是否可用
@@ -869,7 +1520,7 @@ func (s *UserService) GetUserPermissions(userID uint) (*model.UserPermissionsRes
 	// 遍历每个角色，获取其权限
 	for _, role := range roles {
 		// 获取角色的权限ID列表
-		permissionIDs, err := s.roleRepo.GetRolePermissionIDs(role.ID)
+		permissionIDs, err := s.roleRepo.GetRolePermissionIDs(context.Background(), role.ID)
 		if err != nil {
 			logger.Error("获取角色权限ID失败", 
 				zap.Uint("role_id", role.ID),
@@ -878,7 +1529,7 @@ func (s *UserService) GetUserPermissions(userID uint) (*model.UserPermissionsRes
 		}
 
 		// 获取权限详情
-		permissions, err := s.permissionService.permissionRepo.GetByIDs(permissionIDs)
+		permissions, err := s.permissionService.permissionRepo.GetByIDs(context.Background(), permissionIDs)
 		if err != nil {
 			logger.Error("获取权限详情失败", 
 				zap.Uints("permission_ids", permissionIDs),
@@ -929,7 +1580,7 @@ func (s *UserService) syncUserRole(userID uint, roleCode string) error {
 	}
 
 	// 移除用户的所有现有角色
-	if err := s.roleRepo.RemoveAllRolesFromUser(userID); err != nil {
+	if err := s.roleRepo.RemoveAllRolesFromUser(context.Background(), userID); err != nil {
 		logger.Error("移除用户角色失败", 
 			zap.Uint("user_id", userID),
 			zap.Error(err))
@@ -937,7 +1588,7 @@ func (s *UserService) syncUserRole(userID uint, roleCode string) error {
 	}
 
 	// 分配新角色到 user_roles 表
-	if err := s.roleRepo.AssignRoleToUser(userID, role.ID, 0); err != nil {
+	if err := s.roleRepo.AssignRoleToUser(context.Background(), userID, role.ID, 0); err != nil {
 		logger.Error("分配角色失败", 
 			zap.Uint("user_id", userID),
 			zap.Uint("role_id", role.ID),
@@ -945,6 +1596,16 @@ func (s *UserService) syncUserRole(userID uint, roleCode string) error {
 		return apperrors.NewUserRoleAssignFailedError()
 	}
 
+	// 同步 Casbin 用户-角色关系，供接口鉴权使用
+	if s.casbinService != nil {
+		if err := s.casbinService.RemoveAllRolesForUser(userID); err != nil {
+			return apperrors.NewUserCasbinRoleRemoveFailedError()
+		}
+		if err := s.casbinService.AddRoleForUser(userID, role.Code); err != nil {
+			return apperrors.NewUserCasbinRoleAddFailedError()
+		}
+	}
+
 	logger.Info("同步用户角色成功", 
 		zap.Uint("user_id", userID),
 		zap.String("role_code", roleCode),
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// userBatchActions 批量操作在审计日志中的操作描述
+var userBatchActions = map[string]string{
+	model.UserBatchOpStatus:         "批量修改账户状态",
+	model.UserBatchOpDelete:         "批量删除用户",
+	model.UserBatchOpAssignRole:     "批量设置角色",
+	model.UserBatchOpRemoveRole:     "批量移除角色",
+	model.UserBatchOpRevokeSessions: "批量强制下线",
+}
+
+// BatchOperate 对多个用户执行同一操作，每个用户独立执行并返回各自的结果
+// 不能对当前用户执行批量操作；每个操作成功的用户写入一条审计日志
+func (s *UserService) BatchOperate(ctx context.Context, req *model.UserBatchRequest, operatorID uint, info AuditLogRequestInfo) (*model.UserBatchResponse, error) {
+	if err := s.validateBatchRequest(req); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("开始批量操作用户",
+		zap.String("batch_operation", req.Operation),
+		zap.Int("count", len(req.IDs)),
+		zap.String("status", req.Status),
+		zap.String("role", req.Role),
+		zap.Uint("operator_id", operatorID),
+		zap.String("operation", "batch_users"))
+
+	response := &model.UserBatchResponse{
+		Operation: req.Operation,
+		Results:   make([]model.UserBatchResult, 0, len(req.IDs)),
+	}
+	var affected []string
+	seen := make(map[uint]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := model.UserBatchResult{ID: id, Success: true}
+		if err := s.batchOperateUser(ctx, req, id, operatorID); err != nil {
+			result.Success = false
+			if appErr, ok := apperrors.GetAppError(err); ok {
+				result.Error = appErr.Message
+				// 账户已修改、只是吊销会话失败时，修改本身仍要记入审计日志
+				if appErr.BusinessCode == apperrors.CodeUserSessionsNotRevoked {
+					affected = append(affected, strconv.FormatUint(uint64(id), 10))
+				}
+			} else {
+				result.Error = err.Error()
+			}
+			response.Failed++
+		} else {
+			affected = append(affected, strconv.FormatUint(uint64(id), 10))
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Total = len(response.Results)
+
+	if s.auditLogService != nil {
+		detail, _ := json.Marshal(map[string]string{
+			"operation": req.Operation,
+			"status":    req.Status,
+			"role":      req.Role,
+		})
+		s.auditLogService.RecordResourceActions(info, userBatchActions[req.Operation], "users", affected, string(detail))
+	}
+
+	logger.FromContext(ctx).Info("批量操作用户完成",
+		zap.String("batch_operation", req.Operation),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed),
+		zap.Uint("operator_id", operatorID),
+		zap.String("operation", "batch_users"))
+
+	return response, nil
+}
+
+// validateBatchRequest 校验操作所需的参数，角色操作要求角色存在
+func (s *UserService) validateBatchRequest(req *model.UserBatchRequest) error {
+	switch req.Operation {
+	case model.UserBatchOpStatus:
+		if req.Status == "" {
+			return apperrors.NewValidationError("修改账户状态时必须指定 status")
+		}
+	case model.UserBatchOpAssignRole, model.UserBatchOpRemoveRole:
+		if req.Role == "" {
+			return apperrors.NewValidationError("角色操作必须指定 role")
+		}
+		if req.Operation == model.UserBatchOpRemoveRole && req.Role == defaultUserRole {
+			return apperrors.NewValidationError("不能移除默认角色")
+		}
+		if _, err := s.roleRepo.GetByCode(req.Role); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperrors.NewRoleNotFoundError()
+			}
+			logger.Error("查找角色失败",
+				zap.String("role_code", req.Role),
+				zap.Error(err),
+				zap.String("operation", "batch_users"))
+			return apperrors.NewRoleFindFailedError()
+		}
+	}
+	return nil
+}
+
+// batchOperateUser 对单个用户执行批量操作
+func (s *UserService) batchOperateUser(ctx context.Context, req *model.UserBatchRequest, id, operatorID uint) error {
+	if id == operatorID {
+		return apperrors.NewValidationError("不能对当前用户执行批量操作")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewNotFoundError("用户不存在")
+		}
+		logger.FromContext(ctx).Error("查询用户失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "batch_users"))
+		return apperrors.NewUserQueryFailedError()
+	}
+
+	switch req.Operation {
+	case model.UserBatchOpStatus:
+		if user.Status == req.Status {
+			return nil
+		}
+		user.Status = req.Status
+		if err := s.userRepo.Update(user); err != nil {
+			logger.FromContext(ctx).Error("批量修改账户状态失败",
+				zap.Uint("user_id", id),
+				zap.Error(err),
+				zap.String("operation", "batch_users"))
+			return apperrors.NewUserUpdateFailedError()
+		}
+		// 账户被禁用后立即吊销所有会话
+		if checkUserStatus(user, time.Now()) != nil {
+			return s.revokeUserSessions(ctx, id, "batch_users")
+		}
+
+	case model.UserBatchOpDelete:
+		if err := s.userRepo.Delete(id); err != nil {
+			logger.FromContext(ctx).Error("批量删除用户失败",
+				zap.Uint("user_id", id),
+				zap.Error(err),
+				zap.String("operation", "batch_users"))
+			return apperrors.NewUserDeleteFailedError()
+		}
+		return s.revokeUserSessions(ctx, id, "batch_users")
+
+	case model.UserBatchOpAssignRole:
+		if user.Role == req.Role {
+			return nil
+		}
+		return s.updateUserRole(user, req.Role)
+
+	case model.UserBatchOpRemoveRole:
+		if user.Role != req.Role {
+			return apperrors.NewValidationError("用户不是该角色")
+		}
+		return s.updateUserRole(user, defaultUserRole)
+
+	case model.UserBatchOpRevokeSessions:
+		if _, err := s.sessionService.RevokeAllSessions(ctx, id, ""); err != nil {
+			logger.FromContext(ctx).Error("批量强制下线失败",
+				zap.Uint("user_id", id),
+				zap.Error(err),
+				zap.String("operation", "batch_users"))
+			return apperrors.NewSessionRevokeFailedError()
+		}
+	}
+	return nil
+}
+
+// updateUserRole 更新用户角色并同步到 user_roles 表和 Casbin
+func (s *UserService) updateUserRole(user *model.User, roleCode string) error {
+	user.Role = roleCode
+	if err := s.userRepo.Update(user); err != nil {
+		logger.Error("更新用户角色失败",
+			zap.Uint("user_id", user.ID),
+			zap.String("role", roleCode),
+			zap.Error(err),
+			zap.String("operation", "batch_users"))
+		return apperrors.NewUserUpdateFailedError()
+	}
+	return s.syncUserRole(user.ID, roleCode)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeUserRepo 内存中的用户仓库，仅实现批量操作所需的方法
+type fakeUserRepo struct {
+	UserRepositoryInterface
+	users map[uint]*model.User
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uint) (*model.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) Update(user *model.User) error {
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+// fakeRoleRepo 内存中的角色仓库，仅实现角色同步所需的方法
+type fakeRoleRepo struct {
+	RoleRepositoryInterface
+	roles     map[string]*model.Role
+	userRoles map[uint][]uint
+}
+
+func (r *fakeRoleRepo) GetByCode(code string) (*model.Role, error) {
+	role, ok := r.roles[code]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func (r *fakeRoleRepo) RemoveAllRolesFromUser(ctx context.Context, userID uint) error {
+	delete(r.userRoles, userID)
+	return nil
+}
+
+func (r *fakeRoleRepo) AssignRoleToUser(ctx context.Context, userID, roleID uint, assignedBy uint) error {
+	r.userRoles[userID] = append(r.userRoles[userID], roleID)
+	return nil
+}
+
+// fakeSessionService 记录被吊销会话的用户，revokeErr 不为空时模拟吊销失败
+type fakeSessionService struct {
+	SessionServiceInterface
+	revoked   []uint
+	revokeErr error
+}
+
+func (s *fakeSessionService) RevokeAllSessions(ctx context.Context, userID uint, exceptSessionID string) (int, error) {
+	if s.revokeErr != nil {
+		return 0, s.revokeErr
+	}
+	s.revoked = append(s.revoked, userID)
+	return 1, nil
+}
+
+// fakeAuditWriter 记录经写入器提交的审计日志
+type fakeAuditWriter struct {
+	logs []*model.AuditLog
+}
+
+func (w *fakeAuditWriter) Write(auditLog *model.AuditLog) {
+	w.logs = append(w.logs, auditLog)
+}
+
+func newBatchTestService() (*UserService, *fakeUserRepo, *fakeRoleRepo, *fakeSessionService, *fakeAuditWriter) {
+	userRepo := &fakeUserRepo{users: map[uint]*model.User{
+		1: {ID: 1, Username: "admin", Role: "admin", Status: model.UserStatusActive},
+		2: {ID: 2, Username: "alice", Role: "user", Status: model.UserStatusActive},
+		3: {ID: 3, Username: "bob", Role: "manager", Status: model.UserStatusActive},
+	}}
+	roleRepo := &fakeRoleRepo{
+		roles: map[string]*model.Role{
+			"admin":   {ID: 1, Code: "admin"},
+			"user":    {ID: 2, Code: "user"},
+			"manager": {ID: 3, Code: "manager"},
+		},
+		userRoles: map[uint][]uint{},
+	}
+	sessions := &fakeSessionService{}
+	auditWriter := &fakeAuditWriter{}
+
+	service := &UserService{
+		userRepo:        userRepo,
+		roleRepo:        roleRepo,
+		sessionService:  sessions,
+		auditLogService: NewAuditLogService(&fakeAuditLogRepo{}, auditWriter, nil),
+	}
+	return service, userRepo, roleRepo, sessions, auditWriter
+}
+
+func TestUserServiceBatchOperate(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("disable reports per id and revokes sessions", func(t *testing.T) {
+		service, userRepo, _, sessions, auditWriter := newBatchTestService()
+
+		response, err := service.BatchOperate(ctx, &model.UserBatchRequest{
+			Operation: model.UserBatchOpStatus,
+			IDs:       []uint{2, 1, 99, 2},
+			Status:    model.UserStatusInactive,
+		}, 1, AuditLogRequestInfo{UserID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, response.Total)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, 2, response.Failed)
+		assert.True(t, response.Results[0].Success)
+		assert.False(t, response.Results[1].Success)
+		assert.Equal(t, "用户不存在", response.Results[2].Error)
+
+		assert.Equal(t, model.UserStatusInactive, userRepo.users[2].Status)
+		assert.Equal(t, model.UserStatusActive, userRepo.users[1].Status)
+		assert.Equal(t, []uint{2}, sessions.revoked)
+
+		require.Len(t, auditWriter.logs, 1)
+		assert.Equal(t, "2", auditWriter.logs[0].ResourceID)
+		assert.Equal(t, "批量修改账户状态", auditWriter.logs[0].Action)
+	})
+
+	t.Run("failed session revoke reported but change still audited", func(t *testing.T) {
+		service, userRepo, _, sessions, auditWriter := newBatchTestService()
+		sessions.revokeErr = errors.New("connection refused")
+
+		response, err := service.BatchOperate(ctx, &model.UserBatchRequest{
+			Operation: model.UserBatchOpStatus,
+			IDs:       []uint{2},
+			Status:    model.UserStatusInactive,
+		}, 1, AuditLogRequestInfo{UserID: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, response.Failed)
+		assert.Equal(t, apperrors.GetBusinessCodeMessage(apperrors.CodeUserSessionsNotRevoked), response.Results[0].Error)
+		assert.Equal(t, model.UserStatusInactive, userRepo.users[2].Status)
+		require.Len(t, auditWriter.logs, 1)
+		assert.Equal(t, "2", auditWriter.logs[0].ResourceID)
+	})
+
+	t.Run("assign and remove role sync user_roles", func(t *testing.T) {
+		service, userRepo, roleRepo, _, auditWriter := newBatchTestService()
+
+		response, err := service.BatchOperate(ctx, &model.UserBatchRequest{
+			Operation: model.UserBatchOpAssignRole,
+			IDs:       []uint{2},
+			Role:      "manager",
+		}, 1, AuditLogRequestInfo{UserID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Succeeded)
+		assert.Equal(t, "manager", userRepo.users[2].Role)
+		assert.Equal(t, []uint{3}, roleRepo.userRoles[2])
+
+		response, err = service.BatchOperate(ctx, &model.UserBatchRequest{
+			Operation: model.UserBatchOpRemoveRole,
+			IDs:       []uint{2, 3},
+			Role:      "manager",
+		}, 1, AuditLogRequestInfo{UserID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, response.Succeeded)
+		assert.Equal(t, "user", userRepo.users[3].Role)
+		assert.Equal(t, []uint{2}, roleRepo.userRoles[3])
+		assert.Len(t, auditWriter.logs, 3)
+	})
+
+	t.Run("invalid requests rejected before any change", func(t *testing.T) {
+		service, _, _, _, auditWriter := newBatchTestService()
+
+		requests := []*model.UserBatchRequest{
+			{Operation: model.UserBatchOpStatus, IDs: []uint{2}},
+			{Operation: model.UserBatchOpAssignRole, IDs: []uint{2}},
+			{Operation: model.UserBatchOpRemoveRole, IDs: []uint{2}, Role: "user"},
+		}
+		for _, req := range requests {
+			_, err := service.BatchOperate(ctx, req, 1, AuditLogRequestInfo{UserID: 1})
+			var appErr *apperrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperrors.ErrorTypeValidation, appErr.Type)
+		}
+
+		_, err := service.BatchOperate(ctx, &model.UserBatchRequest{
+			Operation: model.UserBatchOpAssignRole,
+			IDs:       []uint{2},
+			Role:      "missing",
+		}, 1, AuditLogRequestInfo{UserID: 1})
+		var appErr *apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeRoleNotFound, appErr.BusinessCode)
+		assert.Empty(t, auditWriter.logs)
+	})
+}
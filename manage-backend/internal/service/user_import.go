@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/utils"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultUserRole 未指定角色时分配的默认角色
+const defaultUserRole = "user"
+
+// importValidator 按 CreateUserRequest 的 binding 标签校验导入行，与 POST /users 的参数校验规则一致
+var importValidator = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	return v
+}()
+
+// importFieldLabels 导入校验错误中使用的字段名称
+var importFieldLabels = map[string]string{
+	"Username": "用户名",
+	"Email":    "邮箱",
+	"Password": "密码",
+}
+
+// UserExportHeader 用户导出文件的表头
+var UserExportHeader = []string{
+	"id", "username", "email", "role", "roles", "status",
+	"expires_at", "must_change_password", "created_by", "created_at", "updated_at",
+}
+
+// importRow 导入文件中的一行数据
+type importRow struct {
+	line               int
+	req                model.CreateUserRequest
+	mustChangePassword bool
+	role               *model.Role
+	messages           []string
+}
+
+// ImportUsers 批量导入用户
+// 每行按与 RegisterWithCreator 相同的规则校验（参数格式、用户名和邮箱唯一、角色存在、密码策略），
+// 同时检查文件内的重复行；dryRun 为 true 或存在任一错误行时只返回校验报告，不导入任何用户
+func (s *UserService) ImportUsers(ctx context.Context, table [][]string, dryRun bool, creatorID uint) (*model.UserImportReport, error) {
+	rows, err := parseImportTable(table)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("开始批量导入用户",
+		zap.Int("rows", len(rows)),
+		zap.Bool("dry_run", dryRun),
+		zap.Uint("creator_id", creatorID),
+		zap.String("operation", "import_users"))
+
+	if err := s.validateImportRows(rows); err != nil {
+		return nil, err
+	}
+
+	report := &model.UserImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []model.UserImportRowError{},
+	}
+	for _, row := range rows {
+		if len(row.messages) == 0 {
+			report.Valid++
+			continue
+		}
+		report.Invalid++
+		report.Errors = append(report.Errors, model.UserImportRowError{
+			Row:      row.line,
+			Username: row.req.Username,
+			Email:    row.req.Email,
+			Messages: row.messages,
+		})
+	}
+
+	if dryRun || report.Invalid > 0 {
+		logger.FromContext(ctx).Info("批量导入用户校验完成",
+			zap.Int("total", report.Total),
+			zap.Int("invalid", report.Invalid),
+			zap.Bool("dry_run", dryRun),
+			zap.String("operation", "import_users"))
+		return report, nil
+	}
+
+	passwords := make([]string, len(rows))
+	for i, row := range rows {
+		passwords[i] = row.req.Password
+	}
+	hashes, err := hashPasswords(passwords)
+	if err != nil {
+		logger.FromContext(ctx).Error("批量导入用户失败：密码加密失败",
+			zap.Error(err),
+			zap.String("operation", "import_users"))
+		return nil, apperrors.NewPasswordHashFailedError()
+	}
+
+	users := make([]model.User, len(rows))
+	roleIDs := make([]uint, len(rows))
+	for i, row := range rows {
+		users[i] = model.User{
+			Username:           row.req.Username,
+			Email:              row.req.Email,
+			Password:           hashes[i],
+			Role:               row.role.Code,
+			MustChangePassword: row.mustChangePassword,
+			CreatedBy:          &creatorID,
+		}
+		roleIDs[i] = row.role.ID
+	}
+
+	if err := s.userRepo.CreateBatch(users, roleIDs, creatorID); err != nil {
+		logger.FromContext(ctx).Error("批量导入用户失败",
+			zap.Int("count", len(users)),
+			zap.Error(err),
+			zap.String("operation", "import_users"))
+		return nil, apperrors.NewUserImportFailedError()
+	}
+
+	// 同步 Casbin 用户-角色关系，用户已创建成功，失败时只记录日志
+	if s.casbinService != nil {
+		for i := range users {
+			if err := s.casbinService.AddRoleForUser(users[i].ID, users[i].Role); err != nil {
+				logger.FromContext(ctx).Error("同步导入用户的 Casbin 角色失败",
+					zap.Uint("user_id", users[i].ID),
+					zap.String("role", users[i].Role),
+					zap.Error(err))
+			}
+		}
+	}
+
+	report.Imported = len(users)
+	logger.FromContext(ctx).Info("批量导入用户成功",
+		zap.Int("imported", report.Imported),
+		zap.Uint("creator_id", creatorID),
+		zap.String("operation", "import_users"))
+
+	return report, nil
+}
+
+// parseImportTable 按表头解析导入文件，跳过空行，返回带文件行号的数据行
+func parseImportTable(table [][]string) ([]*importRow, error) {
+	if len(table) == 0 {
+		return nil, apperrors.NewUserImportFileInvalidError("导入文件为空")
+	}
+
+	columns := make(map[string]int, len(table[0]))
+	for i, name := range table[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{model.UserImportColumnUsername, model.UserImportColumnEmail, model.UserImportColumnPassword} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperrors.NewUserImportFileInvalidError("导入文件缺少必需的列: " + required)
+		}
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []*importRow
+	for i, record := range table[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if len(rows) == model.UserImportMaxRows {
+			return nil, apperrors.NewUserImportFileInvalidError(fmt.Sprintf("单次最多导入%d个用户", model.UserImportMaxRows))
+		}
+
+		row := &importRow{
+			line: i + 2,
+			req: model.CreateUserRequest{
+				Username: cell(record, model.UserImportColumnUsername),
+				Email:    cell(record, model.UserImportColumnEmail),
+				Password: cell(record, model.UserImportColumnPassword),
+				Role:     cell(record, model.UserImportColumnRole),
+			},
+		}
+		if value := cell(record, model.UserImportColumnMustChangePassword); value != "" {
+			mustChange, err := strconv.ParseBool(value)
+			if err != nil {
+				row.messages = append(row.messages, "must_change_password 只能为 true 或 false")
+			}
+			row.mustChangePassword = mustChange
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, apperrors.NewUserImportFileInvalidError("导入文件没有数据行")
+	}
+	return rows, nil
+}
+
+// validateImportRows 校验每一行并把错误原因记录到行上，只有查询失败时返回错误
+func (s *UserService) validateImportRows(rows []*importRow) error {
+	// 参数格式，与创建用户接口的校验规则一致
+	for _, row := range rows {
+		if err := importValidator.Struct(&row.req); err != nil {
+			row.messages = append(row.messages, importValidationMessages(err)...)
+		}
+	}
+
+	// 文件内重复
+	usernameLines := make(map[string]int, len(rows))
+	emailLines := make(map[string]int, len(rows))
+	usernames := make([]string, 0, len(rows))
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if username := row.req.Username; username != "" {
+			if line, ok := usernameLines[username]; ok {
+				row.messages = append(row.messages, fmt.Sprintf("用户名与第%d行重复", line))
+			} else {
+				usernameLines[username] = row.line
+				usernames = append(usernames, username)
+			}
+		}
+		if email := row.req.Email; email != "" {
+			if line, ok := emailLines[email]; ok {
+				row.messages = append(row.messages, fmt.Sprintf("邮箱与第%d行重复", line))
+			} else {
+				emailLines[email] = row.line
+				emails = append(emails, email)
+			}
+		}
+	}
+
+	// 与已有用户重复
+	existing, err := s.userRepo.ListByUsernamesOrEmails(usernames, emails)
+	if err != nil {
+		logger.Error("批量导入用户失败：查询已有用户失败",
+			zap.Error(err),
+			zap.String("operation", "import_users"))
+		return apperrors.NewUserQueryFailedError()
+	}
+	existingUsernames := make(map[string]bool, len(existing))
+	existingEmails := make(map[string]bool, len(existing))
+	for _, user := range existing {
+		existingUsernames[user.Username] = true
+		existingEmails[user.Email] = true
+	}
+
+	roles := make(map[string]*model.Role)
+	for _, row := range rows {
+		if existingUsernames[row.req.Username] {
+			row.messages = append(row.messages, apperrors.GetBusinessCodeMessage(apperrors.CodeUsernameExists))
+		}
+		if existingEmails[row.req.Email] {
+			row.messages = append(row.messages, apperrors.GetBusinessCodeMessage(apperrors.CodeEmailExists))
+		}
+
+		// 角色
+		if row.req.Role == "" {
+			row.req.Role = defaultUserRole
+		}
+		role, ok := roles[row.req.Role]
+		if !ok {
+			role, err = s.roleRepo.GetByCode(row.req.Role)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Error("批量导入用户失败：查找角色失败",
+					zap.String("role_code", row.req.Role),
+					zap.Error(err),
+					zap.String("operation", "import_users"))
+				return apperrors.NewRoleFindFailedError()
+			}
+			roles[row.req.Role] = role
+		}
+		switch {
+		case role == nil:
+			row.messages = append(row.messages, "角色不存在: "+row.req.Role)
+		case role.Status != "active":
+			row.messages = append(row.messages, "角色已禁用: "+row.req.Role)
+		default:
+			row.role = role
+		}
+
+		// 密码策略
+		if row.req.Password != "" {
+			if err := s.passwordPolicy.ValidateStrength(row.req.Password, row.req.Username, row.req.Email); err != nil {
+				if appErr, ok := apperrors.GetAppError(err); ok {
+					row.messages = append(row.messages, appErr.Message)
+				} else {
+					row.messages = append(row.messages, err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// importValidationMessages 将参数校验错误转换为中文说明
+func importValidationMessages(err error) []string {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		label, ok := importFieldLabels[fe.Field()]
+		if !ok {
+			label = fe.Field()
+		}
+		switch fe.Tag() {
+		case "required":
+			messages = append(messages, label+"不能为空")
+		case "min":
+			messages = append(messages, fmt.Sprintf("%s长度不能少于%s个字符", label, fe.Param()))
+		case "max":
+			messages = append(messages, fmt.Sprintf("%s长度不能超过%s个字符", label, fe.Param()))
+		default:
+			messages = append(messages, label+"格式不正确")
+		}
+	}
+	return messages
+}
+
+// hashPasswords 并发加密密码，bcrypt 较慢，逐个加密上千个密码会明显拖慢导入
+func hashPasswords(passwords []string) ([]string, error) {
+	hashes := make([]string, len(passwords))
+	errs := make([]error, len(passwords))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, password := range passwords {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, password string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			hashes[i], errs[i] = utils.HashPassword(password)
+		}(i, password)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// ExportUsers 按可见性规则和筛选条件导出用户，返回与 UserExportHeader 对应的数据行
+// roles 列为通过 user_roles 分配的全部角色代码，以 | 分隔
+func (s *UserService) ExportUsers(ctx context.Context, currentUserID uint, currentUserRole string, req *model.UserListRequest) ([][]string, error) {
+	users, err := s.userRepo.ListAllByVisibility(currentUserID, currentUserRole, req, model.UserExportMaxRows+1)
+	if err != nil {
+		logger.FromContext(ctx).Error("导出用户失败：查询用户列表失败",
+			zap.Uint("current_user_id", currentUserID),
+			zap.Error(err),
+			zap.String("operation", "export_users"))
+		return nil, apperrors.NewUserExportFailedError()
+	}
+	if len(users) > model.UserExportMaxRows {
+		return nil, apperrors.NewUserExportTooLargeError(fmt.Sprintf("单次最多导出%d个用户，请缩小筛选范围", model.UserExportMaxRows))
+	}
+
+	userIDs := make([]uint, len(users))
+	for i := range users {
+		userIDs[i] = users[i].ID
+	}
+	roleCodes, err := s.roleRepo.GetRoleCodesByUserIDs(userIDs)
+	if err != nil {
+		logger.FromContext(ctx).Error("导出用户失败：查询用户角色失败",
+			zap.Uint("current_user_id", currentUserID),
+			zap.Error(err),
+			zap.String("operation", "export_users"))
+		return nil, apperrors.NewUserExportFailedError()
+	}
+
+	rows := make([][]string, len(users))
+	for i, user := range users {
+		var expiresAt, createdBy string
+		if user.ExpiresAt != nil {
+			expiresAt = user.ExpiresAt.Format(time.RFC3339)
+		}
+		if user.CreatedBy != nil {
+			createdBy = strconv.FormatUint(uint64(*user.CreatedBy), 10)
+		}
+		rows[i] = []string{
+			strconv.FormatUint(uint64(user.ID), 10),
+			user.Username,
+			user.Email,
+			user.Role,
+			strings.Join(roleCodes[user.ID], "|"),
+			user.Status,
+			expiresAt,
+			strconv.FormatBool(user.MustChangePassword),
+			createdBy,
+			user.CreatedAt.Format(time.RFC3339),
+			user.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+
+	logger.FromContext(ctx).Info("导出用户成功",
+		zap.Uint("current_user_id", currentUserID),
+		zap.String("current_user_role", currentUserRole),
+		zap.Int("count", len(rows)),
+		zap.String("operation", "export_users"))
+
+	return rows, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportTable(t *testing.T) {
+	t.Run("header matched case-insensitively and blank rows skipped", func(t *testing.T) {
+		rows, err := parseImportTable([][]string{
+			{"Email", "USERNAME", "password", "must_change_password"},
+			{"alice@example.com", "alice", "Secret123", "true"},
+			{"", "", "", ""},
+			{"bob@example.com", "bob", "Secret456"},
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		assert.Equal(t, 2, rows[0].line)
+		assert.Equal(t, "alice", rows[0].req.Username)
+		assert.Equal(t, "alice@example.com", rows[0].req.Email)
+		assert.True(t, rows[0].mustChangePassword)
+
+		assert.Equal(t, 4, rows[1].line)
+		assert.Equal(t, "bob", rows[1].req.Username)
+		assert.Empty(t, rows[1].req.Role)
+		assert.False(t, rows[1].mustChangePassword)
+	})
+
+	t.Run("invalid must_change_password reported on row", func(t *testing.T) {
+		rows, err := parseImportTable([][]string{
+			{"username", "email", "password", "must_change_password"},
+			{"alice", "alice@example.com", "Secret123", "maybe"},
+		})
+		require.NoError(t, err)
+		assert.Len(t, rows[0].messages, 1)
+	})
+
+	tooMany := [][]string{{"username", "email", "password"}}
+	for i := 0; i <= model.UserImportMaxRows; i++ {
+		tooMany = append(tooMany, []string{"user", "user@example.com", "Secret123"})
+	}
+
+	invalid := map[string][][]string{
+		"empty file":     {},
+		"missing column": {{"username", "email"}, {"alice", "alice@example.com"}},
+		"header only":    {{"username", "email", "password"}},
+		"too many rows":  tooMany,
+	}
+	for name, table := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseImportTable(table)
+			var appErr *apperrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperrors.CodeUserImportFileInvalid, appErr.BusinessCode)
+		})
+	}
+}
+
+func TestImportValidationMessages(t *testing.T) {
+	req := model.CreateUserRequest{Username: "ab", Email: "not-an-email"}
+
+	err := importValidator.Struct(&req)
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"用户名长度不能少于3个字符",
+		"邮箱格式不正确",
+		"密码不能为空",
+	}, importValidationMessages(err))
+
+	valid := model.CreateUserRequest{Username: "alice", Email: "alice@example.com", Password: "Secret123"}
+	assert.NoError(t, importValidator.Struct(&valid))
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册 GIF 解码器
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+	"gorm.io/datatypes"
+)
+
+var (
+	profilePhonePattern  = regexp.MustCompile(`^\+?[0-9][0-9 -]{5,18}[0-9]$`)
+	profileLocalePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+)
+
+// avatarFormats 允许上传的头像格式，按文件内容识别，不信任客户端声明的类型
+var avatarFormats = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// UpdateProfile 更新当前用户的个人资料，用户名、角色和状态只能由管理员修改
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, req *model.UpdateProfileRequest) (*model.User, error) {
+	if err := validateProfileRequest(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedFields := []string{}
+	if req.Email != nil && *req.Email != user.Email {
+		exists, err := s.userRepo.CheckEmailExistsExcludeID(*req.Email, userID)
+		if err != nil {
+			logger.FromContext(ctx).Error("检查邮箱失败",
+				zap.Uint("user_id", userID),
+				zap.String("email", *req.Email),
+				zap.Error(err),
+				zap.String("operation", "update_profile"))
+			return nil, apperrors.NewEmailCheckFailedError()
+		}
+		if exists {
+			return nil, apperrors.NewEmailExistsError()
+		}
+		user.Email = *req.Email
+		updatedFields = append(updatedFields, "email")
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+		dest  *string
+	}{
+		{"display_name", req.DisplayName, &user.DisplayName},
+		{"phone", req.Phone, &user.Phone},
+		{"department", req.Department, &user.Department},
+		{"locale", req.Locale, &user.Locale},
+		{"timezone", req.Timezone, &user.Timezone},
+	} {
+		if field.value != nil && *field.value != *field.dest {
+			*field.dest = *field.value
+			updatedFields = append(updatedFields, field.name)
+		}
+	}
+	if req.Preferences != nil {
+		preferences, err := json.Marshal(req.Preferences)
+		if err != nil {
+			return nil, apperrors.NewValidationError("个人偏好设置格式错误")
+		}
+		if len(preferences) > model.ProfilePreferencesMax {
+			return nil, apperrors.NewValidationError("个人偏好设置不能超过 8KB")
+		}
+		user.Preferences = datatypes.JSON(preferences)
+		updatedFields = append(updatedFields, "preferences")
+	}
+
+	if len(updatedFields) == 0 {
+		return user, nil
+	}
+
+	// 只写入本次修改的字段，不覆盖期间管理员对状态、密码等字段的修改
+	if err := s.userRepo.UpdateColumns(ctx, user, updatedFields...); err != nil {
+		logger.FromContext(ctx).Error("更新个人资料失败",
+			zap.Uint("user_id", userID),
+			zap.Strings("updated_fields", updatedFields),
+			zap.Error(err),
+			zap.String("operation", "update_profile"))
+		return nil, apperrors.NewUserUpdateFailedError()
+	}
+
+	logger.FromContext(ctx).Info("个人资料更新成功",
+		zap.Uint("user_id", userID),
+		zap.Strings("updated_fields", updatedFields),
+		zap.String("operation", "update_profile"))
+
+	return user, nil
+}
+
+// validateProfileRequest 去除首尾空格并校验手机号、语言和时区格式
+func validateProfileRequest(req *model.UpdateProfileRequest) error {
+	for _, value := range []*string{req.Email, req.DisplayName, req.Phone, req.Department, req.Locale, req.Timezone} {
+		if value != nil {
+			*value = strings.TrimSpace(*value)
+		}
+	}
+
+	if req.Phone != nil && *req.Phone != "" && !profilePhonePattern.MatchString(*req.Phone) {
+		return apperrors.NewValidationError("手机号格式不正确")
+	}
+	if req.Locale != nil && !profileLocalePattern.MatchString(*req.Locale) {
+		return apperrors.NewValidationError("语言格式不正确，应为 zh-CN、en-US 等形式")
+	}
+	if req.Timezone != nil {
+		// LoadLocation 对空字符串返回 UTC、对 Local 返回服务器时区，二者都不是有效的 IANA 时区名
+		if *req.Timezone == "" || *req.Timezone == "Local" {
+			return apperrors.NewValidationError("时区不能为空")
+		}
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return apperrors.NewValidationError("时区无效，应为 Asia/Shanghai 等 IANA 时区名")
+		}
+	}
+	return nil
+}
+
+// UploadAvatar 处理并保存当前用户上传的头像，保存成功后删除旧头像文件
+func (s *UserService) UploadAvatar(ctx context.Context, userID uint, r io.Reader) (*model.User, error) {
+	user, err := s.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	avatar, err := processAvatar(r)
+	if err != nil {
+		logger.FromContext(ctx).Warn("处理头像失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "upload_avatar"))
+		return nil, err
+	}
+
+	// 每次上传使用新的文件名，避免浏览器和 CDN 缓存旧头像
+	key := fmt.Sprintf("avatars/%d/%d%s", userID, time.Now().UnixNano(), avatar.ext)
+	url, err := s.fileStorage.Save(ctx, key, bytes.NewReader(avatar.data), avatar.contentType)
+	if err != nil {
+		logger.FromContext(ctx).Error("保存头像文件失败",
+			zap.Uint("user_id", userID),
+			zap.String("key", key),
+			zap.Error(err),
+			zap.String("operation", "upload_avatar"))
+		return nil, apperrors.NewAvatarUploadFailedError()
+	}
+
+	oldKey := user.AvatarKey
+	user.AvatarURL = url
+	user.AvatarKey = key
+	if err := s.userRepo.UpdateColumns(ctx, user, "avatar_url", "avatar_key"); err != nil {
+		logger.FromContext(ctx).Error("更新用户头像失败",
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+			zap.String("operation", "upload_avatar"))
+		s.deleteAvatarFile(ctx, userID, key, "upload_avatar")
+		return nil, apperrors.NewUserUpdateFailedError()
+	}
+	if oldKey != "" {
+		s.deleteAvatarFile(ctx, userID, oldKey, "upload_avatar")
+	}
+
+	logger.FromContext(ctx).Info("头像上传成功",
+		zap.Uint("user_id", userID),
+		zap.String("key", key),
+		zap.Int("size", len(avatar.data)),
+		zap.String("operation", "upload_avatar"))
+
+	return user, nil
+}
+
+// deleteAvatarFile 删除头像文件，失败只记录日志，残留文件不影响使用
+func (s *UserService) deleteAvatarFile(ctx context.Context, userID uint, key, operation string) {
+	if err := s.fileStorage.Delete(ctx, key); err != nil {
+		logger.FromContext(ctx).Warn("删除头像文件失败",
+			zap.Uint("user_id", userID),
+			zap.String("key", key),
+			zap.Error(err),
+			zap.String("operation", operation))
+	}
+}
+
+// processedAvatar 处理后的头像文件
+type processedAvatar struct {
+	data        []byte
+	contentType string
+	ext         string
+}
+
+// processAvatar 校验头像文件的大小、格式和尺寸，从中心裁剪为正方形并缩放
+// JPEG 仍保存为 JPEG，其他格式保存为 PNG 以保留透明背景
+func processAvatar(r io.Reader) (*processedAvatar, error) {
+	data, err := io.ReadAll(io.LimitReader(r, model.AvatarMaxFileSize+1))
+	if err != nil {
+		return nil, apperrors.NewAvatarInvalidError("读取头像文件失败")
+	}
+	if len(data) > model.AvatarMaxFileSize {
+		return nil, apperrors.NewAvatarInvalidError("头像文件不能超过 2MB")
+	}
+
+	contentType := http.DetectContentType(data)
+	if !avatarFormats[contentType] {
+		return nil, apperrors.NewAvatarInvalidError("头像只支持 JPEG、PNG、GIF、WebP 格式")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return nil, apperrors.NewAvatarInvalidError("")
+	}
+	if config.Width > model.AvatarMaxDimension || config.Height > model.AvatarMaxDimension {
+		return nil, apperrors.NewAvatarInvalidError(fmt.Sprintf("头像尺寸不能超过 %dx%d", model.AvatarMaxDimension, model.AvatarMaxDimension))
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewAvatarInvalidError("")
+	}
+	dst := resizeAvatar(src, model.AvatarSize)
+
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, apperrors.NewAvatarUploadFailedError()
+		}
+		return &processedAvatar{data: buf.Bytes(), contentType: "image/jpeg", ext: ".jpg"}, nil
+	}
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, apperrors.NewAvatarUploadFailedError()
+	}
+	return &processedAvatar{data: buf.Bytes(), contentType: "image/png", ext: ".png"}, nil
+}
+
+// resizeAvatar 从图片中心裁剪出最大的正方形并缩放到 size×size
+func resizeAvatar(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, image.Rect(x0, y0, x0+side, y0+side), draw.Src, nil)
+	return dst
+}
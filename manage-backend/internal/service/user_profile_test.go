@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm/schema"
+)
+
+// fakeProfileUserRepo 在 fakeUserRepo 的基础上按内存数据检查邮箱是否被占用、只更新指定字段
+// beforeUpdate 在写入前调用，用于模拟读取之后其他人对同一用户的修改
+type fakeProfileUserRepo struct {
+	*fakeUserRepo
+	beforeUpdate func()
+}
+
+func (r *fakeProfileUserRepo) UpdateColumns(ctx context.Context, user *model.User, columns ...string) error {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+	stored := reflect.ValueOf(r.users[user.ID]).Elem()
+	updated := reflect.ValueOf(user).Elem()
+	for i := 0; i < updated.NumField(); i++ {
+		if slices.Contains(columns, schema.NamingStrategy{}.ColumnName("", updated.Type().Field(i).Name)) {
+			stored.Field(i).Set(updated.Field(i))
+		}
+	}
+	return nil
+}
+
+func (r *fakeProfileUserRepo) CheckEmailExistsExcludeID(email string, excludeID uint) (bool, error) {
+	for id, user := range r.users {
+		if id != excludeID && user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeStorage 内存中的文件存储
+type fakeStorage struct {
+	files   map[string][]byte
+	deleted []string
+	saveErr error
+}
+
+func (s *fakeStorage) Save(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	if s.saveErr != nil {
+		return "", s.saveErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.files[key] = data
+	return "/uploads/" + key, nil
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func newProfileTestService() (*UserService, *fakeProfileUserRepo, *fakeStorage) {
+	userRepo := &fakeProfileUserRepo{fakeUserRepo: &fakeUserRepo{users: map[uint]*model.User{
+		1: {ID: 1, Username: "alice", Email: "alice@example.com", Role: "user", Status: model.UserStatusActive, Locale: "zh-CN", Timezone: "Asia/Shanghai"},
+		2: {ID: 2, Username: "bob", Email: "bob@example.com", Role: "user", Status: model.UserStatusActive},
+	}}}
+	files := &fakeStorage{files: map[string][]byte{}}
+	service := &UserService{
+		userRepo:    userRepo,
+		fileStorage: files,
+	}
+	return service, userRepo, files
+}
+
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		require.NoError(t, png.Encode(&buf, img))
+	case "jpeg":
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestUserServiceUpdateProfile(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("updates only provided fields", func(t *testing.T) {
+		service, userRepo, _ := newProfileTestService()
+
+		user, err := service.UpdateProfile(ctx, 1, &model.UpdateProfileRequest{
+			DisplayName: stringPtr("  Alice  "),
+			Phone:       stringPtr("+86 138-0000-0000"),
+			Timezone:    stringPtr("America/New_York"),
+			Preferences: map[string]interface{}{"theme": "dark", "page_size": 20},
+		})
+		require.NoError(t, err)
+
+		stored := userRepo.users[1]
+		assert.Equal(t, "Alice", stored.DisplayName)
+		assert.Equal(t, "+86 138-0000-0000", stored.Phone)
+		assert.Equal(t, "America/New_York", stored.Timezone)
+		assert.Equal(t, "zh-CN", stored.Locale)
+		assert.Equal(t, "alice@example.com", stored.Email)
+		assert.JSONEq(t, `{"theme":"dark","page_size":20}`, string(stored.Preferences))
+		assert.Equal(t, "Alice", user.ToResponse().DisplayName)
+	})
+
+	t.Run("keeps admin changes made after the profile was read", func(t *testing.T) {
+		service, userRepo, _ := newProfileTestService()
+		userRepo.beforeUpdate = func() {
+			userRepo.users[1].Status = model.UserStatusInactive
+			userRepo.users[1].MustChangePassword = true
+		}
+
+		_, err := service.UpdateProfile(ctx, 1, &model.UpdateProfileRequest{DisplayName: stringPtr("Alice")})
+		require.NoError(t, err)
+
+		stored := userRepo.users[1]
+		assert.Equal(t, "Alice", stored.DisplayName)
+		assert.Equal(t, model.UserStatusInactive, stored.Status)
+		assert.True(t, stored.MustChangePassword)
+	})
+
+	t.Run("email already used by another user", func(t *testing.T) {
+		service, userRepo, _ := newProfileTestService()
+
+		_, err := service.UpdateProfile(ctx, 1, &model.UpdateProfileRequest{Email: stringPtr("bob@example.com")})
+		requireBusinessCode(t, err, apperrors.CodeEmailExists)
+		assert.Equal(t, "alice@example.com", userRepo.users[1].Email)
+	})
+
+	invalid := map[string]*model.UpdateProfileRequest{
+		"phone":       {Phone: stringPtr("call me")},
+		"locale":      {Locale: stringPtr("Chinese")},
+		"timezone":    {Timezone: stringPtr("Mars/Olympus")},
+		"empty zone":  {Timezone: stringPtr(" ")},
+		"preferences": {Preferences: map[string]interface{}{"blob": strings.Repeat("x", model.ProfilePreferencesMax)}},
+	}
+	for name, req := range invalid {
+		t.Run("invalid "+name, func(t *testing.T) {
+			service, userRepo, _ := newProfileTestService()
+
+			_, err := service.UpdateProfile(ctx, 1, req)
+			var appErr *apperrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, apperrors.ErrorTypeValidation, appErr.Type)
+			assert.Equal(t, "Asia/Shanghai", userRepo.users[1].Timezone)
+			assert.Empty(t, userRepo.users[1].Phone)
+		})
+	}
+}
+
+func TestUserServiceUploadAvatar(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("resizes and replaces old avatar", func(t *testing.T) {
+		service, userRepo, files := newProfileTestService()
+		userRepo.users[1].AvatarKey = "avatars/1/old.png"
+		files.files["avatars/1/old.png"] = []byte("old")
+
+		user, err := service.UploadAvatar(ctx, 1, bytes.NewReader(encodeTestImage(t, "png", 400, 200)))
+		require.NoError(t, err)
+
+		stored := userRepo.users[1]
+		assert.True(t, strings.HasPrefix(stored.AvatarKey, "avatars/1/"))
+		assert.True(t, strings.HasSuffix(stored.AvatarKey, ".png"))
+		assert.Equal(t, "/uploads/"+stored.AvatarKey, user.AvatarURL)
+		assert.Equal(t, user.AvatarURL, stored.AvatarURL)
+		assert.Equal(t, []string{"avatars/1/old.png"}, files.deleted)
+
+		saved, _, err := image.DecodeConfig(bytes.NewReader(files.files[stored.AvatarKey]))
+		require.NoError(t, err)
+		assert.Equal(t, model.AvatarSize, saved.Width)
+		assert.Equal(t, model.AvatarSize, saved.Height)
+	})
+
+	t.Run("jpeg stays jpeg", func(t *testing.T) {
+		service, userRepo, files := newProfileTestService()
+
+		_, err := service.UploadAvatar(ctx, 1, bytes.NewReader(encodeTestImage(t, "jpeg", 100, 300)))
+		require.NoError(t, err)
+
+		key := userRepo.users[1].AvatarKey
+		assert.True(t, strings.HasSuffix(key, ".jpg"))
+		_, format, err := image.DecodeConfig(bytes.NewReader(files.files[key]))
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+	})
+
+	t.Run("storage failure keeps old avatar", func(t *testing.T) {
+		service, userRepo, files := newProfileTestService()
+		userRepo.users[1].AvatarKey = "avatars/1/old.png"
+		files.saveErr = errors.New("disk full")
+
+		_, err := service.UploadAvatar(ctx, 1, bytes.NewReader(encodeTestImage(t, "png", 10, 10)))
+		requireBusinessCode(t, err, apperrors.CodeAvatarUploadFailed)
+		assert.Equal(t, "avatars/1/old.png", userRepo.users[1].AvatarKey)
+		assert.Empty(t, files.deleted)
+	})
+
+	invalid := map[string][]byte{
+		"not an image":   []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+		"truncated png":  encodeTestImage(t, "png", 10, 10)[:40],
+		"too large":      append(encodeTestImage(t, "png", 10, 10), make([]byte, model.AvatarMaxFileSize)...),
+		"over dimension": encodeTestImage(t, "png", model.AvatarMaxDimension+1, 1),
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			service, userRepo, files := newProfileTestService()
+
+			_, err := service.UploadAvatar(ctx, 1, bytes.NewReader(data))
+			requireBusinessCode(t, err, apperrors.CodeAvatarInvalid)
+			assert.Empty(t, userRepo.users[1].AvatarKey)
+			assert.Empty(t, files.files)
+		})
+	}
+}
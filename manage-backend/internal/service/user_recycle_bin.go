@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ListDeleted 分页查询回收站中的用户
+func (s *UserService) ListDeleted(query *model.ListQuery) ([]model.UserResponse, int64, error) {
+	users, total, err := s.userRepo.ListDeleted(query)
+	if err != nil {
+		logger.Error("查询已删除用户失败",
+			zap.Int("page", query.Page),
+			zap.Int("page_size", query.PageSize),
+			zap.Error(err),
+			zap.String("operation", "list_deleted_users"))
+		return nil, 0, apperrors.NewRecycleBinListFailedError()
+	}
+
+	responses := make([]model.UserResponse, len(users))
+	for i := range users {
+		responses[i] = users[i].ToResponse()
+	}
+	return responses, total, nil
+}
+
+// Restore 从回收站恢复用户
+// 删除期间用户名和邮箱仍被占用，恢复时不会产生唯一性冲突
+func (s *UserService) Restore(id uint) error {
+	user, err := s.getDeletedUser(id, "restore_user")
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Restore(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.NewRecycleBinNotFoundError("用户不存在或未被删除")
+		}
+		logger.Error("恢复用户失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "restore_user"))
+		return apperrors.NewRecycleBinRestoreFailedError()
+	}
+
+	logger.Info("恢复用户成功",
+		zap.Uint("user_id", id),
+		zap.String("username", user.Username),
+		zap.String("operation", "restore_user"))
+	return nil
+}
+
+// Purge 彻底删除回收站中的用户，同时清理角色关联、Casbin 用户-角色关系、所有会话和头像文件
+// 彻底删除后用户名和邮箱可以被重新注册
+func (s *UserService) Purge(ctx context.Context, id uint) error {
+	user, err := s.getDeletedUser(id, "purge_user")
+	if err != nil {
+		return err
+	}
+
+	if s.casbinService != nil {
+		if err := s.casbinService.RemoveAllRolesForUser(id); err != nil {
+			logger.FromContext(ctx).Error("移除用户Casbin角色失败",
+				zap.Uint("user_id", id),
+				zap.Error(err),
+				zap.String("operation", "purge_user"))
+			return apperrors.NewUserCasbinRoleRemoveFailedError()
+		}
+	}
+
+	if _, err := s.sessionService.RevokeAllSessions(ctx, id, ""); err != nil {
+		logger.FromContext(ctx).Error("吊销用户会话失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "purge_user"))
+		return apperrors.NewSessionRevokeFailedError()
+	}
+
+	if err := s.userRepo.Purge(id); err != nil {
+		logger.FromContext(ctx).Error("彻底删除用户失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", "purge_user"))
+		return apperrors.NewRecycleBinPurgeFailedError()
+	}
+	if user.AvatarKey != "" {
+		s.deleteAvatarFile(ctx, id, user.AvatarKey, "purge_user")
+	}
+
+	logger.FromContext(ctx).Info("彻底删除用户成功",
+		zap.Uint("user_id", id),
+		zap.String("username", user.Username),
+		zap.String("operation", "purge_user"))
+	return nil
+}
+
+// getDeletedUser 获取回收站中的用户，用户不存在或未删除时返回 NotFound 错误
+func (s *UserService) getDeletedUser(id uint, operation string) (*model.User, error) {
+	user, err := s.userRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewRecycleBinNotFoundError("用户不存在或未被删除")
+		}
+		logger.Error("查询已删除用户失败",
+			zap.Uint("user_id", id),
+			zap.Error(err),
+			zap.String("operation", operation))
+		return nil, apperrors.NewUserQueryFailedError()
+	}
+	return user, nil
+}
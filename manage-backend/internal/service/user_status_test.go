@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
+	apperrors "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/errors"
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckUserStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		status    string
+		expiresAt *time.Time
+		wantCode  int
+	}{
+		{"active", model.UserStatusActive, nil, 0},
+		{"active not yet expired", model.UserStatusActive, &future, 0},
+		{"active expired", model.UserStatusActive, &past, apperrors.CodeUserExpired},
+		{"inactive", model.UserStatusInactive, nil, apperrors.CodeUserDisabled},
+		{"locked", model.UserStatusLocked, nil, apperrors.CodeUserDisabled},
+		{"unknown status", "deleted", nil, apperrors.CodeUserDisabled},
+		{"suspended", model.UserStatusSuspended, &past, apperrors.CodeUserSuspended},
+		{"pending", model.UserStatusPending, nil, apperrors.CodeUserPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUserStatus(&model.User{Status: tt.status, ExpiresAt: tt.expiresAt}, now)
+			if tt.wantCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var appErr *apperrors.AppError
+			if assert.ErrorAs(t, err, &appErr) {
+				assert.Equal(t, tt.wantCode, appErr.BusinessCode)
+			}
+		})
+	}
+}
+
+func TestUserServiceDeleteRevokesSessions(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	ctx := context.Background()
+
+	t.Run("revokes sessions after delete", func(t *testing.T) {
+		service, userRepo, _, sessions, _ := newBatchTestService()
+
+		require.NoError(t, service.Delete(ctx, 2))
+		assert.NotContains(t, userRepo.users, uint(2))
+		assert.Equal(t, []uint{2}, sessions.revoked)
+	})
+
+	t.Run("reports failed revoke", func(t *testing.T) {
+		service, userRepo, _, sessions, _ := newBatchTestService()
+		sessions.revokeErr = errors.New("connection refused")
+
+		err := service.Delete(ctx, 2)
+		var appErr *apperrors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, apperrors.CodeUserSessionsNotRevoked, appErr.BusinessCode)
+		assert.NotContains(t, userRepo.users, uint(2))
+	})
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 表格文件格式
+const (
+	TableFormatCSV  = "csv"
+	TableFormatXLSX = "xlsx"
+)
+
+// xlsxUnzipSizeLimit 读取 XLSX 时解压后的大小上限，防止压缩炸弹
+const xlsxUnzipSizeLimit = 64 << 20
+
+// utf8BOM Excel 打开 CSV 时依赖 BOM 识别 UTF-8 编码
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ErrUnsupportedTableFormat 不支持的表格文件格式
+var ErrUnsupportedTableFormat = errors.New("仅支持 csv 和 xlsx 格式")
+
+// TableFormatFromFilename 根据文件扩展名判断表格格式
+func TableFormatFromFilename(filename string) (string, error) {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")) {
+	case TableFormatCSV:
+		return TableFormatCSV, nil
+	case TableFormatXLSX:
+		return TableFormatXLSX, nil
+	default:
+		return "", ErrUnsupportedTableFormat
+	}
+}
+
+// ReadTable 读取 CSV 或 XLSX（第一个工作表）的所有行，单元格去除首尾空白
+func ReadTable(r io.Reader, format string) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+	switch format {
+	case TableFormatCSV:
+		rows, err = readCSV(r)
+	case TableFormatXLSX:
+		rows, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedTableFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+	return rows, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 文件失败: %w", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析 CSV 文件失败: %w", err)
+	}
+	return rows, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r, excelize.Options{UnzipSizeLimit: xlsxUnzipSizeLimit})
+	if err != nil {
+		return nil, fmt.Errorf("解析 XLSX 文件失败: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("读取 XLSX 工作表失败: %w", err)
+	}
+	return rows, nil
+}
+
+// WriteTable 将表头和数据行写为 CSV 或 XLSX
+func WriteTable(w io.Writer, format string, header []string, rows [][]string) error {
+	switch format {
+	case TableFormatCSV:
+		return writeCSV(w, header, rows)
+	case TableFormatXLSX:
+		return writeXLSX(w, header, rows)
+	default:
+		return ErrUnsupportedTableFormat
+	}
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = escapeCSVFormula(cell)
+		}
+		if err := writer.Write(escaped); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCSVFormula 以公式字符开头的单元格加单引号前缀，防止在表格软件中被当作公式执行
+func escapeCSVFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func writeXLSX(w io.Writer, header []string, rows [][]string) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := file.GetSheetName(0)
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range append([][]string{header}, rows...) {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(row))
+		for j, value := range row {
+			values[j] = value
+		}
+		if err := stream.SetRow(cell, values); err != nil {
+			return err
+		}
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return file.Write(w)
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableFormatFromFilename(t *testing.T) {
+	format, err := TableFormatFromFilename("users.CSV")
+	require.NoError(t, err)
+	assert.Equal(t, TableFormatCSV, format)
+
+	format, err = TableFormatFromFilename("users.xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, TableFormatXLSX, format)
+
+	_, err = TableFormatFromFilename("users.xls")
+	assert.ErrorIs(t, err, ErrUnsupportedTableFormat)
+}
+
+func TestTableRoundTrip(t *testing.T) {
+	header := []string{"username", "email"}
+	rows := [][]string{
+		{"alice", "alice@example.com"},
+		{"张三", "zhangsan@example.com"},
+	}
+
+	for _, format := range []string{TableFormatCSV, TableFormatXLSX} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteTable(&buf, format, header, rows))
+
+			got, err := ReadTable(&buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, append([][]string{header}, rows...), got)
+		})
+	}
+}
+
+func TestReadTableCSV(t *testing.T) {
+	data := "\xEF\xBB\xBFusername, email\n bob ,bob@example.com,extra\n"
+
+	rows, err := ReadTable(bytes.NewBufferString(data), TableFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"username", "email"},
+		{"bob", "bob@example.com", "extra"},
+	}, rows)
+}
+
+func TestWriteTableCSVEscapesFormula(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, TableFormatCSV, []string{"username"}, [][]string{{"=cmd()"}, {"ok"}}))
+
+	assert.Equal(t, "\xEF\xBB\xBFusername\n'=cmd()\nok\n", buf.String())
+}
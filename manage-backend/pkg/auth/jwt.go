@@ -16,12 +16,13 @@ type Claims struct {
 	Username string `json:"username"` // 用户名
 	Role     string `json:"role"`     // 用户角色
 	JTI      string `json:"jti"`      // JWT唯一ID (用于黑名单支持)
+	SessionID string `json:"sid,omitempty"` // 会话ID (多设备会话支持)
 	jwt.RegisteredClaims              // 标准 JWT 声明 (exp, iat, nbf, iss, sub 等)
 }
 
 // JWTManager 用于生成和验证 JWT
 type JWTManager struct {
-	secretKey          string        // 密钥，用于签名和验证
+	keySet             *KeySet       // 密钥集合，用于签名和验证
 	accessTokenExpire  time.Duration // Access Token 过期时间
 	refreshTokenExpire time.Duration // Refresh Token 过期时间
 }
@@ -32,22 +33,35 @@ type TokenPair struct {
 	RefreshToken     string `json:"refresh_token"`      // 刷新令牌
 	ExpiresIn        int64  `json:"expires_in"`         // Access Token 有效时长（秒）
 	RefreshExpiresIn int64  `json:"refresh_expires_in"` // Refresh Token 有效时长（秒）
+	AccessJTI        string `json:"-"`                  // Access Token 的 JTI
+	RefreshJTI       string `json:"-"`                  // Refresh Token 的 JTI
 }
 
-// NewJWTManager 创建一个新的 JWT 管理器
+// NewJWTManager 创建一个新的 JWT 管理器（HS256 共享密钥）
 // secretKey: 签名密钥
 // accessExpireMinutes: access token 有效期（分钟）
 // refreshExpireHours: refresh token 有效期（小时）
 func NewJWTManager(secretKey string, accessExpireMinutes, refreshExpireHours int) *JWTManager {
+	return NewJWTManagerWithKeySet(NewHMACKeySet(secretKey), accessExpireMinutes, refreshExpireHours)
+}
+
+// NewJWTManagerWithKeySet 使用指定密钥集合创建 JWT 管理器
+// 令牌由活跃密钥签名，集合中的其他密钥仅用于验签
+func NewJWTManagerWithKeySet(keySet *KeySet, accessExpireMinutes, refreshExpireHours int) *JWTManager {
 	return &JWTManager{
-		secretKey:          secretKey,
+		keySet:             keySet,
 		accessTokenExpire:  time.Duration(accessExpireMinutes) * time.Minute,
 		refreshTokenExpire: time.Duration(refreshExpireHours) * time.Hour,
 	}
 }
 
-// GenerateTokenPair 生成 Access Token 和 Refresh Token
+// GenerateTokenPair 生成 Access Token 和 Refresh Token（不绑定会话）
 func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*TokenPair, error) {
+	return j.GenerateTokenPairWithSession(userID, username, role, "")
+}
+
+// GenerateTokenPairWithSession 生成绑定到指定会话的 Access Token 和 Refresh Token
+func (j *JWTManager) GenerateTokenPairWithSession(userID uint, username, role, sessionID string) (*TokenPair, error) {
 	// 生成 Access Token 的 JTI
 	accessJTI, err := j.generateJTI()
 	if err != nil {
@@ -60,6 +74,7 @@ func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*Tok
 		Username: username,
 		Role:     role,
 		JTI:      accessJTI,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTokenExpire)), // 过期时间
 			IssuedAt:  jwt.NewNumericDate(time.Now()),                          // 签发时间
@@ -70,8 +85,7 @@ func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*Tok
 	}
 
 	// 签名生成 Access Token
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString([]byte(j.secretKey))
+	accessTokenString, err := j.keySet.Sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +102,7 @@ func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*Tok
 		Username: username,
 		Role:     role,
 		JTI:      refreshJTI,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.refreshTokenExpire)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -98,8 +113,7 @@ func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*Tok
 	}
 
 	// 签名生成 Refresh Token
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString([]byte(j.secretKey))
+	refreshTokenString, err := j.keySet.Sign(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +124,8 @@ func (j *JWTManager) GenerateTokenPair(userID uint, username, role string) (*Tok
 		RefreshToken:     refreshTokenString,
 		ExpiresIn:        int64(j.accessTokenExpire.Seconds()),
 		RefreshExpiresIn: int64(j.refreshTokenExpire.Seconds()),
+		AccessJTI:        accessJTI,
+		RefreshJTI:       refreshJTI,
 	}, nil
 }
 
@@ -124,13 +140,8 @@ func (j *JWTManager) GenerateToken(userID uint, username, role string) (string,
 
 // ValidateToken 验证 JWT Token 并解析为 Claims
 func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
-	// 使用密钥验证 Token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("不支持的签名方法")
-		}
-		return []byte(j.secretKey), nil
-	})
+	// 根据 kid 选择密钥验证 Token
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.keySet.Keyfunc, jwt.WithValidMethods(j.keySet.ValidMethods()))
 
 	if err != nil {
 		return nil, err
@@ -167,6 +178,11 @@ func (j *JWTManager) generateJTI() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
+// KeySet 返回 JWT 管理器使用的密钥集合
+func (j *JWTManager) KeySet() *KeySet {
+	return j.keySet
+}
+
 // GetTokenExpiration 获取 Token 剩余的过期时间
 func (j *JWTManager) GetTokenExpiration(claims *Claims) time.Duration {
 	if claims.ExpiresAt == nil {
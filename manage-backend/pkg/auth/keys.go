@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// HMACKeyID 对称密钥使用的 kid（同时用于校验未携带 kid 的旧令牌）
+const HMACKeyID = "hs256"
+
+// minRSAKeyBits RSA 密钥的最小长度
+const minRSAKeyBits = 2048
+
+// SigningKey 表示一把签名/验签密钥
+// 活跃密钥必须持有私钥；已退役的密钥只需公钥，用于校验其签发且尚未过期的令牌
+type SigningKey struct {
+	ID         string            // 密钥ID，写入令牌头部的 kid
+	Method     jwt.SigningMethod // 签名方法
+	signKey    interface{}       // 签名使用的密钥（RSA/Ed25519 私钥或 HMAC 密钥）
+	verifyKey  interface{}       // 验签使用的密钥（RSA/Ed25519 公钥或 HMAC 密钥）
+	publicOnly bool              // 是否仅有公钥（不可用于签名）
+}
+
+// KeySet 管理当前签名密钥以及所有可用于验签的密钥
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// JWK JSON Web Key（仅包含公钥部分）
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // OKP 曲线
+	X   string `json:"x,omitempty"`   // OKP 公钥
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKeySet 创建仅包含 HS256 共享密钥的密钥集合（兼容原有配置）
+func NewHMACKeySet(secret string) *KeySet {
+	key := newHMACKey(secret)
+	return &KeySet{
+		active: key,
+		keys:   map[string]*SigningKey{key.ID: key},
+	}
+}
+
+// LoadKeySet 根据 JWT 配置加载密钥集合
+// 未配置 active_key_id 时沿用 HS256 + secret；
+// 否则从 PEM 文件加载 keys 中的所有密钥，active_key_id 指定的密钥用于签名，其余密钥仅用于验签
+func LoadKeySet(cfg config.JWT) (*KeySet, error) {
+	if cfg.ActiveKeyID == "" {
+		if cfg.Secret == "" {
+			return nil, errors.New("未配置 jwt.secret 或 jwt.active_key_id")
+		}
+		return NewHMACKeySet(cfg.Secret), nil
+	}
+
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	for _, keyCfg := range cfg.Keys {
+		if keyCfg.ID == "" {
+			return nil, errors.New("JWT 密钥缺少 id")
+		}
+		if _, exists := ks.keys[keyCfg.ID]; exists {
+			return nil, fmt.Errorf("JWT 密钥 id 重复: %s", keyCfg.ID)
+		}
+
+		key, err := loadKey(keyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("加载 JWT 密钥 %s 失败: %w", keyCfg.ID, err)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("未找到 active_key_id 对应的密钥: %s", cfg.ActiveKeyID)
+	}
+	if active.publicOnly {
+		return nil, fmt.Errorf("活跃密钥 %s 未配置私钥", active.ID)
+	}
+	ks.active = active
+
+	// 迁移期间继续接受旧的 HS256 令牌
+	if cfg.AcceptLegacyHS256 {
+		if cfg.Secret == "" {
+			return nil, errors.New("启用 accept_legacy_hs256 时必须配置 jwt.secret")
+		}
+		if _, exists := ks.keys[HMACKeyID]; exists {
+			return nil, fmt.Errorf("JWT 密钥 id %s 为保留值", HMACKeyID)
+		}
+		legacy := newHMACKey(cfg.Secret)
+		ks.keys[legacy.ID] = legacy
+	}
+
+	return ks, nil
+}
+
+// ActiveKeyID 返回当前签名密钥的 kid
+func (ks *KeySet) ActiveKeyID() string {
+	return ks.active.ID
+}
+
+// Algorithm 返回当前签名算法
+func (ks *KeySet) Algorithm() string {
+	return ks.active.Method.Alg()
+}
+
+// Sign 使用活跃密钥签名声明，并在头部写入 kid
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.signKey)
+}
+
+// Keyfunc 根据令牌头部的 kid 选择验签密钥
+// 未携带 kid 的令牌视为旧版 HS256 令牌
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = HMACKeyID
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("未知的密钥ID: %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("不支持的签名方法")
+	}
+	return key.verifyKey, nil
+}
+
+// ValidMethods 返回所有允许的签名算法
+func (ks *KeySet) ValidMethods() []string {
+	seen := make(map[string]bool)
+	methods := make([]string, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS 返回所有非对称密钥的公钥（HMAC 密钥不会公开）
+// 活跃密钥排在首位，其余按 kid 排序
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	if jwk, ok := toJWK(ks.active); ok {
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.active.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if jwk, ok := toJWK(ks.keys[id]); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+// newHMACKey 创建 HS256 共享密钥
+func newHMACKey(secret string) *SigningKey {
+	return &SigningKey{
+		ID:        HMACKeyID,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// loadKey 从 PEM 文件加载单个密钥
+// 配置了私钥时从私钥推导公钥；否则只加载公钥（已退役的密钥）
+func loadKey(keyCfg config.JWTKey) (*SigningKey, error) {
+	key := &SigningKey{ID: keyCfg.ID}
+
+	switch keyCfg.Algorithm {
+	case AlgRS256:
+		key.Method = jwt.SigningMethodRS256
+	case AlgEdDSA:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", keyCfg.Algorithm)
+	}
+
+	if keyCfg.PrivateKeyFile != "" {
+		pemData, err := os.ReadFile(keyCfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取私钥文件失败: %w", err)
+		}
+
+		switch keyCfg.Algorithm {
+		case AlgRS256:
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemData)
+			if err != nil {
+				return nil, fmt.Errorf("解析 RSA 私钥失败: %w", err)
+			}
+			if privateKey.N.BitLen() < minRSAKeyBits {
+				return nil, fmt.Errorf("RSA 密钥长度不能小于 %d 位", minRSAKeyBits)
+			}
+			key.signKey = privateKey
+			key.verifyKey = &privateKey.PublicKey
+		case AlgEdDSA:
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemData)
+			if err != nil {
+				return nil, fmt.Errorf("解析 Ed25519 私钥失败: %w", err)
+			}
+			key.signKey = privateKey
+			key.verifyKey = privateKey.(crypto.Signer).Public()
+		}
+		return key, nil
+	}
+
+	if keyCfg.PublicKeyFile == "" {
+		return nil, errors.New("必须配置 private_key_file 或 public_key_file")
+	}
+
+	pemData, err := os.ReadFile(keyCfg.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取公钥文件失败: %w", err)
+	}
+
+	switch keyCfg.Algorithm {
+	case AlgRS256:
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("解析 RSA 公钥失败: %w", err)
+		}
+		key.verifyKey = publicKey
+	case AlgEdDSA:
+		publicKey, err := jwt.ParseEdPublicKeyFromPEM(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("解析 Ed25519 公钥失败: %w", err)
+		}
+		key.verifyKey = publicKey
+	}
+	key.publicOnly = true
+	return key, nil
+}
+
+// toJWK 将公钥转换为 JWK，对称密钥返回 false
+func toJWK(key *SigningKey) (JWK, bool) {
+	switch publicKey := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}, true
+	default:
+		return JWK{}, false
+	}
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRSAKeyPair 生成 RSA 密钥对并写入 PEM 文件，返回私钥和公钥路径
+func writeRSAKeyPair(t *testing.T, dir, name string) (string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return writePEM(t, dir, name+".pem", "PRIVATE KEY", privateDER),
+		writePEM(t, dir, name+".pub.pem", "PUBLIC KEY", publicDER)
+}
+
+// writeEd25519PrivateKey 生成 Ed25519 私钥并写入 PEM 文件
+func writeEd25519PrivateKey(t *testing.T, dir, name string) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	return writePEM(t, dir, name+".pem", "PRIVATE KEY", privateDER)
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestLoadKeySetDefaultsToHMAC(t *testing.T) {
+	ks, err := LoadKeySet(config.JWT{Secret: "test-secret"})
+	require.NoError(t, err)
+
+	assert.Equal(t, AlgHS256, ks.Algorithm())
+	assert.Empty(t, ks.JWKS().Keys, "HMAC 密钥不应公开")
+
+	_, err = LoadKeySet(config.JWT{})
+	assert.Error(t, err)
+}
+
+func TestKeyRotationKeepsRetiredKeysValid(t *testing.T) {
+	dir := t.TempDir()
+	rsaPrivate, rsaPublic := writeRSAKeyPair(t, dir, "rs-1")
+	edPrivate := writeEd25519PrivateKey(t, dir, "ed-1")
+
+	// 使用 RS256 签发令牌
+	oldKeySet, err := LoadKeySet(config.JWT{
+		ActiveKeyID: "rs-1",
+		Keys: []config.JWTKey{
+			{ID: "rs-1", Algorithm: AlgRS256, PrivateKeyFile: rsaPrivate},
+		},
+	})
+	require.NoError(t, err)
+
+	oldManager := NewJWTManagerWithKeySet(oldKeySet, 30, 720)
+	oldPair, err := oldManager.GenerateTokenPairWithSession(1, "admin", "admin", "sid-1")
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(oldPair.AccessToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "rs-1", token.Header["kid"])
+	assert.Equal(t, AlgRS256, token.Method.Alg())
+
+	// 轮换到 EdDSA，RS256 密钥只保留公钥
+	newKeySet, err := LoadKeySet(config.JWT{
+		ActiveKeyID: "ed-1",
+		Keys: []config.JWTKey{
+			{ID: "ed-1", Algorithm: AlgEdDSA, PrivateKeyFile: edPrivate},
+			{ID: "rs-1", Algorithm: AlgRS256, PublicKeyFile: rsaPublic},
+		},
+	})
+	require.NoError(t, err)
+
+	newManager := NewJWTManagerWithKeySet(newKeySet, 30, 720)
+
+	claims, err := newManager.ValidateToken(oldPair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "sid-1", claims.SessionID)
+
+	newPair, err := newManager.GenerateTokenPair(2, "user", "user")
+	require.NoError(t, err)
+	_, err = newManager.ValidateRefreshToken(newPair.RefreshToken)
+	assert.NoError(t, err)
+
+	// 旧的密钥集合不认识新的 kid
+	_, err = oldManager.ValidateToken(newPair.AccessToken)
+	assert.Error(t, err)
+
+	jwks := newKeySet.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "ed-1", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "rs-1", jwks.Keys[1].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+}
+
+func TestLegacyHS256Tokens(t *testing.T) {
+	dir := t.TempDir()
+	edPrivate := writeEd25519PrivateKey(t, dir, "ed-1")
+
+	legacyPair, err := NewJWTManager("legacy-secret", 30, 720).GenerateTokenPair(1, "admin", "admin")
+	require.NoError(t, err)
+
+	cfg := config.JWT{
+		Secret:      "legacy-secret",
+		ActiveKeyID: "ed-1",
+		Keys: []config.JWTKey{
+			{ID: "ed-1", Algorithm: AlgEdDSA, PrivateKeyFile: edPrivate},
+		},
+	}
+
+	strict, err := LoadKeySet(cfg)
+	require.NoError(t, err)
+	_, err = NewJWTManagerWithKeySet(strict, 30, 720).ValidateToken(legacyPair.AccessToken)
+	assert.Error(t, err, "未开启 accept_legacy_hs256 时应拒绝 HS256 令牌")
+
+	cfg.AcceptLegacyHS256 = true
+	migrating, err := LoadKeySet(cfg)
+	require.NoError(t, err)
+	_, err = NewJWTManagerWithKeySet(migrating, 30, 720).ValidateToken(legacyPair.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestLoadKeySetRejectsPublicOnlyActiveKey(t *testing.T) {
+	dir := t.TempDir()
+	_, rsaPublic := writeRSAKeyPair(t, dir, "rs-1")
+
+	_, err := LoadKeySet(config.JWT{
+		ActiveKeyID: "rs-1",
+		Keys: []config.JWTKey{
+			{ID: "rs-1", Algorithm: AlgRS256, PublicKeyFile: rsaPublic},
+		},
+	})
+	assert.Error(t, err)
+}
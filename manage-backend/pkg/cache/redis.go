@@ -39,6 +39,11 @@ func (r *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error)
 	return r.client.Exists(ctx, keys...).Result()
 }
 
+// Ping 检查 Redis 连接是否可用
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 func (r *RedisClient) Close() error {
 	return r.client.Close()
 }
@@ -61,4 +66,24 @@ func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Du
 // TTL 获取剩余过期时间
 func (r *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
 	return r.client.TTL(ctx, key).Result()
-}
\ No newline at end of file
+}
+
+// SAdd 向集合添加成员
+func (r *RedisClient) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return r.client.SAdd(ctx, key, members...).Err()
+}
+
+// SRem 从集合移除成员
+func (r *RedisClient) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return r.client.SRem(ctx, key, members...).Err()
+}
+
+// SMembers 获取集合所有成员
+func (r *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+// SetNX 仅当键不存在时设置值，返回是否设置成功
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
@@ -48,6 +48,11 @@ func autoMigrate(db *gorm.DB, cfg *config.Config) error {
 		&model.Menu{},
 		&model.UserRole{},
 		&model.RolePermission{},
+		&model.UserTwoFactor{},
+		&model.AuditLog{},
+		&model.AuditLogCheckpoint{},
+		&model.SystemSetting{},
+		&model.PasswordHistory{},
 		// 在这里添加其他模型
 	)
 	
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/model"
 	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/pkg/logger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -105,17 +106,21 @@ var migrations = []Migration{
 		Down: func(db *gorm.DB) error {
 			// 回滚：删除所有表
 			tables := []string{
+				"audit_log_checkpoints",
 				"audit_logs",
 				"casbin_rule",
 				"dict_items",
 				"dict_types",
 				"menus",
 				"migration_records",
+				"password_histories",
 				"password_reset_tokens",
 				"permissions",
 				"role_permissions",
 				"roles",
+				"system_settings",
 				"user_roles",
+				"user_two_factors",
 				"users",
 			}
 
@@ -127,17 +132,20 @@ var migrations = []Migration{
 
 			// 删除序列
 			sequences := []string{
+				"audit_log_checkpoints_id_seq",
 				"audit_logs_id_seq",
 				"casbin_rule_id_seq",
 				"dict_items_id_seq",
 				"dict_types_id_seq",
 				"menus_id_seq",
 				"migration_records_id_seq",
+				"password_histories_id_seq",
 				"password_reset_tokens_id_seq",
 				"permissions_id_seq",
 				"role_permissions_id_seq",
 				"roles_id_seq",
 				"user_roles_id_seq",
+				"user_two_factors_id_seq",
 				"users_id_seq",
 			}
 
@@ -151,6 +159,155 @@ var migrations = []Migration{
 			return nil
 		},
 	},
+	{
+		ID: "003_create_user_two_factors_table",
+		Up: func(db *gorm.DB) error {
+			// 两步验证表（已通过 manage_dev.sql 初始化的数据库会跳过已存在的表）
+			if err := db.AutoMigrate(&model.UserTwoFactor{}); err != nil {
+				return fmt.Errorf("failed to create user_two_factors table: %w", err)
+			}
+
+			logger.Info("两步验证表创建成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&model.UserTwoFactor{}); err != nil {
+				return fmt.Errorf("failed to drop user_two_factors table: %w", err)
+			}
+
+			logger.Info("两步验证表删除成功")
+			return nil
+		},
+	},
+	{
+		ID: "004_add_audit_log_hash_chain",
+		Up: func(db *gorm.DB) error {
+			// 审计日志新增 prev_hash/hash 列，并创建清理检查点表
+			if err := db.AutoMigrate(&model.AuditLog{}, &model.AuditLogCheckpoint{}); err != nil {
+				return fmt.Errorf("failed to migrate audit log hash chain: %w", err)
+			}
+
+			logger.Info("审计日志哈希链迁移成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&model.AuditLogCheckpoint{}); err != nil {
+				return fmt.Errorf("failed to drop audit_log_checkpoints table: %w", err)
+			}
+			for _, column := range []string{"Hash", "PrevHash"} {
+				if err := db.Migrator().DropColumn(&model.AuditLog{}, column); err != nil {
+					return fmt.Errorf("failed to drop audit_logs column %s: %w", column, err)
+				}
+			}
+
+			logger.Info("审计日志哈希链回滚成功")
+			return nil
+		},
+	},
+	{
+		ID: "005_add_audit_log_request_id",
+		Up: func(db *gorm.DB) error {
+			// 审计日志新增 request_id 列及索引
+			if err := db.AutoMigrate(&model.AuditLog{}); err != nil {
+				return fmt.Errorf("failed to add audit_logs request_id column: %w", err)
+			}
+
+			logger.Info("审计日志请求ID迁移成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropColumn(&model.AuditLog{}, "RequestID"); err != nil {
+				return fmt.Errorf("failed to drop audit_logs column request_id: %w", err)
+			}
+
+			logger.Info("审计日志请求ID回滚成功")
+			return nil
+		},
+	},
+	{
+		ID: "006_create_system_settings_table",
+		Up: func(db *gorm.DB) error {
+			// 系统设置表，保存运行时修改的安全策略等配置
+			if err := db.AutoMigrate(&model.SystemSetting{}); err != nil {
+				return fmt.Errorf("failed to create system_settings table: %w", err)
+			}
+
+			logger.Info("系统设置表创建成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&model.SystemSetting{}); err != nil {
+				return fmt.Errorf("failed to drop system_settings table: %w", err)
+			}
+
+			logger.Info("系统设置表删除成功")
+			return nil
+		},
+	},
+	{
+		ID: "007_add_user_status_fields",
+		Up: func(db *gorm.DB) error {
+			// 账户过期时间和下次登录必须修改密码标记
+			if err := db.AutoMigrate(&model.User{}); err != nil {
+				return fmt.Errorf("failed to add user status fields: %w", err)
+			}
+
+			logger.Info("用户状态字段添加成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			for _, column := range []string{"ExpiresAt", "MustChangePassword"} {
+				if err := db.Migrator().DropColumn(&model.User{}, column); err != nil {
+					return fmt.Errorf("failed to drop users column %s: %w", column, err)
+				}
+			}
+
+			logger.Info("用户状态字段删除成功")
+			return nil
+		},
+	},
+	{
+		ID: "008_create_password_histories_table",
+		Up: func(db *gorm.DB) error {
+			// 密码历史表，禁止重复使用最近的旧密码
+			if err := db.AutoMigrate(&model.PasswordHistory{}); err != nil {
+				return fmt.Errorf("failed to create password_histories table: %w", err)
+			}
+
+			logger.Info("密码历史表创建成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Migrator().DropTable(&model.PasswordHistory{}); err != nil {
+				return fmt.Errorf("failed to drop password_histories table: %w", err)
+			}
+
+			logger.Info("密码历史表删除成功")
+			return nil
+		},
+	},
+	{
+		ID: "009_add_user_profile_fields",
+		Up: func(db *gorm.DB) error {
+			// 个人资料字段：显示名称、手机号、部门、语言、时区、头像和个人偏好设置
+			if err := db.AutoMigrate(&model.User{}); err != nil {
+				return fmt.Errorf("failed to add user profile fields: %w", err)
+			}
+
+			logger.Info("用户个人资料字段添加成功")
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			for _, column := range []string{"DisplayName", "Phone", "Department", "Locale", "Timezone", "AvatarURL", "AvatarKey", "Preferences"} {
+				if err := db.Migrator().DropColumn(&model.User{}, column); err != nil {
+					return fmt.Errorf("failed to drop users column %s: %w", column, err)
+				}
+			}
+
+			logger.Info("用户个人资料字段删除成功")
+			return nil
+		},
+	},
 }
 
 // RollbackMigration 回滚指定的迁移
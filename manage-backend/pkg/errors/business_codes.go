@@ -15,6 +15,11 @@ const (
 	CodeInvalidToken       = 10104 // 无效的令牌
 	CodeTokenExpired       = 10105 // 令牌已过期
 	CodeUnauthorized       = 10106 // 未授权
+	CodeTwoFactorInvalidCode      = 10107 // 两步验证码错误
+	CodeTwoFactorChallengeInvalid = 10108 // 两步验证挑战无效或已过期
+	CodeTwoFactorAlreadyEnabled   = 10109 // 已启用两步验证
+	CodeTwoFactorNotEnrolled      = 10110 // 未绑定两步验证
+	CodePasswordChangeRequired    = 10111 // 需要先修改密码
 
 	// 注册相关 (102xx)
 	CodeUsernameExists = 10201 // 用户名已存在
@@ -24,6 +29,9 @@ const (
 	// 用户信息 (103xx)
 	CodeUserNotFound = 10301 // 用户不存在
 	CodeUserDisabled = 10302 // 用户已禁用
+	CodeUserSuspended = 10303 // 用户已暂停
+	CodeUserExpired   = 10304 // 账户已过期
+	CodeUserPending   = 10305 // 用户待审核
 
 	// 用户操作内部错误 (104xx)
 	CodePasswordHashFailed    = 10401 // 密码加密失败
@@ -44,6 +52,24 @@ const (
 	CodeSessionDeleteFailed   = 10504 // 删除会话失败
 	CodeSessionServiceUnavailable = 10505 // 会话服务不可用
 	CodeTokenBlacklistFailed  = 10506 // 添加令牌到黑名单失败
+	CodeSessionNotFound       = 10507 // 会话不存在
+	CodeSessionListFailed     = 10508 // 获取会话列表失败
+	CodeSessionRevokeFailed   = 10509 // 吊销会话失败
+	CodeRefreshTokenReused    = 10510 // 刷新令牌重复使用
+	CodeTwoFactorSetupFailed  = 10511 // 设置两步验证失败
+	CodeTwoFactorQueryFailed  = 10512 // 查询两步验证信息失败
+	CodeTwoFactorResetFailed  = 10513 // 重置两步验证失败
+	CodeUserSessionsNotRevoked = 10514 // 账户已停用但吊销会话失败
+
+	// 批量导入导出 (106xx)
+	CodeUserImportFileInvalid = 10601 // 导入文件无效
+	CodeUserImportFailed      = 10602 // 批量导入用户失败
+	CodeUserExportFailed      = 10603 // 导出用户失败
+	CodeUserExportTooLarge    = 10604 // 导出数据量超过上限
+
+	// 个人资料 (107xx)
+	CodeAvatarInvalid      = 10701 // 头像图片无效
+	CodeAvatarUploadFailed = 10702 // 上传头像失败
 
 	// ========== 角色权限模块 (20xxx) ==========
 
@@ -91,6 +117,8 @@ const (
 	// 限流相关 (301xx)
 	CodeRateLimitExceeded = 30101 // 请求过于频繁
 	CodeIPBlocked         = 30102 // IP被限制
+	CodeRateLimitQueryFailed = 30103 // 查询限流计数失败
+	CodeRateLimitClearFailed = 30104 // 清除限流计数失败
 
 	// 内部错误 (302xx)
 	CodeInternalError  = 30201 // 内部服务错误
@@ -109,6 +137,18 @@ const (
 	CodeResourceNotFound = 30401 // 资源不存在
 	CodeResourceConflict = 30402 // 资源冲突
 
+	// 安全策略 (305xx)
+	CodeSecurityPolicyGetFailed    = 30501 // 获取安全策略失败
+	CodeSecurityPolicyUpdateFailed = 30502 // 更新安全策略失败
+	CodeLoginLockQueryFailed       = 30503 // 查询登录锁定状态失败
+	CodeLoginLockClearFailed       = 30504 // 解除登录锁定失败
+
+	// 回收站 (306xx)
+	CodeRecycleBinNotFound      = 30601 // 回收站中不存在该记录
+	CodeRecycleBinListFailed    = 30602 // 查询回收站失败
+	CodeRecycleBinRestoreFailed = 30603 // 恢复记录失败
+	CodeRecycleBinPurgeFailed   = 30604 // 彻底删除记录失败
+
 	// ========== 字典模块 (40xxx) ==========
 	
 	// 字典类型 (401xx)
@@ -183,6 +223,8 @@ const (
 	CodeAuditLogGetFailed   = 70201 // 获取审计日志失败
 	CodeAuditLogQueryFailed = 70202 // 查询审计日志失败
 	CodeAuditLogCleanFailed = 70203 // 清理审计日志失败
+	CodeAuditLogExportFailed = 70204 // 导出审计日志失败
+	CodeAuditLogVerifyFailed = 70205 // 校验审计日志失败
 )
 
 // GetBusinessCodeMessage 获取业务错误码对应的默认消息
@@ -197,6 +239,11 @@ func GetBusinessCodeMessage(code int) string {
 		CodeInvalidToken:       "无效的令牌",
 		CodeTokenExpired:       "令牌已过期",
 		CodeUnauthorized:       "未授权访问",
+		CodeTwoFactorInvalidCode:      "两步验证码错误",
+		CodeTwoFactorChallengeInvalid: "两步验证已过期，请重新登录",
+		CodeTwoFactorAlreadyEnabled:   "已启用两步验证",
+		CodeTwoFactorNotEnrolled:      "请先生成两步验证密钥",
+		CodePasswordChangeRequired:    "请先修改密码",
 
 		CodeUsernameExists: "用户名已存在",
 		CodeEmailExists:    "邮箱已存在",
@@ -204,6 +251,9 @@ func GetBusinessCodeMessage(code int) string {
 
 		CodeUserNotFound: "用户不存在",
 		CodeUserDisabled: "用户已禁用",
+		CodeUserSuspended: "用户已暂停使用",
+		CodeUserExpired:   "账户已过期",
+		CodeUserPending:   "用户待审核",
 
 		// 用户操作内部错误
 		CodePasswordHashFailed:   "密码加密失败",
@@ -224,6 +274,24 @@ func GetBusinessCodeMessage(code int) string {
 		CodeSessionDeleteFailed:       "删除会话失败",
 		CodeSessionServiceUnavailable: "会话服务不可用",
 		CodeTokenBlacklistFailed:      "添加令牌到黑名单失败",
+		CodeSessionNotFound:           "会话不存在",
+		CodeSessionListFailed:         "获取会话列表失败",
+		CodeSessionRevokeFailed:       "吊销会话失败",
+		CodeRefreshTokenReused:        "刷新令牌已失效，请重新登录",
+		CodeTwoFactorSetupFailed:      "设置两步验证失败",
+		CodeTwoFactorQueryFailed:      "查询两步验证信息失败",
+		CodeTwoFactorResetFailed:      "重置两步验证失败",
+		CodeUserSessionsNotRevoked:    "账户已更新，但吊销会话失败，请重新强制下线该用户",
+
+		// 批量导入导出
+		CodeUserImportFileInvalid: "导入文件无效",
+		CodeUserImportFailed:      "批量导入用户失败",
+		CodeUserExportFailed:      "导出用户失败",
+		CodeUserExportTooLarge:    "导出数据量超过上限，请缩小筛选范围",
+
+		// 个人资料
+		CodeAvatarInvalid:      "头像图片无效",
+		CodeAvatarUploadFailed: "上传头像失败",
 
 		// 角色权限模块
 		CodeRoleNotFound: "角色不存在",
@@ -266,6 +334,8 @@ func GetBusinessCodeMessage(code int) string {
 		// 系统模块
 		CodeRateLimitExceeded: "请求过于频繁",
 		CodeIPBlocked:         "IP被限制",
+		CodeRateLimitQueryFailed: "查询限流计数失败",
+		CodeRateLimitClearFailed: "清除限流计数失败",
 
 		CodeInternalError: "内部服务错误",
 		CodeDatabaseError: "数据库错误",
@@ -281,6 +351,16 @@ func GetBusinessCodeMessage(code int) string {
 		CodeResourceNotFound: "资源不存在",
 		CodeResourceConflict: "资源冲突",
 
+		CodeSecurityPolicyGetFailed:    "获取安全策略失败",
+		CodeSecurityPolicyUpdateFailed: "更新安全策略失败",
+		CodeLoginLockQueryFailed:       "查询登录锁定状态失败",
+		CodeLoginLockClearFailed:       "解除登录锁定失败",
+
+		CodeRecycleBinNotFound:      "回收站中不存在该记录",
+		CodeRecycleBinListFailed:    "查询回收站失败",
+		CodeRecycleBinRestoreFailed: "恢复记录失败",
+		CodeRecycleBinPurgeFailed:   "彻底删除记录失败",
+
 		// 字典模块
 		CodeDictTypeNotFound: "字典类型不存在",
 		CodeDictTypeExists:   "字典类型代码已存在",
@@ -349,6 +429,8 @@ func GetBusinessCodeMessage(code int) string {
 		CodeAuditLogGetFailed:   "获取审计日志失败",
 		CodeAuditLogQueryFailed: "查询审计日志失败",
 		CodeAuditLogCleanFailed: "清理审计日志失败",
+		CodeAuditLogExportFailed: "导出审计日志失败",
+		CodeAuditLogVerifyFailed: "校验审计日志失败",
 	}
 
 	if msg, ok := messages[code]; ok {
@@ -324,6 +324,75 @@ func NewUserNotFoundErrorWithCode() *AppError {
 	}
 }
 
+// NewUserDisabledError 用户已禁用
+func NewUserDisabledError() *AppError {
+	code := CodeUserDisabled
+	return &AppError{
+		Type:         ErrorTypeAccountDisabled,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         403,
+		BusinessCode: code,
+	}
+}
+
+// NewUserSuspendedError 用户已暂停
+func NewUserSuspendedError() *AppError {
+	code := CodeUserSuspended
+	return &AppError{
+		Type:         ErrorTypeAccountDisabled,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         403,
+		BusinessCode: code,
+	}
+}
+
+// NewUserExpiredError 账户已过期
+func NewUserExpiredError() *AppError {
+	code := CodeUserExpired
+	return &AppError{
+		Type:         ErrorTypeAccountDisabled,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         403,
+		BusinessCode: code,
+	}
+}
+
+// NewUserPendingError 用户待审核
+func NewUserPendingError() *AppError {
+	code := CodeUserPending
+	return &AppError{
+		Type:         ErrorTypeAccountDisabled,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         403,
+		BusinessCode: code,
+	}
+}
+
+// NewPasswordChangeRequiredError 需要先修改密码
+func NewPasswordChangeRequiredError() *AppError {
+	code := CodePasswordChangeRequired
+	return &AppError{
+		Type:         ErrorTypePermissionDenied,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         403,
+		BusinessCode: code,
+	}
+}
+
+// NewWeakPasswordError 密码不符合密码策略，message 说明具体原因
+func NewWeakPasswordError(message string) *AppError {
+	code := CodeWeakPassword
+	if message == "" {
+		message = GetBusinessCodeMessage(code)
+	}
+	return &AppError{
+		Type:         ErrorTypeValidation,
+		Message:      message,
+		Code:         400,
+		BusinessCode: code,
+	}
+}
+
 // ========== 用户操作内部错误 ==========
 
 // NewPasswordHashFailedError 密码加密失败
@@ -504,6 +573,215 @@ func NewTokenBlacklistFailedError() *AppError {
 	}
 }
 
+// NewSessionNotFoundError 会话不存在
+func NewSessionNotFoundError() *AppError {
+	code := CodeSessionNotFound
+	return &AppError{
+		Type:         ErrorTypeNotFound,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         404,
+		BusinessCode: code,
+	}
+}
+
+// NewSessionListFailedError 获取会话列表失败
+func NewSessionListFailedError() *AppError {
+	code := CodeSessionListFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewSessionRevokeFailedError 吊销会话失败
+func NewSessionRevokeFailedError() *AppError {
+	code := CodeSessionRevokeFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewRefreshTokenReusedError 刷新令牌重复使用（会话已被吊销）
+func NewRefreshTokenReusedError() *AppError {
+	code := CodeRefreshTokenReused
+	return &AppError{
+		Type:         ErrorTypeUnauthorized,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         401,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorInvalidCodeError 两步验证码错误
+func NewTwoFactorInvalidCodeError() *AppError {
+	code := CodeTwoFactorInvalidCode
+	return &AppError{
+		Type:         ErrorTypeInvalidCredentials,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         401,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorChallengeInvalidError 两步验证挑战无效或已过期
+func NewTwoFactorChallengeInvalidError() *AppError {
+	code := CodeTwoFactorChallengeInvalid
+	return &AppError{
+		Type:         ErrorTypeInvalidToken,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         401,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorAlreadyEnabledError 已启用两步验证
+func NewTwoFactorAlreadyEnabledError() *AppError {
+	code := CodeTwoFactorAlreadyEnabled
+	return &AppError{
+		Type:         ErrorTypeConflict,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         409,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorNotEnrolledError 未绑定两步验证
+func NewTwoFactorNotEnrolledError() *AppError {
+	code := CodeTwoFactorNotEnrolled
+	return &AppError{
+		Type:         ErrorTypeValidation,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         400,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorSetupFailedError 设置两步验证失败
+func NewTwoFactorSetupFailedError() *AppError {
+	code := CodeTwoFactorSetupFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorQueryFailedError 查询两步验证信息失败
+func NewTwoFactorQueryFailedError() *AppError {
+	code := CodeTwoFactorQueryFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewTwoFactorResetFailedError 重置两步验证失败
+func NewTwoFactorResetFailedError() *AppError {
+	code := CodeTwoFactorResetFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewUserSessionsNotRevokedError 账户已停用但吊销会话失败
+func NewUserSessionsNotRevokedError() *AppError {
+	code := CodeUserSessionsNotRevoked
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// ========== 批量导入导出 ==========
+
+// NewUserImportFileInvalidError 导入文件无效，message 说明具体原因
+func NewUserImportFileInvalidError(message string) *AppError {
+	code := CodeUserImportFileInvalid
+	if message == "" {
+		message = GetBusinessCodeMessage(code)
+	}
+	return &AppError{
+		Type:         ErrorTypeValidation,
+		Message:      message,
+		Code:         400,
+		BusinessCode: code,
+	}
+}
+
+// NewUserImportFailedError 批量导入用户失败
+func NewUserImportFailedError() *AppError {
+	code := CodeUserImportFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewUserExportFailedError 导出用户失败
+func NewUserExportFailedError() *AppError {
+	code := CodeUserExportFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewUserExportTooLargeError 导出数据量超过上限
+func NewUserExportTooLargeError(message string) *AppError {
+	code := CodeUserExportTooLarge
+	if message == "" {
+		message = GetBusinessCodeMessage(code)
+	}
+	return &AppError{
+		Type:         ErrorTypeValidation,
+		Message:      message,
+		Code:         400,
+		BusinessCode: code,
+	}
+}
+
+// NewAvatarInvalidError 头像图片无效
+func NewAvatarInvalidError(message string) *AppError {
+	code := CodeAvatarInvalid
+	if message == "" {
+		message = GetBusinessCodeMessage(code)
+	}
+	return &AppError{
+		Type:         ErrorTypeValidation,
+		Message:      message,
+		Code:         400,
+		BusinessCode: code,
+	}
+}
+
+// NewAvatarUploadFailedError 上传头像失败
+func NewAvatarUploadFailedError() *AppError {
+	code := CodeAvatarUploadFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
 // NewInvalidCredentialsErrorWithCode 用户名或密码错误（带业务码）
 func NewInvalidCredentialsErrorWithCode(message string) *AppError {
 	code := CodeInvalidCredentials
@@ -590,6 +868,119 @@ func NewRateLimitErrorWithCode(message string) *AppError {
 	}
 }
 
+// NewRateLimitQueryFailedError 查询限流计数失败
+func NewRateLimitQueryFailedError() *AppError {
+	code := CodeRateLimitQueryFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewRateLimitClearFailedError 清除限流计数失败
+func NewRateLimitClearFailedError() *AppError {
+	code := CodeRateLimitClearFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewSecurityPolicyGetFailedError 获取安全策略失败
+func NewSecurityPolicyGetFailedError() *AppError {
+	code := CodeSecurityPolicyGetFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewSecurityPolicyUpdateFailedError 更新安全策略失败
+func NewSecurityPolicyUpdateFailedError() *AppError {
+	code := CodeSecurityPolicyUpdateFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewLoginLockQueryFailedError 查询登录锁定状态失败
+func NewLoginLockQueryFailedError() *AppError {
+	code := CodeLoginLockQueryFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewLoginLockClearFailedError 解除登录锁定失败
+func NewLoginLockClearFailedError() *AppError {
+	code := CodeLoginLockClearFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewRecycleBinNotFoundError 回收站中不存在该记录（记录不存在或未被删除），message 说明具体记录
+func NewRecycleBinNotFoundError(message string) *AppError {
+	code := CodeRecycleBinNotFound
+	if message == "" {
+		message = GetBusinessCodeMessage(code)
+	}
+	return &AppError{
+		Type:         ErrorTypeNotFound,
+		Message:      message,
+		Code:         404,
+		BusinessCode: code,
+	}
+}
+
+// NewRecycleBinListFailedError 查询回收站失败
+func NewRecycleBinListFailedError() *AppError {
+	code := CodeRecycleBinListFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewRecycleBinRestoreFailedError 恢复记录失败
+func NewRecycleBinRestoreFailedError() *AppError {
+	code := CodeRecycleBinRestoreFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewRecycleBinPurgeFailedError 彻底删除记录失败
+func NewRecycleBinPurgeFailedError() *AppError {
+	code := CodeRecycleBinPurgeFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
 // NewInternalErrorWithCode 内部服务错误（带业务码）
 func NewInternalErrorWithCode(message string) *AppError {
 	code := CodeInternalError
@@ -1529,3 +1920,25 @@ func NewAuditLogCleanFailedError() *AppError {
 		BusinessCode: code,
 	}
 }
+
+// NewAuditLogExportFailedError 导出审计日志失败
+func NewAuditLogExportFailedError() *AppError {
+	code := CodeAuditLogExportFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
+
+// NewAuditLogVerifyFailedError 校验审计日志失败
+func NewAuditLogVerifyFailedError() *AppError {
+	code := CodeAuditLogVerifyFailed
+	return &AppError{
+		Type:         ErrorTypeInternal,
+		Message:      GetBusinessCodeMessage(code),
+		Code:         500,
+		BusinessCode: code,
+	}
+}
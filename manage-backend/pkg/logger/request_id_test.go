@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	Logger = zap.New(core)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	FromContext(context.Background()).Info("without request id")
+	FromContext(ctx).Info("with request id")
+
+	// 请求中另起的协程传入同一个 context 即可带上请求ID
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		FromContext(ctx).Info("other goroutine")
+	}()
+	<-done
+
+	FromContext(nil).Info("nil context")
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 4)
+	assert.NotContains(t, entries[0].ContextMap(), "request_id")
+	assert.Equal(t, "req-1", entries[1].ContextMap()["request_id"])
+	assert.Equal(t, "req-1", entries[2].ContextMap()["request_id"])
+	assert.NotContains(t, entries[3].ContextMap(), "request_id")
+}
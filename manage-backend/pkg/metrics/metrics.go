@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "manage"
+
+// Registry 应用指标注册表
+// 使用独立的注册表而不是全局默认注册表，只暴露本应用和 Go 运行时的指标
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// HTTP 请求指标
+var (
+	HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求处理耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// 登录结果
+const (
+	LoginResultSuccess           = "success"            // 登录成功并签发令牌
+	LoginResultTwoFactorRequired = "two_factor_required" // 密码验证通过，等待两步验证
+	LoginResultFailure           = "failure"            // 登录失败
+)
+
+// 登录失败原因
+const (
+	LoginReasonRateLimited      = "rate_limited"       // IP 请求频率超限
+	LoginReasonAccountLocked    = "account_locked"     // 账户已锁定
+	LoginReasonAccountDisabled  = "account_disabled"   // 账户已禁用、暂停、待审核或已过期
+	LoginReasonInvalidCaptcha   = "invalid_captcha"    // 验证码错误
+	LoginReasonUserNotFound     = "user_not_found"     // 用户不存在
+	LoginReasonInvalidPassword  = "invalid_password"   // 密码错误
+	LoginReasonInvalidTwoFactor = "invalid_two_factor" // 两步验证码错误或挑战无效
+	LoginReasonInternalError    = "internal_error"     // 查询用户、创建会话等内部错误
+)
+
+// 认证与业务事件指标
+var (
+	LoginAttemptsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "登录尝试次数，按结果和失败原因统计",
+	}, []string{"result", "reason"})
+
+	AccountLockoutsTotal = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "account_lockouts_total",
+		Help:      "账户因连续登录失败被锁定的次数",
+	})
+
+	CaptchaGeneratedTotal = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "captcha_generated_total",
+		Help:      "生成的验证码数量",
+	})
+
+	CaptchaVerificationsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "captcha_verifications_total",
+		Help:      "验证码校验次数，按结果统计",
+	}, []string{"result"})
+
+	PasswordResetEmailsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "password_reset_emails_total",
+		Help:      "密码重置邮件发送次数，按结果统计",
+	}, []string{"result"})
+
+	CasbinDecisionsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "casbin_decisions_total",
+		Help:      "Casbin 接口鉴权决策次数，按结果统计",
+	}, []string{"result"})
+
+	RateLimitRejectedTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejected_total",
+		Help:      "因接口限流被拒绝的请求数，按路由分组统计",
+	}, []string{"group"})
+)
+
+// RecordLoginSuccess 记录登录成功
+func RecordLoginSuccess() {
+	LoginAttemptsTotal.WithLabelValues(LoginResultSuccess, "").Inc()
+}
+
+// RecordLoginTwoFactorRequired 记录密码验证通过、等待两步验证的登录
+func RecordLoginTwoFactorRequired() {
+	LoginAttemptsTotal.WithLabelValues(LoginResultTwoFactorRequired, "").Inc()
+}
+
+// RecordLoginFailure 记录登录失败及原因
+func RecordLoginFailure(reason string) {
+	LoginAttemptsTotal.WithLabelValues(LoginResultFailure, reason).Inc()
+}
+
+// RegisterAuditQueue 注册审计日志写入队列的指标
+// queued 返回当前排队条数，written、dropped、failed 返回累计写入、丢弃和写入失败的条数
+func RegisterAuditQueue(queued, written, dropped, failed func() float64) {
+	Registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "audit_log_queue_depth",
+			Help:      "审计日志写入队列中排队的条数",
+		}, queued),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "audit_log_written_total",
+			Help:      "已写入数据库的审计日志条数",
+		}, written),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "audit_log_dropped_total",
+			Help:      "因队列已满或已关闭而丢弃的审计日志条数",
+		}, dropped),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "audit_log_write_failed_total",
+			Help:      "写入数据库失败的审计日志条数",
+		}, failed),
+	)
+}
+
+// Handler 返回 Prometheus 文本格式的指标接口
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorageSaveAndDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "/uploads/")
+
+	url, err := s.Save(ctx, "avatars/1/a.png", strings.NewReader("first"), "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/avatars/1/a.png", url)
+
+	_, err = s.Save(ctx, "avatars/1/a.png", strings.NewReader("second"), "image/png")
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "avatars", "1", "a.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	// 临时文件在重命名后不会残留
+	entries, err := os.ReadDir(filepath.Join(dir, "avatars", "1"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, s.Delete(ctx, "avatars/1/a.png"))
+	_, err = os.Stat(filepath.Join(dir, "avatars", "1", "a.png"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// 重复删除不报错
+	assert.NoError(t, s.Delete(ctx, "avatars/1/a.png"))
+}
+
+func TestLocalStorageRejectsInvalidKey(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := NewLocalStorage(filepath.Join(dir, "root"), "/uploads")
+
+	for _, key := range []string{"", ".", "/etc/passwd", "../outside.png", "avatars/../../outside.png", `avatars\..\x.png`} {
+		_, err := s.Save(ctx, key, strings.NewReader("data"), "image/png")
+		assert.ErrorIs(t, err, ErrInvalidKey, "key=%q", key)
+		assert.ErrorIs(t, s.Delete(ctx, key), ErrInvalidKey, "key=%q", key)
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "outside.png"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+)
+
+// ErrInvalidKey 文件键为空、是绝对路径或包含 ..
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// Storage 文件存储接口
+// key 为以 / 分隔的相对路径，如 avatars/1/1700000000.png
+type Storage interface {
+	// Save 保存文件，已存在时覆盖，返回文件的访问 URL
+	Save(ctx context.Context, key string, r io.Reader, contentType string) (string, error)
+	// Delete 删除文件，文件不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// New 根据配置创建文件存储，未配置类型时使用本地磁盘
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Type {
+	case "", config.StorageTypeLocal:
+		return NewLocalStorage(cfg.Local.Dir, cfg.Local.BaseURL), nil
+	default:
+		return nil, fmt.Errorf("storage: unsupported type %q", cfg.Type)
+	}
+}
+
+// cleanKey 规范化文件键，拒绝可能访问存储目录之外的键
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// 链路导出方式
+const (
+	ExporterOTLP   = "otlp"   // OTLP/HTTP，发送到 Collector、Jaeger、Tempo 等
+	ExporterStdout = "stdout" // 打印到控制台，用于本地开发
+)
+
+const instrumentationName = "github.com/XIAOZHUXUEJAVA/go-manage-starter/manage-backend"
+
+// enabled 是否已启用链路追踪
+var enabled atomic.Bool
+
+// noopSpan 未启用链路追踪时 Start 返回的空 span
+var noopSpan = &Span{Span: noop.Span{}}
+
+// Init 初始化链路追踪，返回关闭函数（退出前调用以导出剩余的 span）
+// 未启用时 Start 直接返回空 span，各处埋点不产生开销
+func Init(cfg config.TracingConfig, environment string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterOTLP, "":
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("deployment.environment", environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled.Store(true)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer 返回应用的 Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Span 应用内部的 span
+type Span struct {
+	trace.Span
+}
+
+// Start 创建子 span，返回的 context 需继续传给 Redis 操作和仓库层（GORM 通过 db.WithContext），
+// 期间的 SQL、Redis 操作才会成为它的子 span。未启用链路追踪时原样返回 ctx 和空 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, *Span) {
+	if !enabled.Load() {
+		return ctx, noopSpan
+	}
+	ctx, span := Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, &Span{Span: span}
+}
+
+// Fail 记录错误并将 span 标记为失败
+func (s *Span) Fail(err error) {
+	if err == nil {
+		return
+	}
+	s.RecordError(err)
+	s.SetStatus(codes.Error, err.Error())
+}
+
+// hasParent 判断调用方传入的 context 中是否有 span，没有时不创建孤立的根 span
+func hasParent(ctx context.Context) bool {
+	return ctx != nil && trace.SpanContextFromContext(ctx).IsValid()
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	enabled.Store(true)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		enabled.Store(false)
+	})
+	return recorder
+}
+
+func TestStartDisabled(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := Start(ctx, "disabled")
+	defer span.End()
+
+	assert.Equal(t, ctx, spanCtx, "未启用时应原样返回 context")
+	assert.False(t, span.SpanContext().IsValid())
+	assert.False(t, hasParent(spanCtx))
+}
+
+func TestStartCreatesChildOfExplicitContext(t *testing.T) {
+	recorder := newRecorder(t)
+
+	assert.False(t, hasParent(context.Background()), "没有 span 时不应创建孤立的根 span")
+
+	ctx, outer := Start(context.Background(), "outer")
+	innerCtx, inner := Start(ctx, "inner")
+	require.True(t, hasParent(innerCtx))
+
+	inner.End()
+	outer.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "inner", spans[0].Name())
+	assert.Equal(t, outer.SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	// 没有传入 context 的操作不会挂到其他请求的 span 下
+	assert.False(t, hasParent(context.Background()))
+}
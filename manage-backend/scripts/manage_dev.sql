@@ -16,6 +16,17 @@
 */
 
 
+-- ----------------------------
+-- Sequence structure for audit_log_checkpoints_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "manage_dev"."audit_log_checkpoints_id_seq" CASCADE;
+CREATE SEQUENCE "manage_dev"."audit_log_checkpoints_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for audit_logs_id_seq
 -- ----------------------------
@@ -82,6 +93,17 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for password_histories_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "manage_dev"."password_histories_id_seq" CASCADE;
+CREATE SEQUENCE "manage_dev"."password_histories_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for password_reset_tokens_id_seq
 -- ----------------------------
@@ -137,6 +159,17 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Sequence structure for user_two_factors_id_seq
+-- ----------------------------
+DROP SEQUENCE IF EXISTS "manage_dev"."user_two_factors_id_seq" CASCADE;
+CREATE SEQUENCE "manage_dev"."user_two_factors_id_seq" 
+INCREMENT 1
+MINVALUE  1
+MAXVALUE 9223372036854775807
+START 1
+CACHE 1;
+
 -- ----------------------------
 -- Sequence structure for users_id_seq
 -- ----------------------------
@@ -148,6 +181,29 @@ MAXVALUE 9223372036854775807
 START 1
 CACHE 1;
 
+-- ----------------------------
+-- Table structure for audit_log_checkpoints
+-- ----------------------------
+DROP TABLE IF EXISTS "manage_dev"."audit_log_checkpoints" CASCADE;
+CREATE TABLE "manage_dev"."audit_log_checkpoints" (
+  "id" int8 NOT NULL DEFAULT nextval('"manage_dev".audit_log_checkpoints_id_seq'::regclass),
+  "last_entry_id" int8 NOT NULL,
+  "last_entry_hash" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "last_entry_at" timestamptz(6) NOT NULL,
+  "purged_count" int8 NOT NULL,
+  "cutoff" timestamptz(6) NOT NULL,
+  "signature" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamptz(6)
+)
+;
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."last_entry_id" IS '被清理的最后一条日志ID';
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."last_entry_hash" IS '被清理的最后一条日志哈希';
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."last_entry_at" IS '被清理的最后一条日志时间';
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."purged_count" IS '清理条数';
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."cutoff" IS '清理截止时间';
+COMMENT ON COLUMN "manage_dev"."audit_log_checkpoints"."signature" IS 'HMAC-SHA256 签名';
+COMMENT ON TABLE "manage_dev"."audit_log_checkpoints" IS '审计日志清理检查点表';
+
 -- ----------------------------
 -- Table structure for audit_logs
 -- ----------------------------
@@ -168,7 +224,10 @@ CREATE TABLE "manage_dev"."audit_logs" (
   "request_body" text COLLATE "pg_catalog"."default",
   "duration" int8,
   "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
-  "deleted_at" timestamptz(6)
+  "deleted_at" timestamptz(6),
+  "prev_hash" varchar(64) COLLATE "pg_catalog"."default" NOT NULL DEFAULT ''::character varying,
+  "hash" varchar(64) COLLATE "pg_catalog"."default" NOT NULL DEFAULT ''::character varying,
+  "request_id" varchar(64) COLLATE "pg_catalog"."default" NOT NULL DEFAULT ''::character varying
 )
 ;
 COMMENT ON COLUMN "manage_dev"."audit_logs"."user_id" IS '用户ID';
@@ -184,6 +243,9 @@ COMMENT ON COLUMN "manage_dev"."audit_logs"."status" IS 'HTTP状态码';
 COMMENT ON COLUMN "manage_dev"."audit_logs"."error_msg" IS '错误信息';
 COMMENT ON COLUMN "manage_dev"."audit_logs"."request_body" IS '请求体';
 COMMENT ON COLUMN "manage_dev"."audit_logs"."duration" IS '请求耗时（毫秒）';
+COMMENT ON COLUMN "manage_dev"."audit_logs"."prev_hash" IS '上一条日志哈希';
+COMMENT ON COLUMN "manage_dev"."audit_logs"."hash" IS '本条日志哈希';
+COMMENT ON COLUMN "manage_dev"."audit_logs"."request_id" IS '请求ID';
 COMMENT ON TABLE "manage_dev"."audit_logs" IS '审计日志表';
 
 -- ----------------------------
@@ -302,6 +364,35 @@ INSERT INTO "manage_dev"."casbin_rule" VALUES (68, 'p', 'role:admin', '/dict-ite
 INSERT INTO "manage_dev"."casbin_rule" VALUES (69, 'p', 'role:admin', '/dict-items/:id', 'DELETE', '', '', '');
 INSERT INTO "manage_dev"."casbin_rule" VALUES (70, 'p', 'role:admin', '/audit-logs', 'GET', '', '', '');
 INSERT INTO "manage_dev"."casbin_rule" VALUES (71, 'p', 'role:admin', '/audit-logs/clean', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (72, 'p', 'role:admin', '/audit-logs/export', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (73, 'p', 'role:admin', '/audit-logs/verify', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (74, 'p', 'role:admin', '/rate-limits', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (75, 'p', 'role:admin', '/rate-limits', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (76, 'p', 'role:admin', '/security/policy', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (77, 'p', 'role:admin', '/security/policy', 'PUT', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (78, 'p', 'role:admin', '/login-locks/accounts', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (79, 'p', 'role:admin', '/login-locks/accounts/:username', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (80, 'p', 'role:admin', '/login-locks/accounts/:username/failures', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (81, 'p', 'role:admin', '/login-locks/ips', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (82, 'p', 'role:admin', '/login-locks/ips/:ip', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (83, 'p', 'role:admin', '/users/import', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (84, 'p', 'role:admin', '/users/export', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (85, 'p', 'role:admin', '/users/batch', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (86, 'p', 'role:admin', '/users/deleted', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (87, 'p', 'role:admin', '/users/:id/restore', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (88, 'p', 'role:admin', '/users/:id/purge', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (89, 'p', 'role:admin', '/roles/deleted', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (90, 'p', 'role:admin', '/roles/:id/restore', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (91, 'p', 'role:admin', '/roles/:id/purge', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (92, 'p', 'role:admin', '/permissions/deleted', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (93, 'p', 'role:admin', '/permissions/:id/restore', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (94, 'p', 'role:admin', '/permissions/:id/purge', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (95, 'p', 'role:admin', '/menus/deleted', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (96, 'p', 'role:admin', '/menus/:id/restore', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (97, 'p', 'role:admin', '/menus/:id/purge', 'DELETE', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (98, 'p', 'role:admin', '/dict-types/deleted', 'GET', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (99, 'p', 'role:admin', '/dict-types/:id/restore', 'POST', '', '', '');
+INSERT INTO "manage_dev"."casbin_rule" VALUES (100, 'p', 'role:admin', '/dict-types/:id/purge', 'DELETE', '', '', '');
 
 -- ----------------------------
 -- Table structure for dict_items
@@ -347,6 +438,7 @@ INSERT INTO "manage_dev"."dict_items" VALUES (8, 'common_status', '启用', 'act
 INSERT INTO "manage_dev"."dict_items" VALUES (9, 'common_status', '禁用', 'inactive', '{"color": "red"}', '禁用状态', 'active', 2, 'f', 't', '2025-10-13 15:38:32.332429+08', '2025-10-13 15:38:32.332429+08', NULL);
 INSERT INTO "manage_dev"."dict_items" VALUES (10, 'yes_no', '是', 'yes', '{"color": "green"}', '是', 'active', 1, 'f', 't', '2025-10-13 15:38:32.332429+08', '2025-10-13 15:38:32.332429+08', NULL);
 INSERT INTO "manage_dev"."dict_items" VALUES (11, 'yes_no', '否', 'no', '{"color": "gray"}', '否', 'active', 2, 't', 't', '2025-10-13 15:38:32.332429+08', '2025-10-13 15:38:32.332429+08', NULL);
+INSERT INTO "manage_dev"."dict_items" VALUES (12, 'user_status', '已暂停', 'suspended', '{"badge": "warning", "color": "gold"}', '用户账户暂停使用', 'active', 5, 'f', 't', '2025-10-13 15:38:32.332429+08', '2025-10-13 15:38:32.332429+08', NULL);
 
 -- ----------------------------
 -- Table structure for dict_types
@@ -453,6 +545,20 @@ INSERT INTO "manage_dev"."migration_records" VALUES (2, '002_create_rbac_tables'
 INSERT INTO "manage_dev"."migration_records" VALUES (3, '003_create_audit_logs_table', '2025-10-13 15:38:32.324239+08');
 INSERT INTO "manage_dev"."migration_records" VALUES (4, '004_create_dict_tables', '2025-10-13 15:38:32.384307+08');
 
+-- ----------------------------
+-- Table structure for password_histories
+-- ----------------------------
+DROP TABLE IF EXISTS "manage_dev"."password_histories" CASCADE;
+CREATE TABLE "manage_dev"."password_histories" (
+  "id" int8 NOT NULL DEFAULT nextval('"manage_dev".password_histories_id_seq'::regclass),
+  "user_id" int8 NOT NULL,
+  "password_hash" varchar(255) COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamptz(6)
+)
+;
+COMMENT ON COLUMN "manage_dev"."password_histories"."password_hash" IS '被替换的旧密码（bcrypt哈希）';
+COMMENT ON TABLE "manage_dev"."password_histories" IS '密码历史表';
+
 -- ----------------------------
 -- Table structure for password_reset_tokens
 -- ----------------------------
@@ -546,6 +652,35 @@ INSERT INTO "manage_dev"."permissions" VALUES (14, '创建菜单', 'menu:create'
 INSERT INTO "manage_dev"."permissions" VALUES (31, '测试权限', 'test:test', 'test', 'test', '', '', '', 'api', 'active', '2025-10-20 11:17:57.572313+08', '2025-10-20 11:17:57.572313+08', '2025-10-20 11:18:09.200203+08');
 INSERT INTO "manage_dev"."permissions" VALUES (32, '测试测出', 'test:test2', 'test', 'test2', '', '', '', 'api', 'active', '2025-10-20 11:57:14.8564+08', '2025-10-20 11:57:14.8564+08', '2025-10-20 11:57:37.114172+08');
 INSERT INTO "manage_dev"."permissions" VALUES (33, '测试测试测试1', 'test:test8', 'test', 'test8', '', '', '', 'api', 'active', '2025-10-20 15:58:51.694298+08', '2025-10-20 15:59:07.841682+08', '2025-10-20 15:59:13.278167+08');
+INSERT INTO "manage_dev"."permissions" VALUES (34, '导出日志', 'logs:export', 'logs', 'export', '/audit-logs/export', 'GET', '', 'api', 'active', '2025-10-21 10:00:00+08', '2025-10-21 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (35, '校验日志', 'logs:verify', 'logs', 'verify', '/audit-logs/verify', 'GET', '', 'api', 'active', '2025-10-21 10:00:00+08', '2025-10-21 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (36, '查看限流', 'ratelimit:view', 'ratelimit', 'view', '/rate-limits', 'GET', '', 'api', 'active', '2025-10-22 10:00:00+08', '2025-10-22 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (37, '清除限流', 'ratelimit:clear', 'ratelimit', 'clear', '/rate-limits', 'DELETE', '', 'api', 'active', '2025-10-22 10:00:00+08', '2025-10-22 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (38, '查看安全策略', 'security:view', 'security', 'view', '/security/policy', 'GET', '', 'api', 'active', '2025-10-22 10:00:00+08', '2025-10-22 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (39, '修改安全策略', 'security:update', 'security', 'update', '/security/policy', 'PUT', '', 'api', 'active', '2025-10-22 10:00:00+08', '2025-10-22 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (40, '查看锁定账户', 'login_lock:view_account', 'login_lock', 'view_account', '/login-locks/accounts', 'GET', '', 'api', 'active', '2025-10-23 10:00:00+08', '2025-10-23 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (41, '解除账户锁定', 'login_lock:unlock_account', 'login_lock', 'unlock_account', '/login-locks/accounts/:username', 'DELETE', '', 'api', 'active', '2025-10-23 10:00:00+08', '2025-10-23 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (42, '重置登录失败次数', 'login_lock:reset_failures', 'login_lock', 'reset_failures', '/login-locks/accounts/:username/failures', 'DELETE', '', 'api', 'active', '2025-10-23 10:00:00+08', '2025-10-23 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (43, '查看封禁 IP', 'login_lock:view_ip', 'login_lock', 'view_ip', '/login-locks/ips', 'GET', '', 'api', 'active', '2025-10-23 10:00:00+08', '2025-10-23 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (44, '解除 IP 封禁', 'login_lock:unblock_ip', 'login_lock', 'unblock_ip', '/login-locks/ips/:ip', 'DELETE', '', 'api', 'active', '2025-10-23 10:00:00+08', '2025-10-23 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (45, '批量导入用户', 'user:import', 'user', 'import', '/users/import', 'POST', '', 'api', 'active', '2025-10-24 10:00:00+08', '2025-10-24 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (46, '导出用户', 'user:export', 'user', 'export', '/users/export', 'GET', '', 'api', 'active', '2025-10-24 10:00:00+08', '2025-10-24 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (47, '批量操作用户', 'user:batch', 'user', 'batch', '/users/batch', 'POST', '', 'api', 'active', '2025-10-24 10:00:00+08', '2025-10-24 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (48, '查看已删除用户', 'user:recycle', 'user', 'recycle', '/users/deleted', 'GET', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (49, '恢复用户', 'user:restore', 'user', 'restore', '/users/:id/restore', 'POST', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (50, '彻底删除用户', 'user:purge', 'user', 'purge', '/users/:id/purge', 'DELETE', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (51, '查看已删除角色', 'role:recycle', 'role', 'recycle', '/roles/deleted', 'GET', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (52, '恢复角色', 'role:restore', 'role', 'restore', '/roles/:id/restore', 'POST', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (53, '彻底删除角色', 'role:purge', 'role', 'purge', '/roles/:id/purge', 'DELETE', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (54, '查看已删除权限', 'permission:recycle', 'permission', 'recycle', '/permissions/deleted', 'GET', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (55, '恢复权限', 'permission:restore', 'permission', 'restore', '/permissions/:id/restore', 'POST', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (56, '彻底删除权限', 'permission:purge', 'permission', 'purge', '/permissions/:id/purge', 'DELETE', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (57, '查看已删除菜单', 'menu:recycle', 'menu', 'recycle', '/menus/deleted', 'GET', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (58, '恢复菜单', 'menu:restore', 'menu', 'restore', '/menus/:id/restore', 'POST', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (59, '彻底删除菜单', 'menu:purge', 'menu', 'purge', '/menus/:id/purge', 'DELETE', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (60, '查看已删除字典类型', 'dict_type:recycle', 'dict_type', 'recycle', '/dict-types/deleted', 'GET', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (61, '恢复字典类型', 'dict_type:restore', 'dict_type', 'restore', '/dict-types/:id/restore', 'POST', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
+INSERT INTO "manage_dev"."permissions" VALUES (62, '彻底删除字典类型', 'dict_type:purge', 'dict_type', 'purge', '/dict-types/:id/purge', 'DELETE', '', 'api', 'active', '2025-10-25 10:00:00+08', '2025-10-25 10:00:00+08', NULL);
 
 -- ----------------------------
 -- Table structure for role_permissions
@@ -602,6 +737,35 @@ INSERT INTO "manage_dev"."role_permissions" VALUES (85, 1, 3, '2025-10-18 11:21:
 INSERT INTO "manage_dev"."role_permissions" VALUES (86, 1, 28, '2025-10-18 11:21:25.296831+08');
 INSERT INTO "manage_dev"."role_permissions" VALUES (87, 1, 29, '2025-10-18 11:21:25.296831+08');
 INSERT INTO "manage_dev"."role_permissions" VALUES (88, 1, 30, '2025-10-18 11:21:25.296831+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (89, 1, 34, '2025-10-21 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (90, 1, 35, '2025-10-21 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (91, 1, 36, '2025-10-22 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (92, 1, 37, '2025-10-22 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (93, 1, 38, '2025-10-22 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (94, 1, 39, '2025-10-22 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (95, 1, 40, '2025-10-23 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (96, 1, 41, '2025-10-23 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (97, 1, 42, '2025-10-23 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (98, 1, 43, '2025-10-23 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (99, 1, 44, '2025-10-23 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (100, 1, 45, '2025-10-24 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (101, 1, 46, '2025-10-24 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (102, 1, 47, '2025-10-24 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (103, 1, 48, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (104, 1, 49, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (105, 1, 50, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (106, 1, 51, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (107, 1, 52, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (108, 1, 53, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (109, 1, 54, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (110, 1, 55, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (111, 1, 56, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (112, 1, 57, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (113, 1, 58, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (114, 1, 59, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (115, 1, 60, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (116, 1, 61, '2025-10-25 10:00:00+08');
+INSERT INTO "manage_dev"."role_permissions" VALUES (117, 1, 62, '2025-10-25 10:00:00+08');
 
 -- ----------------------------
 -- Table structure for roles
@@ -635,6 +799,22 @@ INSERT INTO "manage_dev"."roles" VALUES (3, '普通用户', 'user', '基础权
 INSERT INTO "manage_dev"."roles" VALUES (4, '测试角色', 'testrole', '', 'active', 'f', '2025-10-20 11:07:16.933402+08', '2025-10-20 11:07:16.933402+08', '2025-10-20 11:07:23.762151+08');
 INSERT INTO "manage_dev"."roles" VALUES (6, '测试角色1', 'testrole', '', 'active', 'f', '2025-10-20 15:46:20.338954+08', '2025-10-20 15:46:27.440498+08', NULL);
 
+-- ----------------------------
+-- Table structure for system_settings
+-- ----------------------------
+DROP TABLE IF EXISTS "manage_dev"."system_settings" CASCADE;
+CREATE TABLE "manage_dev"."system_settings" (
+  "key" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "value" text COLLATE "pg_catalog"."default" NOT NULL,
+  "updated_by" int8 NOT NULL DEFAULT 0,
+  "updated_at" timestamptz(6)
+)
+;
+COMMENT ON COLUMN "manage_dev"."system_settings"."key" IS '设置键';
+COMMENT ON COLUMN "manage_dev"."system_settings"."value" IS '设置值（JSON）';
+COMMENT ON COLUMN "manage_dev"."system_settings"."updated_by" IS '最后修改人ID';
+COMMENT ON TABLE "manage_dev"."system_settings" IS '系统设置表';
+
 -- ----------------------------
 -- Table structure for user_roles
 -- ----------------------------
@@ -666,6 +846,26 @@ INSERT INTO "manage_dev"."user_roles" VALUES (7, 10, 3, '2025-10-20 14:32:45.587
 INSERT INTO "manage_dev"."user_roles" VALUES (8, 11, 3, '2025-10-20 14:48:45.129333+08', 0);
 INSERT INTO "manage_dev"."user_roles" VALUES (9, 12, 3, '2025-10-20 15:10:15.775768+08', 0);
 
+-- ----------------------------
+-- Table structure for user_two_factors
+-- ----------------------------
+DROP TABLE IF EXISTS "manage_dev"."user_two_factors" CASCADE;
+CREATE TABLE "manage_dev"."user_two_factors" (
+  "id" int8 NOT NULL DEFAULT nextval('"manage_dev".user_two_factors_id_seq'::regclass),
+  "user_id" int8 NOT NULL,
+  "secret" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "enabled" bool NOT NULL DEFAULT false,
+  "recovery_codes" text COLLATE "pg_catalog"."default",
+  "confirmed_at" timestamptz(6),
+  "created_at" timestamptz(6),
+  "updated_at" timestamptz(6)
+)
+;
+COMMENT ON COLUMN "manage_dev"."user_two_factors"."secret" IS 'TOTP密钥（Base32）';
+COMMENT ON COLUMN "manage_dev"."user_two_factors"."enabled" IS '是否已确认启用';
+COMMENT ON COLUMN "manage_dev"."user_two_factors"."recovery_codes" IS '恢复码哈希（JSON数组）';
+COMMENT ON TABLE "manage_dev"."user_two_factors" IS '用户两步验证表';
+
 -- ----------------------------
 -- Table structure for users
 -- ----------------------------
@@ -679,7 +879,17 @@ CREATE TABLE "manage_dev"."users" (
   "status" text COLLATE "pg_catalog"."default" DEFAULT 'active'::text,
   "created_at" timestamptz(6),
   "updated_at" timestamptz(6),
-  "deleted_at" timestamptz(6)
+  "deleted_at" timestamptz(6),
+  "expires_at" timestamptz(6),
+  "must_change_password" bool NOT NULL DEFAULT false,
+  "display_name" varchar(50) COLLATE "pg_catalog"."default",
+  "phone" varchar(20) COLLATE "pg_catalog"."default",
+  "department" varchar(100) COLLATE "pg_catalog"."default",
+  "locale" varchar(20) COLLATE "pg_catalog"."default" DEFAULT 'zh-CN'::character varying,
+  "timezone" varchar(50) COLLATE "pg_catalog"."default" DEFAULT 'Asia/Shanghai'::character varying,
+  "avatar_url" varchar(255) COLLATE "pg_catalog"."default",
+  "avatar_key" varchar(255) COLLATE "pg_catalog"."default",
+  "preferences" jsonb
 )
 ;
 COMMENT ON COLUMN "manage_dev"."users"."username" IS '用户名';
@@ -687,6 +897,16 @@ COMMENT ON COLUMN "manage_dev"."users"."email" IS '邮箱';
 COMMENT ON COLUMN "manage_dev"."users"."password" IS '密码（加密）';
 COMMENT ON COLUMN "manage_dev"."users"."role" IS '角色';
 COMMENT ON COLUMN "manage_dev"."users"."status" IS '状态';
+COMMENT ON COLUMN "manage_dev"."users"."expires_at" IS '账户过期时间，为空表示永不过期';
+COMMENT ON COLUMN "manage_dev"."users"."must_change_password" IS '下次登录必须修改密码';
+COMMENT ON COLUMN "manage_dev"."users"."display_name" IS '显示名称';
+COMMENT ON COLUMN "manage_dev"."users"."phone" IS '手机号';
+COMMENT ON COLUMN "manage_dev"."users"."department" IS '部门';
+COMMENT ON COLUMN "manage_dev"."users"."locale" IS '语言';
+COMMENT ON COLUMN "manage_dev"."users"."timezone" IS 'IANA 时区';
+COMMENT ON COLUMN "manage_dev"."users"."avatar_url" IS '头像访问地址';
+COMMENT ON COLUMN "manage_dev"."users"."avatar_key" IS '头像在文件存储中的键';
+COMMENT ON COLUMN "manage_dev"."users"."preferences" IS '个人偏好设置（JSON）';
 COMMENT ON TABLE "manage_dev"."users" IS '用户表';
 
 -- ----------------------------
@@ -701,6 +921,13 @@ INSERT INTO "manage_dev"."users" VALUES (12, '测试信的埃塞挨打', 'xiaozh
 INSERT INTO "manage_dev"."users" VALUES (4, 'manager', 'xiaozhulzq@2925.com', '$2a$10$6DOUbQEThHbOWY99oTbn2.OQ843xrjduFbgz3RgI7TaKG07baMTq6', 'manager', 'active', NULL, NULL, NULL);
 INSERT INTO "manage_dev"."users" VALUES (2, 'user1', 'xiaozhulzq@2925.com', '$2a$10$cYuTag0oSlnO8O/N4mSaIO8Fedq9n3bRpXB71XcgkiZoyrxAzJI5O', 'user', 'active', NULL, '2025-10-23 10:21:08.559969+08', NULL);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "manage_dev"."audit_log_checkpoints_id_seq"
+OWNED BY "manage_dev"."audit_log_checkpoints"."id";
+SELECT setval('"manage_dev"."audit_log_checkpoints_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -713,14 +940,14 @@ SELECT setval('"manage_dev"."audit_logs_id_seq"', 1658, true);
 -- ----------------------------
 ALTER SEQUENCE "manage_dev"."casbin_rule_id_seq"
 OWNED BY "manage_dev"."casbin_rule"."id";
-SELECT setval('"manage_dev"."casbin_rule_id_seq"', 71, true);
+SELECT setval('"manage_dev"."casbin_rule_id_seq"', 100, true);
 
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
 ALTER SEQUENCE "manage_dev"."dict_items_id_seq"
 OWNED BY "manage_dev"."dict_items"."id";
-SELECT setval('"manage_dev"."dict_items_id_seq"', 11, true);
+SELECT setval('"manage_dev"."dict_items_id_seq"', 12, true);
 
 -- ----------------------------
 -- Alter sequences owned by
@@ -746,6 +973,10 @@ SELECT setval('"manage_dev"."migration_records_id_seq"', 4, true);
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
+ALTER SEQUENCE "manage_dev"."password_histories_id_seq"
+OWNED BY "manage_dev"."password_histories"."id";
+SELECT setval('"manage_dev"."password_histories_id_seq"', 1, false);
+
 ALTER SEQUENCE "manage_dev"."password_reset_tokens_id_seq"
 OWNED BY "manage_dev"."password_reset_tokens"."id";
 SELECT setval('"manage_dev"."password_reset_tokens_id_seq"', 7, true);
@@ -755,14 +986,14 @@ SELECT setval('"manage_dev"."password_reset_tokens_id_seq"', 7, true);
 -- ----------------------------
 ALTER SEQUENCE "manage_dev"."permissions_id_seq"
 OWNED BY "manage_dev"."permissions"."id";
-SELECT setval('"manage_dev"."permissions_id_seq"', 33, true);
+SELECT setval('"manage_dev"."permissions_id_seq"', 62, true);
 
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
 ALTER SEQUENCE "manage_dev"."role_permissions_id_seq"
 OWNED BY "manage_dev"."role_permissions"."id";
-SELECT setval('"manage_dev"."role_permissions_id_seq"', 88, true);
+SELECT setval('"manage_dev"."role_permissions_id_seq"', 117, true);
 
 -- ----------------------------
 -- Alter sequences owned by
@@ -778,6 +1009,13 @@ ALTER SEQUENCE "manage_dev"."user_roles_id_seq"
 OWNED BY "manage_dev"."user_roles"."id";
 SELECT setval('"manage_dev"."user_roles_id_seq"', 9, true);
 
+-- ----------------------------
+-- Alter sequences owned by
+-- ----------------------------
+ALTER SEQUENCE "manage_dev"."user_two_factors_id_seq"
+OWNED BY "manage_dev"."user_two_factors"."id";
+SELECT setval('"manage_dev"."user_two_factors_id_seq"', 1, false);
+
 -- ----------------------------
 -- Alter sequences owned by
 -- ----------------------------
@@ -785,6 +1023,18 @@ ALTER SEQUENCE "manage_dev"."users_id_seq"
 OWNED BY "manage_dev"."users"."id";
 SELECT setval('"manage_dev"."users_id_seq"', 12, true);
 
+-- ----------------------------
+-- Indexes structure for table audit_log_checkpoints
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_audit_log_checkpoints_last_entry_hash" ON "manage_dev"."audit_log_checkpoints" USING btree (
+  "last_entry_hash" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table audit_log_checkpoints
+-- ----------------------------
+ALTER TABLE "manage_dev"."audit_log_checkpoints" ADD CONSTRAINT "audit_log_checkpoints_pkey" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Indexes structure for table audit_logs
 -- ----------------------------
@@ -797,6 +1047,9 @@ CREATE INDEX "idx_audit_logs_created_at" ON "manage_dev"."audit_logs" USING btre
 CREATE INDEX "idx_audit_logs_deleted_at" ON "manage_dev"."audit_logs" USING btree (
   "deleted_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST
 );
+CREATE INDEX "idx_audit_logs_request_id" ON "manage_dev"."audit_logs" USING btree (
+  "request_id" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST
+);
 CREATE INDEX "idx_audit_logs_resource" ON "manage_dev"."audit_logs" USING btree (
   "resource" COLLATE "pg_catalog"."default" "pg_catalog"."text_ops" ASC NULLS LAST
 );
@@ -921,6 +1174,19 @@ CREATE UNIQUE INDEX "idx_migration_records_migration_id" ON "manage_dev"."migrat
 -- ----------------------------
 ALTER TABLE "manage_dev"."migration_records" ADD CONSTRAINT "migration_records_pkey" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Indexes structure for table password_histories
+-- ----------------------------
+CREATE INDEX "idx_password_histories_user_created" ON "manage_dev"."password_histories" USING btree (
+  "user_id" "pg_catalog"."int8_ops" ASC NULLS LAST,
+  "created_at" "pg_catalog"."timestamptz_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table password_histories
+-- ----------------------------
+ALTER TABLE "manage_dev"."password_histories" ADD CONSTRAINT "password_histories_pkey" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Indexes structure for table password_reset_tokens
 -- ----------------------------
@@ -1004,6 +1270,11 @@ CREATE INDEX "idx_roles_status" ON "manage_dev"."roles" USING btree (
 -- ----------------------------
 ALTER TABLE "manage_dev"."roles" ADD CONSTRAINT "roles_pkey" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Primary Key structure for table system_settings
+-- ----------------------------
+ALTER TABLE "manage_dev"."system_settings" ADD CONSTRAINT "system_settings_pkey" PRIMARY KEY ("key");
+
 -- ----------------------------
 -- Indexes structure for table user_roles
 -- ----------------------------
@@ -1023,6 +1294,18 @@ CREATE INDEX "idx_user_roles_user_id" ON "manage_dev"."user_roles" USING btree (
 -- ----------------------------
 ALTER TABLE "manage_dev"."user_roles" ADD CONSTRAINT "user_roles_pkey" PRIMARY KEY ("id");
 
+-- ----------------------------
+-- Indexes structure for table user_two_factors
+-- ----------------------------
+CREATE UNIQUE INDEX "idx_user_two_factors_user_id" ON "manage_dev"."user_two_factors" USING btree (
+  "user_id" "pg_catalog"."int8_ops" ASC NULLS LAST
+);
+
+-- ----------------------------
+-- Primary Key structure for table user_two_factors
+-- ----------------------------
+ALTER TABLE "manage_dev"."user_two_factors" ADD CONSTRAINT "user_two_factors_pkey" PRIMARY KEY ("id");
+
 -- ----------------------------
 -- Indexes structure for table users
 -- ----------------------------
@@ -1046,7 +1329,17 @@ ALTER TABLE "manage_dev"."users" ADD CONSTRAINT "users_pkey" PRIMARY KEY ("id");
 -- ----------------------------
 ALTER TABLE "manage_dev"."dict_items" ADD CONSTRAINT "dict_items_dict_type_code_fkey" FOREIGN KEY ("dict_type_code") REFERENCES "manage_dev"."dict_types" ("code") ON DELETE CASCADE ON UPDATE NO ACTION;
 
+-- ----------------------------
+-- Foreign Keys structure for table password_histories
+-- ----------------------------
+ALTER TABLE "manage_dev"."password_histories" ADD CONSTRAINT "fk_password_histories_user" FOREIGN KEY ("user_id") REFERENCES "manage_dev"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
 -- ----------------------------
 -- Foreign Keys structure for table password_reset_tokens
 -- ----------------------------
 ALTER TABLE "manage_dev"."password_reset_tokens" ADD CONSTRAINT "fk_password_reset_tokens_user" FOREIGN KEY ("user_id") REFERENCES "manage_dev"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
+
+-- ----------------------------
+-- Foreign Keys structure for table user_two_factors
+-- ----------------------------
+ALTER TABLE "manage_dev"."user_two_factors" ADD CONSTRAINT "fk_user_two_factors_user" FOREIGN KEY ("user_id") REFERENCES "manage_dev"."users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION;
@@ -114,10 +114,12 @@ apiClient.interceptors.response.use(
             );
 
             if (response.data.data) {
-              const { access_token, expires_in } = response.data.data;
+              const { access_token, refresh_token, expires_in } =
+                response.data.data;
 
-              // 保存新的access token
+              // 保存新的access token和轮换后的refresh token
               localStorage.setItem("access-token", access_token);
+              localStorage.setItem("refresh-token", refresh_token);
 
               // 计算过期时间
               const expiresAt = Date.now() + expires_in * 1000;
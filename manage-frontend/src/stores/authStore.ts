@@ -152,17 +152,19 @@ export const useAuthStore = create<AuthStore>()(
             try {
               const refreshResponse = await authApi.refreshToken(refreshToken);
               if (refreshResponse.data) {
-                const { access_token, expires_in } = refreshResponse.data;
+                const { access_token, refresh_token, expires_in } =
+                  refreshResponse.data;
                 const newTokenExpiresAt = Date.now() + expires_in * 1000;
 
                 // 更新store状态
                 set({
                   accessToken: access_token,
+                  refreshToken: refresh_token,
                   tokenExpiresAt: newTokenExpiresAt,
                 });
 
                 // 更新localStorage
-                setTokens(access_token, refreshToken, expires_in);
+                setTokens(access_token, refresh_token, expires_in);
               }
             } catch (refreshError) {
               // 刷新失败，清除认证状态
@@ -179,11 +181,11 @@ export const useAuthStore = create<AuthStore>()(
             }
           }
 
-          // 同步store状态与localStorage
+          // 同步store状态与localStorage（刷新后令牌可能已轮换，重新读取）
           set({
-            accessToken,
-            refreshToken,
-            tokenExpiresAt,
+            accessToken: getAccessToken(),
+            refreshToken: getRefreshToken(),
+            tokenExpiresAt: getTokenExpiresAt(),
           });
 
           set({ isLoading: true });
@@ -24,6 +24,7 @@ export interface RefreshTokenRequest {
 // 刷新token响应
 export interface RefreshTokenResponse {
   access_token: string;
+  refresh_token: string; // 每次刷新都会轮换，旧的刷新令牌立即失效
   expires_in: number;
   token_type: string;
 }